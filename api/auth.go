@@ -0,0 +1,119 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/loadimpact/k6/api/v1"
+	"github.com/urfave/negroni"
+)
+
+// Role is the level of access an API client has.
+type Role int
+
+const (
+	// RoleNone can only use the ping endpoint.
+	RoleNone Role = iota
+	// RoleRead can make requests that don't change anything, e.g. get the status or the metrics.
+	RoleRead
+	// RoleControl can also change the test, e.g. pause or scale it.
+	RoleControl
+)
+
+// Role returns the role a request is authenticated with. The credential can be sent either as a
+// bearer token or with basic auth, where it's compared as "username:password". If no credentials
+// are configured, all requests have the control role.
+func (o Options) Role(r *http.Request) Role {
+	if o.ControlAuth == "" && o.ReadAuth == "" {
+		return RoleControl
+	}
+
+	var cred string
+	if username, password, ok := r.BasicAuth(); ok {
+		cred = username + ":" + password
+	} else if h := r.Header.Get("Authorization"); strings.HasPrefix(h, "Bearer ") {
+		cred = strings.TrimPrefix(h, "Bearer ")
+	}
+	switch {
+	case cred == "":
+		return RoleNone
+	case o.ControlAuth != "" && subtle.ConstantTimeCompare([]byte(cred), []byte(o.ControlAuth)) == 1:
+		return RoleControl
+	case o.ReadAuth != "" && subtle.ConstantTimeCompare([]byte(cred), []byte(o.ReadAuth)) == 1:
+		return RoleRead
+	default:
+		return RoleNone
+	}
+}
+
+// requiredRole returns the role needed for a request, which depends only on whether it can change
+// anything or not.
+func requiredRole(r *http.Request) Role {
+	if r.URL.Path == "/ping" {
+		return RoleNone
+	}
+	switch r.Method {
+	case "GET", "HEAD", "OPTIONS":
+		return RoleRead
+	default:
+		return RoleControl
+	}
+}
+
+// WithAuth rejects requests that aren't authenticated with the role they need. The errors are in
+// the same format as the ones of the v1 API, so that the client can report them.
+func WithAuth(opts Options) negroni.HandlerFunc {
+	return negroni.HandlerFunc(func(rw http.ResponseWriter, r *http.Request, next http.HandlerFunc) {
+		required := requiredRole(r)
+		if required == RoleNone {
+			next(rw, r)
+			return
+		}
+
+		switch role := opts.Role(r); {
+		case role == RoleNone:
+			// Lets browsers, e.g. for the dashboard, ask for the credentials
+			rw.Header().Set("WWW-Authenticate", `Basic realm="k6"`)
+			authError(rw, "Unauthorized", "Valid credentials are required", http.StatusUnauthorized)
+		case role < required:
+			authError(rw, "Forbidden", "The credentials only allow read-only access", http.StatusForbidden)
+		default:
+			next(rw, r)
+		}
+	})
+}
+
+func authError(rw http.ResponseWriter, title, detail string, status int) {
+	data, err := json.Marshal(v1.ErrorResponse{
+		Errors: []v1.Error{{Status: strconv.Itoa(status), Title: title, Detail: detail}},
+	})
+	if err != nil {
+		panic(err)
+	}
+	rw.Header().Set("Content-Type", "application/json")
+	rw.WriteHeader(status)
+	_, _ = rw.Write(data)
+}
@@ -0,0 +1,114 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/loadimpact/k6/api/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRole(t *testing.T) {
+	opts := Options{ControlAuth: "admin:secret", ReadAuth: "read-token"}
+
+	testdata := map[string]struct {
+		opts   Options
+		header string
+		role   Role
+	}{
+		"no auth configured":  {Options{}, "", RoleControl},
+		"no credentials":      {opts, "", RoleNone},
+		"control basic":       {opts, "Basic YWRtaW46c2VjcmV0", RoleControl},
+		"control bearer":      {opts, "Bearer admin:secret", RoleControl},
+		"read bearer":         {opts, "Bearer read-token", RoleRead},
+		"wrong password":      {opts, "Basic YWRtaW46d3Jvbmc=", RoleNone},
+		"wrong token":         {opts, "Bearer nope", RoleNone},
+		"unknown scheme":      {opts, "Token read-token", RoleNone},
+		"read only":           {Options{ReadAuth: "read-token"}, "Bearer read-token", RoleRead},
+		"read only, no token": {Options{ReadAuth: "read-token"}, "Bearer ", RoleNone},
+	}
+	for name, data := range testdata {
+		t.Run(name, func(t *testing.T) {
+			r := httptest.NewRequest("GET", "/v1/status", nil)
+			if data.header != "" {
+				r.Header.Set("Authorization", data.header)
+			}
+			assert.Equal(t, data.role, data.opts.Role(r))
+		})
+	}
+}
+
+func TestWithAuth(t *testing.T) {
+	opts := Options{ControlAuth: "control-token", ReadAuth: "read-token"}
+
+	testdata := map[string]struct {
+		method, path, token string
+		status              int
+	}{
+		"ping":                {"GET", "/ping", "", http.StatusOK},
+		"unauthenticated":     {"GET", "/v1/status", "", http.StatusUnauthorized},
+		"read":                {"GET", "/v1/status", "read-token", http.StatusOK},
+		"read, patch":         {"PATCH", "/v1/status", "read-token", http.StatusForbidden},
+		"read, teardown":      {"POST", "/v1/teardown", "read-token", http.StatusForbidden},
+		"control":             {"GET", "/v1/status", "control-token", http.StatusOK},
+		"control, patch":      {"PATCH", "/v1/status", "control-token", http.StatusOK},
+		"invalid, patch":      {"PATCH", "/v1/status", "nope", http.StatusUnauthorized},
+		"unauthenticated, ws": {"GET", "/v1/metrics/stream", "", http.StatusUnauthorized},
+	}
+	for name, data := range testdata {
+		t.Run(name, func(t *testing.T) {
+			rw := httptest.NewRecorder()
+			r := httptest.NewRequest(data.method, data.path, nil)
+			if data.token != "" {
+				r.Header.Set("Authorization", "Bearer "+data.token)
+			}
+			WithAuth(opts)(rw, r, testHTTPHandler)
+
+			res := rw.Result()
+			require.Equal(t, data.status, res.StatusCode)
+			if data.status == http.StatusOK {
+				return
+			}
+
+			var errs v1.ErrorResponse
+			require.NoError(t, json.Unmarshal(rw.Body.Bytes(), &errs))
+			require.Len(t, errs.Errors, 1)
+			if data.status == http.StatusUnauthorized {
+				assert.Equal(t, `Basic realm="k6"`, res.Header.Get("WWW-Authenticate"))
+			}
+		})
+	}
+}
+
+func TestOptionsValidate(t *testing.T) {
+	assert.NoError(t, Options{}.Validate())
+	assert.NoError(t, Options{TLSCert: "cert.pem", TLSKey: "key.pem", ControlAuth: "a", ReadAuth: "b"}.Validate())
+	assert.Error(t, Options{TLSCert: "cert.pem"}.Validate())
+	assert.Error(t, Options{TLSKey: "key.pem"}.Validate())
+	assert.Error(t, Options{ControlAuth: "a", ReadAuth: "a"}.Validate())
+	assert.NoError(t, Options{ControlAuth: "a"}.Validate())
+	assert.Error(t, Options{ReadAuth: "b"}.Validate())
+}
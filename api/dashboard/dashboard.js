@@ -0,0 +1,313 @@
+// The k6 live dashboard. It's served by the API server when k6 is started with --dashboard, and
+// only uses the REST API: the metric snapshots and threshold changes come from the
+// /v1/metrics/stream Server-Sent Events, the groups and checks are polled from /v1/groups.
+(function () {
+  "use strict";
+
+  var MAX_POINTS = 600; // 10 minutes with the default stream interval
+  var COLORS = ["#7d64ff", "#1b9e3e", "#ff8c00", "#d7263d", "#2e86de", "#8e8e9e"];
+
+  // A minimal multi-series line chart, drawn on a canvas.
+  function Chart(canvas, series, opts) {
+    this.canvas = canvas;
+    this.series = series;
+    this.opts = opts || {};
+    this.points = []; // [time, value1, value2, ...]
+  }
+
+  Chart.prototype.add = function (t, values) {
+    this.points.push([t].concat(values));
+    if (this.points.length > MAX_POINTS) {
+      this.points.shift();
+    }
+    this.draw();
+  };
+
+  Chart.prototype.draw = function () {
+    var canvas = this.canvas;
+    var ratio = window.devicePixelRatio || 1;
+    var width = canvas.clientWidth, height = canvas.clientHeight;
+    if (canvas.width !== width * ratio || canvas.height !== height * ratio) {
+      canvas.width = width * ratio;
+      canvas.height = height * ratio;
+    }
+    var ctx = canvas.getContext("2d");
+    ctx.setTransform(ratio, 0, 0, ratio, 0, 0);
+    ctx.clearRect(0, 0, width, height);
+
+    var pad = { left: 56, right: 8, top: 8, bottom: 36 };
+    var w = width - pad.left - pad.right, h = height - pad.top - pad.bottom;
+    var points = this.points;
+    if (points.length === 0 || w <= 0 || h <= 0) {
+      return;
+    }
+
+    var minT = points[0][0], maxT = Math.max(points[points.length - 1][0], minT + 1);
+    var maxY = this.opts.max || 0;
+    if (!this.opts.max) {
+      points.forEach(function (p) {
+        for (var i = 1; i < p.length; i++) {
+          if (p[i] > maxY) {
+            maxY = p[i];
+          }
+        }
+      });
+      maxY = niceCeil(maxY);
+    }
+    var x = function (t) { return pad.left + (t - minT) / (maxT - minT) * w; };
+    var y = function (v) { return pad.top + h - v / maxY * h; };
+
+    // Axes and grid
+    ctx.font = "11px sans-serif";
+    ctx.fillStyle = "#8e8e9e";
+    ctx.strokeStyle = "#e6e6ee";
+    ctx.lineWidth = 1;
+    ctx.textAlign = "right";
+    ctx.textBaseline = "middle";
+    for (var i = 0; i <= 4; i++) {
+      var v = maxY * i / 4;
+      ctx.beginPath();
+      ctx.moveTo(pad.left, Math.round(y(v)) + 0.5);
+      ctx.lineTo(pad.left + w, Math.round(y(v)) + 0.5);
+      ctx.stroke();
+      ctx.fillText(formatNumber(v), pad.left - 6, y(v));
+    }
+    ctx.textAlign = "center";
+    ctx.textBaseline = "top";
+    ctx.fillText(formatTime(minT), pad.left, pad.top + h + 4);
+    ctx.fillText(formatTime(maxT), pad.left + w, pad.top + h + 4);
+
+    // Series and legend
+    var legendX = pad.left;
+    this.series.forEach(function (name, s) {
+      ctx.strokeStyle = COLORS[s % COLORS.length];
+      ctx.lineWidth = 2;
+      ctx.beginPath();
+      var started = false;
+      points.forEach(function (p) {
+        var value = p[s + 1];
+        if (value === null || value === undefined || isNaN(value)) {
+          started = false;
+          return;
+        }
+        if (started) {
+          ctx.lineTo(x(p[0]), y(value));
+        } else {
+          ctx.moveTo(x(p[0]), y(value));
+          started = true;
+        }
+      });
+      ctx.stroke();
+
+      ctx.fillStyle = ctx.strokeStyle;
+      ctx.fillRect(legendX, height - 10, 10, 4);
+      ctx.fillStyle = "#3c3c64";
+      ctx.textAlign = "left";
+      ctx.textBaseline = "middle";
+      ctx.fillText(name, legendX + 14, height - 8);
+      legendX += ctx.measureText(name).width + 32;
+    });
+  };
+
+  function niceCeil(v) {
+    if (v <= 0) {
+      return 1;
+    }
+    var magnitude = Math.pow(10, Math.floor(Math.log(v) / Math.LN10));
+    var steps = [1, 2, 2.5, 5, 10];
+    for (var i = 0; i < steps.length; i++) {
+      if (v <= steps[i] * magnitude) {
+        return steps[i] * magnitude;
+      }
+    }
+    return 10 * magnitude;
+  }
+
+  function formatNumber(v) {
+    if (v >= 1000000) {
+      return (v / 1000000).toFixed(1) + "M";
+    }
+    if (v >= 1000) {
+      return (v / 1000).toFixed(1) + "k";
+    }
+    return Math.round(v * 100) / 100 + "";
+  }
+
+  function formatTime(seconds) {
+    seconds = Math.round(seconds);
+    var m = Math.floor(seconds / 60), s = seconds % 60;
+    return m + "m" + (s < 10 ? "0" : "") + s + "s";
+  }
+
+  function element(tag, className, text) {
+    var el = document.createElement(tag);
+    if (className) {
+      el.className = className;
+    }
+    if (text !== undefined) {
+      el.textContent = text;
+    }
+    return el;
+  }
+
+  function fillTable(table, rows, emptyText, columns) {
+    var tbody = table.querySelector("tbody");
+    tbody.innerHTML = "";
+    if (rows.length === 0) {
+      var tr = element("tr", "empty");
+      var td = element("td", "", emptyText);
+      td.colSpan = columns;
+      tr.appendChild(td);
+      tbody.appendChild(tr);
+      return;
+    }
+    rows.forEach(function (row) {
+      tbody.appendChild(row);
+    });
+  }
+
+  // Decodes a JSON:API document of metrics into a map of name => attributes.
+  function decodeMetrics(doc) {
+    var metrics = {};
+    (doc.data || []).forEach(function (item) {
+      metrics[item.id] = item.attributes;
+    });
+    return metrics;
+  }
+
+  var charts = {
+    vus: new Chart(document.getElementById("chart-vus"), ["vus", "vus_max"]),
+    rps: new Chart(document.getElementById("chart-rps"), ["http_reqs/s", "errors/s"]),
+    duration: new Chart(document.getElementById("chart-duration"), ["avg", "med", "p(90)", "p(95)"]),
+    rates: new Chart(document.getElementById("chart-rates"), ["checks"], { max: 100 })
+  };
+
+  var start = Date.now();
+  var lastCounts = {};
+
+  function onMetrics(metrics) {
+    var t = (Date.now() - start) / 1000;
+    var value = function (name, key) {
+      var m = metrics[name];
+      return m && m.sample && m.sample[key] !== undefined ? m.sample[key] : null;
+    };
+
+    charts.vus.add(t, [value("vus", "value"), value("vus_max", "value")]);
+
+    // How much counters grew per second since the previous snapshot. A counter is only there once
+    // it has been incremented, e.g. after the first iteration error, so it's 0 until then.
+    var perSecond = function (name) {
+      var count = value(name, "count") || 0, last = lastCounts[name];
+      lastCounts[name] = { t: t, count: count };
+      return last && t > last.t ? (count - last.count) / (t - last.t) : null;
+    };
+    charts.rps.add(t, [perSecond("http_reqs"), perSecond("errors")]);
+
+    charts.duration.add(t, ["avg", "med", "p(90)", "p(95)"].map(function (key) {
+      return value("http_req_duration", key);
+    }));
+
+    var percent = function (v) { return v === null ? null : v * 100; };
+    charts.rates.add(t, [percent(value("checks", "rate"))]);
+
+    var failed = false;
+    var rows = Object.keys(metrics).sort().filter(function (name) {
+      // Metrics without thresholds are never tainted
+      return metrics[name].tainted !== null;
+    }).map(function (name) {
+      var tainted = metrics[name].tainted;
+      failed = failed || tainted;
+      var tr = element("tr");
+      tr.appendChild(element("td", "", name));
+      tr.appendChild(element("td", tainted ? "fail" : "ok", tainted ? "failing" : "passing"));
+      return tr;
+    });
+    fillTable(document.getElementById("thresholds"), rows, "No thresholds", 2);
+    setStatus(failed ? "some thresholds are failing" : "running", failed);
+  }
+
+  function setStatus(text, failed) {
+    var status = document.getElementById("status");
+    status.textContent = text;
+    status.className = "status" + (failed ? " failed" : "");
+  }
+
+  function addGroupRows(rows, groups, group, depth) {
+    var indent = new Array(depth + 1).join("    ");
+    if (group.attributes.name !== "") {
+      var tr = element("tr", "group");
+      var td = element("td", "", indent + group.attributes.name);
+      td.colSpan = 3;
+      tr.appendChild(td);
+      rows.push(tr);
+      depth++;
+      indent += "    ";
+    }
+    (group.attributes.checks || []).forEach(function (check) {
+      var tr = element("tr");
+      tr.appendChild(element("td", "", indent + "✓ " + check.name));
+      tr.appendChild(element("td", "number ok", check.passes));
+      tr.appendChild(element("td", "number" + (check.fails > 0 ? " fail" : ""), check.fails));
+      rows.push(tr);
+    });
+    var children = group.relationships && group.relationships.groups && group.relationships.groups.data;
+    (children || []).forEach(function (ref) {
+      if (groups[ref.id]) {
+        addGroupRows(rows, groups, groups[ref.id], depth);
+      }
+    });
+  }
+
+  function pollGroups() {
+    var req = new XMLHttpRequest();
+    req.open("GET", "/v1/groups");
+    req.onload = function () {
+      if (req.status !== 200) {
+        return;
+      }
+      var doc = JSON.parse(req.responseText);
+      var groups = {}, root = null;
+      (doc.data || []).forEach(function (g) {
+        groups[g.id] = g;
+        var parent = g.relationships && g.relationships.parent && g.relationships.parent.data;
+        if (!parent) {
+          root = g;
+        }
+      });
+      var rows = [];
+      if (root) {
+        addGroupRows(rows, groups, root, 0);
+      }
+      fillTable(document.getElementById("checks"), rows, "No checks", 3);
+    };
+    req.send();
+  }
+
+  function connect() {
+    var source = new EventSource("/v1/metrics/stream");
+    source.addEventListener("metrics", function (e) {
+      onMetrics(decodeMetrics(JSON.parse(e.data)));
+    });
+    source.addEventListener("threshold", function (e) {
+      var change = JSON.parse(e.data);
+      setStatus(change.metric + " thresholds " + (change.tainted ? "failed" : "recovered") +
+        " at " + change.time, change.tainted);
+    });
+    source.onerror = function () {
+      setStatus("disconnected", false);
+    };
+  }
+
+  setInterval(function () {
+    document.getElementById("time").textContent = formatTime((Date.now() - start) / 1000);
+  }, 1000);
+  setInterval(pollGroups, 5000);
+  window.addEventListener("resize", function () {
+    Object.keys(charts).forEach(function (name) {
+      charts[name].draw();
+    });
+  });
+
+  pollGroups();
+  connect();
+})();
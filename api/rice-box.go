@@ -0,0 +1,55 @@
+package api
+
+import (
+	"github.com/GeertJohan/go.rice/embedded"
+	"time"
+)
+
+func init() {
+
+	// define files
+	file2 := &embedded.EmbeddedFile{
+		Filename:    "dashboard.css",
+		FileModTime: time.Unix(1571400000, 0),
+		Content:     string("body {\n  margin: 0;\n  font-family: -apple-system, \"Segoe UI\", Helvetica, Arial, sans-serif;\n  font-size: 14px;\n  color: #3c3c64;\n  background: #f4f5f7;\n}\n\nheader {\n  display: flex;\n  align-items: baseline;\n  padding: 0 24px;\n  color: #fff;\n  background: #7d64ff;\n}\n\nheader h1 {\n  margin: 12px 24px 12px 0;\n  font-size: 24px;\n}\n\nheader .time {\n  margin-left: auto;\n  font-family: monospace;\n}\n\n.status.failed {\n  font-weight: bold;\n  color: #ffd2d2;\n}\n\nmain {\n  padding: 12px 24px;\n}\n\n.charts {\n  display: grid;\n  grid-template-columns: repeat(auto-fill, minmax(480px, 1fr));\n  grid-gap: 16px;\n}\n\nfigure {\n  margin: 0;\n  padding: 12px;\n  background: #fff;\n  border-radius: 4px;\n}\n\nfigcaption {\n  margin-bottom: 8px;\n  font-weight: bold;\n}\n\ncanvas {\n  width: 100%;\n  height: 200px;\n}\n\n.tables {\n  display: grid;\n  grid-template-columns: 1fr 2fr;\n  grid-gap: 16px;\n  margin-top: 16px;\n}\n\ntable {\n  width: 100%;\n  border-collapse: collapse;\n  background: #fff;\n}\n\nth, td {\n  padding: 6px 12px;\n  text-align: left;\n  border-bottom: 1px solid #e6e6ee;\n}\n\ntd.number {\n  font-family: monospace;\n  text-align: right;\n}\n\ntr.group td {\n  font-weight: bold;\n  background: #fafafc;\n}\n\ntr.empty td {\n  color: #9696aa;\n}\n\n.ok {\n  color: #1b9e3e;\n}\n\n.fail {\n  font-weight: bold;\n  color: #d7263d;\n}\n"),
+	}
+	file3 := &embedded.EmbeddedFile{
+		Filename:    "dashboard.js",
+		FileModTime: time.Unix(1571400000, 0),
+		Content:     string("// The k6 live dashboard. It's served by the API server when k6 is started with --dashboard, and\n// only uses the REST API: the metric snapshots and threshold changes come from the\n// /v1/metrics/stream Server-Sent Events, the groups and checks are polled from /v1/groups.\n(function () {\n  \"use strict\";\n\n  var MAX_POINTS = 600; // 10 minutes with the default stream interval\n  var COLORS = [\"#7d64ff\", \"#1b9e3e\", \"#ff8c00\", \"#d7263d\", \"#2e86de\", \"#8e8e9e\"];\n\n  // A minimal multi-series line chart, drawn on a canvas.\n  function Chart(canvas, series, opts) {\n    this.canvas = canvas;\n    this.series = series;\n    this.opts = opts || {};\n    this.points = []; // [time, value1, value2, ...]\n  }\n\n  Chart.prototype.add = function (t, values) {\n    this.points.push([t].concat(values));\n    if (this.points.length > MAX_POINTS) {\n      this.points.shift();\n    }\n    this.draw();\n  };\n\n  Chart.prototype.draw = function () {\n    var canvas = this.canvas;\n    var ratio = window.devicePixelRatio || 1;\n    var width = canvas.clientWidth, height = canvas.clientHeight;\n    if (canvas.width !== width * ratio || canvas.height !== height * ratio) {\n      canvas.width = width * ratio;\n      canvas.height = height * ratio;\n    }\n    var ctx = canvas.getContext(\"2d\");\n    ctx.setTransform(ratio, 0, 0, ratio, 0, 0);\n    ctx.clearRect(0, 0, width, height);\n\n    var pad = { left: 56, right: 8, top: 8, bottom: 36 };\n    var w = width - pad.left - pad.right, h = height - pad.top - pad.bottom;\n    var points = this.points;\n    if (points.length === 0 || w <= 0 || h <= 0) {\n      return;\n    }\n\n    var minT = points[0][0], maxT = Math.max(points[points.length - 1][0], minT + 1);\n    var maxY = this.opts.max || 0;\n    if (!this.opts.max) {\n      points.forEach(function (p) {\n        for (var i = 1; i < p.length; i++) {\n          if (p[i] > maxY) {\n            maxY = p[i];\n          }\n        }\n      });\n      maxY = niceCeil(maxY);\n    }\n    var x = function (t) { return pad.left + (t - minT) / (maxT - minT) * w; };\n    var y = function (v) { return pad.top + h - v / maxY * h; };\n\n    // Axes and grid\n    ctx.font = \"11px sans-serif\";\n    ctx.fillStyle = \"#8e8e9e\";\n    ctx.strokeStyle = \"#e6e6ee\";\n    ctx.lineWidth = 1;\n    ctx.textAlign = \"right\";\n    ctx.textBaseline = \"middle\";\n    for (var i = 0; i <= 4; i++) {\n      var v = maxY * i / 4;\n      ctx.beginPath();\n      ctx.moveTo(pad.left, Math.round(y(v)) + 0.5);\n      ctx.lineTo(pad.left + w, Math.round(y(v)) + 0.5);\n      ctx.stroke();\n      ctx.fillText(formatNumber(v), pad.left - 6, y(v));\n    }\n    ctx.textAlign = \"center\";\n    ctx.textBaseline = \"top\";\n    ctx.fillText(formatTime(minT), pad.left, pad.top + h + 4);\n    ctx.fillText(formatTime(maxT), pad.left + w, pad.top + h + 4);\n\n    // Series and legend\n    var legendX = pad.left;\n    this.series.forEach(function (name, s) {\n      ctx.strokeStyle = COLORS[s % COLORS.length];\n      ctx.lineWidth = 2;\n      ctx.beginPath();\n      var started = false;\n      points.forEach(function (p) {\n        var value = p[s + 1];\n        if (value === null || value === undefined || isNaN(value)) {\n          started = false;\n          return;\n        }\n        if (started) {\n          ctx.lineTo(x(p[0]), y(value));\n        } else {\n          ctx.moveTo(x(p[0]), y(value));\n          started = true;\n        }\n      });\n      ctx.stroke();\n\n      ctx.fillStyle = ctx.strokeStyle;\n      ctx.fillRect(legendX, height - 10, 10, 4);\n      ctx.fillStyle = \"#3c3c64\";\n      ctx.textAlign = \"left\";\n      ctx.textBaseline = \"middle\";\n      ctx.fillText(name, legendX + 14, height - 8);\n      legendX += ctx.measureText(name).width + 32;\n    });\n  };\n\n  function niceCeil(v) {\n    if (v <= 0) {\n      return 1;\n    }\n    var magnitude = Math.pow(10, Math.floor(Math.log(v) / Math.LN10));\n    var steps = [1, 2, 2.5, 5, 10];\n    for (var i = 0; i < steps.length; i++) {\n      if (v <= steps[i] * magnitude) {\n        return steps[i] * magnitude;\n      }\n    }\n    return 10 * magnitude;\n  }\n\n  function formatNumber(v) {\n    if (v >= 1000000) {\n      return (v / 1000000).toFixed(1) + \"M\";\n    }\n    if (v >= 1000) {\n      return (v / 1000).toFixed(1) + \"k\";\n    }\n    return Math.round(v * 100) / 100 + \"\";\n  }\n\n  function formatTime(seconds) {\n    seconds = Math.round(seconds);\n    var m = Math.floor(seconds / 60), s = seconds % 60;\n    return m + \"m\" + (s < 10 ? \"0\" : \"\") + s + \"s\";\n  }\n\n  function element(tag, className, text) {\n    var el = document.createElement(tag);\n    if (className) {\n      el.className = className;\n    }\n    if (text !== undefined) {\n      el.textContent = text;\n    }\n    return el;\n  }\n\n  function fillTable(table, rows, emptyText, columns) {\n    var tbody = table.querySelector(\"tbody\");\n    tbody.innerHTML = \"\";\n    if (rows.length === 0) {\n      var tr = element(\"tr\", \"empty\");\n      var td = element(\"td\", \"\", emptyText);\n      td.colSpan = columns;\n      tr.appendChild(td);\n      tbody.appendChild(tr);\n      return;\n    }\n    rows.forEach(function (row) {\n      tbody.appendChild(row);\n    });\n  }\n\n  // Decodes a JSON:API document of metrics into a map of name => attributes.\n  function decodeMetrics(doc) {\n    var metrics = {};\n    (doc.data || []).forEach(function (item) {\n      metrics[item.id] = item.attributes;\n    });\n    return metrics;\n  }\n\n  var charts = {\n    vus: new Chart(document.getElementById(\"chart-vus\"), [\"vus\", \"vus_max\"]),\n    rps: new Chart(document.getElementById(\"chart-rps\"), [\"http_reqs/s\", \"errors/s\"]),\n    duration: new Chart(document.getElementById(\"chart-duration\"), [\"avg\", \"med\", \"p(90)\", \"p(95)\"]),\n    rates: new Chart(document.getElementById(\"chart-rates\"), [\"checks\"], { max: 100 })\n  };\n\n  var start = Date.now();\n  var lastCounts = {};\n\n  function onMetrics(metrics) {\n    var t = (Date.now() - start) / 1000;\n    var value = function (name, key) {\n      var m = metrics[name];\n      return m && m.sample && m.sample[key] !== undefined ? m.sample[key] : null;\n    };\n\n    charts.vus.add(t, [value(\"vus\", \"value\"), value(\"vus_max\", \"value\")]);\n\n    // How much counters grew per second since the previous snapshot. A counter is only there once\n    // it has been incremented, e.g. after the first iteration error, so it's 0 until then.\n    var perSecond = function (name) {\n      var count = value(name, \"count\") || 0, last = lastCounts[name];\n      lastCounts[name] = { t: t, count: count };\n      return last && t > last.t ? (count - last.count) / (t - last.t) : null;\n    };\n    charts.rps.add(t, [perSecond(\"http_reqs\"), perSecond(\"errors\")]);\n\n    charts.duration.add(t, [\"avg\", \"med\", \"p(90)\", \"p(95)\"].map(function (key) {\n      return value(\"http_req_duration\", key);\n    }));\n\n    var percent = function (v) { return v === null ? null : v * 100; };\n    charts.rates.add(t, [percent(value(\"checks\", \"rate\"))]);\n\n    var failed = false;\n    var rows = Object.keys(metrics).sort().filter(function (name) {\n      // Metrics without thresholds are never tainted\n      return metrics[name].tainted !== null;\n    }).map(function (name) {\n      var tainted = metrics[name].tainted;\n      failed = failed || tainted;\n      var tr = element(\"tr\");\n      tr.appendChild(element(\"td\", \"\", name));\n      tr.appendChild(element(\"td\", tainted ? \"fail\" : \"ok\", tainted ? \"failing\" : \"passing\"));\n      return tr;\n    });\n    fillTable(document.getElementById(\"thresholds\"), rows, \"No thresholds\", 2);\n    setStatus(failed ? \"some thresholds are failing\" : \"running\", failed);\n  }\n\n  function setStatus(text, failed) {\n    var status = document.getElementById(\"status\");\n    status.textContent = text;\n    status.className = \"status\" + (failed ? \" failed\" : \"\");\n  }\n\n  function addGroupRows(rows, groups, group, depth) {\n    var indent = new Array(depth + 1).join(\"\u00a0\u00a0\u00a0\u00a0\");\n    if (group.attributes.name !== \"\") {\n      var tr = element(\"tr\", \"group\");\n      var td = element(\"td\", \"\", indent + group.attributes.name);\n      td.colSpan = 3;\n      tr.appendChild(td);\n      rows.push(tr);\n      depth++;\n      indent += \"\u00a0\u00a0\u00a0\u00a0\";\n    }\n    (group.attributes.checks || []).forEach(function (check) {\n      var tr = element(\"tr\");\n      tr.appendChild(element(\"td\", \"\", indent + \"✓ \" + check.name));\n      tr.appendChild(element(\"td\", \"number ok\", check.passes));\n      tr.appendChild(element(\"td\", \"number\" + (check.fails > 0 ? \" fail\" : \"\"), check.fails));\n      rows.push(tr);\n    });\n    var children = group.relationships && group.relationships.groups && group.relationships.groups.data;\n    (children || []).forEach(function (ref) {\n      if (groups[ref.id]) {\n        addGroupRows(rows, groups, groups[ref.id], depth);\n      }\n    });\n  }\n\n  function pollGroups() {\n    var req = new XMLHttpRequest();\n    req.open(\"GET\", \"/v1/groups\");\n    req.onload = function () {\n      if (req.status !== 200) {\n        return;\n      }\n      var doc = JSON.parse(req.responseText);\n      var groups = {}, root = null;\n      (doc.data || []).forEach(function (g) {\n        groups[g.id] = g;\n        var parent = g.relationships && g.relationships.parent && g.relationships.parent.data;\n        if (!parent) {\n          root = g;\n        }\n      });\n      var rows = [];\n      if (root) {\n        addGroupRows(rows, groups, root, 0);\n      }\n      fillTable(document.getElementById(\"checks\"), rows, \"No checks\", 3);\n    };\n    req.send();\n  }\n\n  function connect() {\n    var source = new EventSource(\"/v1/metrics/stream\");\n    source.addEventListener(\"metrics\", function (e) {\n      onMetrics(decodeMetrics(JSON.parse(e.data)));\n    });\n    source.addEventListener(\"threshold\", function (e) {\n      var change = JSON.parse(e.data);\n      setStatus(change.metric + \" thresholds \" + (change.tainted ? \"failed\" : \"recovered\") +\n        \" at \" + change.time, change.tainted);\n    });\n    source.onerror = function () {\n      setStatus(\"disconnected\", false);\n    };\n  }\n\n  setInterval(function () {\n    document.getElementById(\"time\").textContent = formatTime((Date.now() - start) / 1000);\n  }, 1000);\n  setInterval(pollGroups, 5000);\n  window.addEventListener(\"resize\", function () {\n    Object.keys(charts).forEach(function (name) {\n      charts[name].draw();\n    });\n  });\n\n  pollGroups();\n  connect();\n})();\n"),
+	}
+	file4 := &embedded.EmbeddedFile{
+		Filename:    "index.html",
+		FileModTime: time.Unix(1571400000, 0),
+		Content:     string("<!DOCTYPE html>\n<html lang=\"en\">\n<head>\n  <meta charset=\"utf-8\">\n  <title>k6 dashboard</title>\n  <link rel=\"stylesheet\" href=\"dashboard.css\">\n</head>\n<body>\n  <header>\n    <h1>k6</h1>\n    <span id=\"status\" class=\"status\">connecting...</span>\n    <span id=\"time\" class=\"time\"></span>\n  </header>\n\n  <main>\n    <section class=\"charts\">\n      <figure>\n        <figcaption>Virtual users</figcaption>\n        <canvas id=\"chart-vus\"></canvas>\n      </figure>\n      <figure>\n        <figcaption>Requests and iteration errors per second</figcaption>\n        <canvas id=\"chart-rps\"></canvas>\n      </figure>\n      <figure>\n        <figcaption>Request duration (ms, since the start of the test)</figcaption>\n        <canvas id=\"chart-duration\"></canvas>\n      </figure>\n      <figure>\n        <figcaption>Check pass rate (%)</figcaption>\n        <canvas id=\"chart-rates\"></canvas>\n      </figure>\n    </section>\n\n    <section class=\"tables\">\n      <div>\n        <h2>Thresholds</h2>\n        <table id=\"thresholds\">\n          <thead><tr><th>Metric</th><th>Status</th></tr></thead>\n          <tbody><tr class=\"empty\"><td colspan=\"2\">No thresholds</td></tr></tbody>\n        </table>\n      </div>\n      <div>\n        <h2>Groups and checks</h2>\n        <table id=\"checks\">\n          <thead><tr><th>Group / check</th><th>Passes</th><th>Fails</th></tr></thead>\n          <tbody><tr class=\"empty\"><td colspan=\"3\">No checks</td></tr></tbody>\n        </table>\n      </div>\n    </section>\n  </main>\n\n  <script src=\"dashboard.js\"></script>\n</body>\n</html>\n"),
+	}
+
+	// define dirs
+	dir1 := &embedded.EmbeddedDir{
+		Filename:   "",
+		DirModTime: time.Unix(1571400000, 0),
+		ChildFiles: []*embedded.EmbeddedFile{
+			file2, // "dashboard.css"
+			file3, // "dashboard.js"
+			file4, // "index.html"
+
+		},
+	}
+
+	// link ChildDirs
+	dir1.ChildDirs = []*embedded.EmbeddedDir{}
+
+	// register embeddedBox
+	embedded.RegisterEmbeddedBox(`dashboard`, &embedded.EmbeddedBox{
+		Name: `dashboard`,
+		Time: time.Unix(1571400000, 0),
+		Dirs: map[string]*embedded.EmbeddedDir{
+			"": dir1,
+		},
+		Files: map[string]*embedded.EmbeddedFile{
+			"dashboard.css": file2,
+			"dashboard.js":  file3,
+			"index.html":    file4,
+		},
+	})
+}
@@ -18,36 +18,78 @@
  *
  */
 
+//go:generate rice embed-go
+
 package api
 
 import (
 	"fmt"
 	"net/http"
 
+	"github.com/GeertJohan/go.rice"
 	"github.com/loadimpact/k6/api/common"
 	"github.com/loadimpact/k6/api/v1"
 	"github.com/loadimpact/k6/core"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"github.com/urfave/negroni"
 )
 
-func NewHandler() http.Handler {
+// Options are the optional features of the API server.
+type Options struct {
+	// Dashboard serves the live dashboard at /dashboard/.
+	Dashboard bool
+
+	// ControlAuth and ReadAuth are the credentials for the control and the read-only roles,
+	// either bearer tokens or "username:password" pairs for basic auth. If neither is set,
+	// no authentication is required, and ReadAuth can't be set without ControlAuth.
+	ControlAuth string
+	ReadAuth    string
+
+	// TLSCert and TLSKey are the certificate and private key files to serve HTTPS with.
+	TLSCert string
+	TLSKey  string
+}
+
+// Validate checks that the options can be used together.
+func (o Options) Validate() error {
+	if (o.TLSCert == "") != (o.TLSKey == "") {
+		return errors.New("both a TLS certificate and a key are needed to serve the API with TLS")
+	}
+	if o.ControlAuth == "" && o.ReadAuth != "" {
+		// Nothing could be granted the control role, so the test couldn't be controlled at all
+		return errors.New("read-only API credentials need control credentials (--api-auth) too")
+	}
+	if o.ControlAuth != "" && o.ControlAuth == o.ReadAuth {
+		return errors.New("the control and read-only API credentials must be different")
+	}
+	return nil
+}
+
+func NewHandler(opts Options) http.Handler {
 	mux := http.NewServeMux()
 	mux.Handle("/v1/", v1.NewHandler())
 	mux.Handle("/ping", HandlePing())
+	if opts.Dashboard {
+		mux.Handle("/dashboard/", http.StripPrefix("/dashboard", HandleDashboard()))
+	}
 	mux.Handle("/", HandlePing())
 	return mux
 }
 
-func ListenAndServe(addr string, engine *core.Engine) error {
-	mux := NewHandler()
+func ListenAndServe(addr string, engine *core.Engine, opts Options) error {
+	mux := NewHandler(opts)
 
 	n := negroni.New()
 	n.Use(negroni.NewRecovery())
 	n.UseFunc(WithEngine(engine))
 	n.UseFunc(NewLogger(log.StandardLogger()))
+	n.UseFunc(WithAuth(opts))
 	n.UseHandler(mux)
 
+	if opts.TLSCert != "" {
+		return http.ListenAndServeTLS(addr, opts.TLSCert, opts.TLSKey, n)
+	}
 	return http.ListenAndServe(addr, n)
 }
 
@@ -75,3 +117,12 @@ func HandlePing() http.Handler {
 		}
 	})
 }
+
+// HandleDashboard serves the dashboard page and its assets, which are embedded from the dashboard
+// directory with `go generate`.
+func HandleDashboard() http.Handler {
+	conf := rice.Config{
+		LocateOrder: []rice.LocateMethod{rice.LocateEmbedded},
+	}
+	return http.FileServer(conf.MustFindBox("dashboard").HTTPBox())
+}
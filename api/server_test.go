@@ -86,7 +86,7 @@ func TestWithEngine(t *testing.T) {
 }
 
 func TestPing(t *testing.T) {
-	mux := NewHandler()
+	mux := NewHandler(Options{})
 
 	rw := httptest.NewRecorder()
 	r := httptest.NewRequest("GET", "/ping", nil)
@@ -96,3 +96,31 @@ func TestPing(t *testing.T) {
 	assert.Equal(t, http.StatusOK, res.StatusCode)
 	assert.Equal(t, []byte{'o', 'k'}, rw.Body.Bytes())
 }
+
+func TestDashboard(t *testing.T) {
+	testdata := map[string]struct {
+		path        string
+		contentType string
+		contains    string
+	}{
+		"index": {"/dashboard/", "text/html", "<title>k6 dashboard</title>"},
+		"js":    {"/dashboard/dashboard.js", "javascript", "/v1/metrics/stream"},
+		"css":   {"/dashboard/dashboard.css", "text/css", "canvas"},
+	}
+	for name, data := range testdata {
+		t.Run(name, func(t *testing.T) {
+			rw := httptest.NewRecorder()
+			NewHandler(Options{Dashboard: true}).ServeHTTP(rw, httptest.NewRequest("GET", data.path, nil))
+			res := rw.Result()
+			assert.Equal(t, http.StatusOK, res.StatusCode)
+			assert.Contains(t, res.Header.Get("Content-Type"), data.contentType)
+			assert.Contains(t, rw.Body.String(), data.contains)
+		})
+	}
+
+	t.Run("disabled", func(t *testing.T) {
+		rw := httptest.NewRecorder()
+		NewHandler(Options{}).ServeHTTP(rw, httptest.NewRequest("GET", "/dashboard/", nil))
+		assert.Equal(t, "ok", rw.Body.String())
+	})
+}
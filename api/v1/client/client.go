@@ -28,6 +28,7 @@ import (
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"strings"
 
 	"github.com/manyminds/api2go/jsonapi"
 
@@ -36,10 +37,22 @@ import (
 
 type Client struct {
 	BaseURL *url.URL
+
+	// Auth is sent with every request, with basic auth if it's a "username:password" pair and as
+	// a bearer token otherwise.
+	Auth string
+
+	// HTTPClient is used to make the requests, http.DefaultClient if it's nil.
+	HTTPClient *http.Client
 }
 
+// New returns a client for the API server at base, which is either a host:port address or a URL
+// with an http or https scheme.
 func New(base string) (*Client, error) {
-	baseURL, err := url.Parse("http://" + base)
+	if !strings.Contains(base, "://") {
+		base = "http://" + base
+	}
+	baseURL, err := url.Parse(base)
 	if err != nil {
 		return nil, err
 	}
@@ -62,8 +75,20 @@ func (c *Client) call(ctx context.Context, method string, rel *url.URL, body, ou
 		Body:   bodyReader,
 	}
 	req = req.WithContext(ctx)
+	if c.Auth != "" {
+		req.Header = make(http.Header)
+		if kv := strings.SplitN(c.Auth, ":", 2); len(kv) == 2 {
+			req.SetBasicAuth(kv[0], kv[1])
+		} else {
+			req.Header.Set("Authorization", "Bearer "+c.Auth)
+		}
+	}
 
-	res, err := http.DefaultClient.Do(req)
+	httpClient := c.HTTPClient
+	if httpClient == nil {
+		httpClient = http.DefaultClient
+	}
+	res, err := httpClient.Do(req)
 	if err != nil {
 		return err
 	}
@@ -82,5 +107,8 @@ func (c *Client) call(ctx context.Context, method string, rel *url.URL, body, ou
 		return errs.Errors[0]
 	}
 
+	if out == nil || res.StatusCode == http.StatusNoContent {
+		return nil
+	}
 	return jsonapi.Unmarshal(data, out)
 }
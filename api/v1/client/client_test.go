@@ -0,0 +1,77 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/loadimpact/k6/api/v1"
+	"github.com/manyminds/api2go/jsonapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v3"
+)
+
+func TestNew(t *testing.T) {
+	testdata := map[string]string{
+		"localhost:6565":         "http://localhost:6565",
+		"http://localhost:6565":  "http://localhost:6565",
+		"https://localhost:6565": "https://localhost:6565",
+	}
+	for base, expected := range testdata {
+		c, err := New(base)
+		require.NoError(t, err)
+		assert.Equal(t, expected, c.BaseURL.String())
+	}
+}
+
+func TestClientAuth(t *testing.T) {
+	var header http.Header
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		data, err := jsonapi.Marshal(v1.Status{Paused: null.BoolFrom(true)})
+		require.NoError(t, err)
+		_, _ = rw.Write(data)
+	}))
+	defer srv.Close()
+
+	testdata := map[string]string{
+		"":            "",
+		"token":       "Bearer token",
+		"user:secret": "Basic dXNlcjpzZWNyZXQ=",
+	}
+	for auth, expected := range testdata {
+		t.Run(auth, func(t *testing.T) {
+			c, err := New(srv.URL)
+			require.NoError(t, err)
+			c.Auth = auth
+			c.HTTPClient = srv.Client()
+
+			status, err := c.Status(context.Background())
+			require.NoError(t, err)
+			assert.True(t, status.Paused.Bool)
+			assert.Equal(t, expected, header.Get("Authorization"))
+		})
+	}
+}
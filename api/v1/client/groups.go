@@ -0,0 +1,35 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package client
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/loadimpact/k6/api/v1"
+)
+
+var GroupsURL = &url.URL{Path: "/v1/groups"}
+
+// Groups returns all groups of the test, the root group first and every other group after its parent.
+func (c *Client) Groups(ctx context.Context) (ret []v1.Group, err error) {
+	return ret, c.call(ctx, "GET", GroupsURL, nil, &ret)
+}
@@ -0,0 +1,42 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package client
+
+import (
+	"context"
+	"net/url"
+
+	"github.com/loadimpact/k6/api/v1"
+)
+
+var ThresholdsURL = &url.URL{Path: "/v1/thresholds"}
+
+func (c *Client) Thresholds(ctx context.Context) (ret []v1.Thresholds, err error) {
+	return ret, c.call(ctx, "GET", ThresholdsURL, nil, &ret)
+}
+
+func (c *Client) SetThresholds(ctx context.Context, ths v1.Thresholds) (ret v1.Thresholds, err error) {
+	return ret, c.call(ctx, "POST", ThresholdsURL, ths, &ret)
+}
+
+func (c *Client) DeleteThresholds(ctx context.Context, metric string) error {
+	return c.call(ctx, "DELETE", &url.URL{Path: "/v1/thresholds/" + metric}, nil, nil)
+}
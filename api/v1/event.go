@@ -0,0 +1,62 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package v1
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/loadimpact/k6/stats"
+)
+
+// Event is an annotation of the test run, recorded either by the script or through the API.
+type Event struct {
+	// The position of the event in the list of all events, starting from 0.
+	ID string `json:"-" yaml:"id"`
+
+	// Defaults to the current time if it's not set when recording an event.
+	Time    time.Time         `json:"time" yaml:"time"`
+	Message string            `json:"message" yaml:"message"`
+	Tags    map[string]string `json:"tags" yaml:"tags"`
+}
+
+// NewEvent converts the event at index i of the engine's events.
+func NewEvent(i int, event stats.Event) Event {
+	return Event{
+		ID:      strconv.Itoa(i),
+		Time:    event.Time,
+		Message: event.Message,
+		Tags:    event.Tags.CloneTags(),
+	}
+}
+
+func (e Event) GetName() string {
+	return "events"
+}
+
+func (e Event) GetID() string {
+	return e.ID
+}
+
+func (e *Event) SetID(id string) error {
+	e.ID = id
+	return nil
+}
@@ -0,0 +1,89 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package v1
+
+import (
+	"io/ioutil"
+	"net/http"
+	"time"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/loadimpact/k6/api/common"
+	"github.com/loadimpact/k6/stats"
+	"github.com/manyminds/api2go/jsonapi"
+)
+
+func HandleGetEvents(rw http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	engine := common.GetEngine(r.Context())
+
+	all := engine.GetEvents()
+	events := make([]Event, len(all))
+	for i, event := range all {
+		events[i] = NewEvent(i, event)
+	}
+
+	data, err := jsonapi.Marshal(events)
+	if err != nil {
+		apiError(rw, "Encoding error", err.Error(), http.StatusInternalServerError)
+		return
+	}
+	_, _ = rw.Write(data)
+}
+
+// HandlePostEvents records an event, tagged with the test's run tags and any tags it has.
+func HandlePostEvents(rw http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	engine := common.GetEngine(r.Context())
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		apiError(rw, "Couldn't read request", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var event Event
+	if err := jsonapi.Unmarshal(body, &event); err != nil {
+		apiError(rw, "Invalid data", err.Error(), http.StatusBadRequest)
+		return
+	}
+	if event.Message == "" {
+		apiError(rw, "Invalid data", "An event needs a message", http.StatusBadRequest)
+		return
+	}
+	if event.Time.IsZero() {
+		event.Time = time.Now()
+	}
+
+	tags := engine.Options.RunTags.CloneTags()
+	for k, v := range event.Tags {
+		tags[k] = v
+	}
+	engine.AddEvent(stats.Event{Time: event.Time, Message: event.Message, Tags: stats.IntoSampleTags(&tags)})
+
+	event.ID = ""
+	event.Tags = tags
+	data, err := jsonapi.Marshal(event)
+	if err != nil {
+		apiError(rw, "Encoding error", err.Error(), http.StatusInternalServerError)
+		return
+	}
+	rw.WriteHeader(http.StatusCreated)
+	_, _ = rw.Write(data)
+}
@@ -0,0 +1,92 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package v1
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/loadimpact/k6/core"
+	"github.com/loadimpact/k6/lib"
+	"github.com/loadimpact/k6/stats"
+	"github.com/manyminds/api2go/jsonapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetEvents(t *testing.T) {
+	engine, err := core.NewEngine(nil, lib.Options{})
+	require.NoError(t, err)
+	now := time.Now()
+	engine.AddEvent(stats.Event{Time: now, Message: "deployed v2"})
+	engine.AddEvent(stats.Event{Time: now.Add(time.Second), Message: "rolled back"})
+
+	rw := httptest.NewRecorder()
+	NewHandler().ServeHTTP(rw, newRequestWithEngine(engine, "GET", "/v1/events", nil))
+	res := rw.Result()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	var events []Event
+	require.NoError(t, jsonapi.Unmarshal(rw.Body.Bytes(), &events))
+	require.Len(t, events, 2)
+	assert.Equal(t, "0", events[0].ID)
+	assert.Equal(t, "deployed v2", events[0].Message)
+	assert.True(t, now.Equal(events[0].Time))
+	assert.Equal(t, "1", events[1].ID)
+	assert.Equal(t, "rolled back", events[1].Message)
+}
+
+func TestPostEvents(t *testing.T) {
+	runTags := map[string]string{"testid": "123"}
+	engine, err := core.NewEngine(nil, lib.Options{RunTags: stats.IntoSampleTags(&runTags)})
+	require.NoError(t, err)
+
+	body, err := jsonapi.Marshal(Event{Message: "fault injected", Tags: map[string]string{"fault": "latency"}})
+	require.NoError(t, err)
+
+	rw := httptest.NewRecorder()
+	NewHandler().ServeHTTP(rw, newRequestWithEngine(engine, "POST", "/v1/events", bytes.NewReader(body)))
+	res := rw.Result()
+	require.Equal(t, http.StatusCreated, res.StatusCode)
+
+	var event Event
+	require.NoError(t, jsonapi.Unmarshal(rw.Body.Bytes(), &event))
+	assert.Equal(t, "fault injected", event.Message)
+	assert.False(t, event.Time.IsZero())
+
+	events := engine.GetEvents()
+	require.Len(t, events, 1)
+	assert.Equal(t, "fault injected", events[0].Message)
+	assert.Equal(t, map[string]string{"testid": "123", "fault": "latency"}, events[0].Tags.CloneTags())
+
+	t.Run("no message", func(t *testing.T) {
+		body, err := jsonapi.Marshal(Event{})
+		require.NoError(t, err)
+
+		rw := httptest.NewRecorder()
+		NewHandler().ServeHTTP(rw, newRequestWithEngine(engine, "POST", "/v1/events", bytes.NewReader(body)))
+		assert.Equal(t, http.StatusBadRequest, rw.Result().StatusCode)
+		assert.Len(t, engine.GetEvents(), 1)
+	})
+}
@@ -21,6 +21,9 @@
 package v1
 
 import (
+	"sync/atomic"
+
+	"github.com/loadimpact/k6/core"
 	"github.com/loadimpact/k6/lib"
 	"github.com/manyminds/api2go/jsonapi"
 	"github.com/pkg/errors"
@@ -39,8 +42,8 @@ func NewCheck(c *lib.Check) Check {
 		ID:     c.ID,
 		Path:   c.Path,
 		Name:   c.Name,
-		Passes: c.Passes,
-		Fails:  c.Fails,
+		Passes: atomic.LoadInt64(&c.Passes),
+		Fails:  atomic.LoadInt64(&c.Fails),
 	}
 }
 
@@ -50,6 +53,11 @@ type Group struct {
 	Name   string  `json:"name" yaml:"name"`
 	Checks []Check `json:"checks" yaml:"checks"`
 
+	// Live stats, see SetStats().
+	Duration map[string]float64 `json:"duration" yaml:"duration"`
+	Runs     uint64             `json:"runs" yaml:"runs"`
+	HTTPReqs int64              `json:"http_reqs" yaml:"http-reqs"`
+
 	Parent   *Group   `json:"-" yaml:"-"`
 	ParentID string   `json:"-" yaml:"parent-id"`
 	Groups   []*Group `json:"-" yaml:"-"`
@@ -82,6 +90,19 @@ func NewGroup(g *lib.Group, parent *Group) *Group {
 	return group
 }
 
+// SetStats fills in the live stats of the group and all of its subgroups. A group's HTTP requests
+// include the ones made in its subgroups, while the durations are null until it has been run.
+func (g *Group) SetStats(groupStats map[string]core.GroupStats) {
+	gs := groupStats[g.Path]
+	g.Duration = gs.Duration
+	g.Runs = gs.Count
+	g.HTTPReqs = gs.HTTPReqs
+	for _, gp := range g.Groups {
+		gp.SetStats(groupStats)
+		g.HTTPReqs += gp.HTTPReqs
+	}
+}
+
 func (g Group) GetID() string {
 	return g.ID
 }
@@ -32,6 +32,7 @@ func HandleGetGroups(rw http.ResponseWriter, r *http.Request, p httprouter.Param
 	engine := common.GetEngine(r.Context())
 
 	root := NewGroup(engine.Executor.GetRunner().GetDefaultGroup(), nil)
+	root.SetStats(engine.GetGroupStats())
 	groups := FlattenGroup(root)
 
 	data, err := jsonapi.Marshal(groups)
@@ -48,6 +49,7 @@ func HandleGetGroup(rw http.ResponseWriter, r *http.Request, p httprouter.Params
 	engine := common.GetEngine(r.Context())
 
 	root := NewGroup(engine.Executor.GetRunner().GetDefaultGroup(), nil)
+	root.SetStats(engine.GetGroupStats())
 	groups := FlattenGroup(root)
 
 	var group *Group
@@ -58,6 +58,12 @@ func TestGetGroups(t *testing.T) {
 			assert.Nil(t, doc.Data.DataObject)
 			if assert.NotEmpty(t, doc.Data.DataArray) {
 				assert.Equal(t, "groups", doc.Data.DataArray[0].Type)
+
+				var attrs map[string]json.RawMessage
+				assert.NoError(t, json.Unmarshal(doc.Data.DataArray[0].Attributes, &attrs))
+				assert.Contains(t, attrs, "duration")
+				assert.Contains(t, attrs, "runs")
+				assert.Contains(t, attrs, "http_reqs")
 			}
 		})
 
@@ -23,6 +23,7 @@ package v1
 import (
 	"testing"
 
+	"github.com/loadimpact/k6/core"
 	"github.com/loadimpact/k6/lib"
 	"github.com/stretchr/testify/assert"
 )
@@ -93,6 +94,34 @@ func TestNewGroup(t *testing.T) {
 	})
 }
 
+func TestGroupSetStats(t *testing.T) {
+	root, _ := lib.NewGroup("", nil)
+	login, _ := root.Group("login")
+	form, _ := login.Group("form")
+	browse, _ := root.Group("browse")
+
+	g := NewGroup(root, nil)
+	g.SetStats(map[string]core.GroupStats{
+		root.Path:  {HTTPReqs: 1},
+		login.Path: {Duration: map[string]float64{"avg": 250}, Count: 2, HTTPReqs: 2},
+		form.Path:  {Duration: map[string]float64{"avg": 100}, Count: 2, HTTPReqs: 4},
+	})
+
+	assert.Equal(t, int64(7), g.HTTPReqs)
+	assert.Nil(t, g.Duration)
+
+	groups := map[string]*Group{}
+	for _, gp := range FlattenGroup(g) {
+		groups[gp.Path] = gp
+	}
+	assert.Equal(t, int64(6), groups[login.Path].HTTPReqs)
+	assert.Equal(t, uint64(2), groups[login.Path].Runs)
+	assert.Equal(t, map[string]float64{"avg": 250}, groups[login.Path].Duration)
+	assert.Equal(t, int64(4), groups[form.Path].HTTPReqs)
+	assert.Equal(t, int64(0), groups[browse.Path].HTTPReqs)
+	assert.Equal(t, uint64(0), groups[browse.Path].Runs)
+}
+
 func TestFlattenGroup(t *testing.T) {
 	t.Run("blank", func(t *testing.T) {
 		g := &Group{}
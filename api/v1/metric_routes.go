@@ -21,28 +21,53 @@
 package v1
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
 	"net/http"
+	"strconv"
 	"time"
 
+	"github.com/gorilla/websocket"
 	"github.com/julienschmidt/httprouter"
 	"github.com/loadimpact/k6/api/common"
+	"github.com/loadimpact/k6/core"
+	"github.com/loadimpact/k6/lib/types"
 	"github.com/manyminds/api2go/jsonapi"
+	log "github.com/sirupsen/logrus"
 )
 
-func HandleGetMetrics(rw http.ResponseWriter, r *http.Request, p httprouter.Params) {
-	engine := common.GetEngine(r.Context())
+// DefaultStreamInterval is how often metric snapshots are pushed to the stream by default.
+const DefaultStreamInterval = 1 * time.Second
+
+// ThresholdEvent is pushed to the metrics stream when a metric's thresholds start or stop failing.
+type ThresholdEvent struct {
+	Metric  string         `json:"metric"`
+	Tainted bool           `json:"tainted"`
+	Time    types.Duration `json:"time"`
+}
 
+// getMetrics returns a snapshot of all the engine's metrics.
+func getMetrics(engine *core.Engine) []Metric {
 	var t time.Duration
 	if engine.Executor != nil {
 		t = engine.Executor.GetTime()
 	}
 
-	metrics := make([]Metric, 0)
+	engine.MetricsLock.Lock()
+	defer engine.MetricsLock.Unlock()
+
+	metrics := make([]Metric, 0, len(engine.Metrics))
 	for _, m := range engine.Metrics {
 		metrics = append(metrics, NewMetric(m, t))
 	}
+	return metrics
+}
 
-	data, err := jsonapi.Marshal(metrics)
+func HandleGetMetrics(rw http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	engine := common.GetEngine(r.Context())
+
+	data, err := jsonapi.Marshal(getMetrics(engine))
 	if err != nil {
 		apiError(rw, "Encoding error", err.Error(), http.StatusInternalServerError)
 		return
@@ -81,3 +106,134 @@ func HandleGetMetric(rw http.ResponseWriter, r *http.Request, p httprouter.Param
 	}
 	_, _ = rw.Write(data)
 }
+
+// streamWriter sends named events to a metrics stream client.
+type streamWriter interface {
+	WriteEvent(event string, data []byte) error
+}
+
+type sseWriter struct {
+	rw      http.ResponseWriter
+	flusher http.Flusher
+}
+
+func (w sseWriter) WriteEvent(event string, data []byte) error {
+	if _, err := fmt.Fprintf(w.rw, "event: %s\ndata: %s\n\n", event, data); err != nil {
+		return err
+	}
+	w.flusher.Flush()
+	return nil
+}
+
+type wsWriter struct {
+	conn *websocket.Conn
+}
+
+func (w wsWriter) WriteEvent(event string, data []byte) error {
+	return w.conn.WriteJSON(struct {
+		Event string          `json:"event"`
+		Data  json.RawMessage `json:"data"`
+	}{event, data})
+}
+
+// The default origin check only lets browsers connect from the API's own origin, i.e. the dashboard.
+//nolint:gochecknoglobals
+var streamUpgrader = websocket.Upgrader{}
+
+// HandleStreamMetrics pushes a "metrics" event with a snapshot of all metrics, in the same format
+// as GET /v1/metrics, right away and then on every interval, and a "threshold" event whenever
+// the thresholds of a metric start or stop failing. The interval is taken from the "interval"
+// query parameter, either as a duration ("5s") or as a number of seconds.
+//
+// The events are sent as Server-Sent Events, unless the request is a WebSocket handshake, in
+// which case every event is a {"event": ..., "data": ...} JSON message.
+func HandleStreamMetrics(rw http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	engine := common.GetEngine(r.Context())
+
+	interval := DefaultStreamInterval
+	if v := r.URL.Query().Get("interval"); v != "" {
+		var err error
+		if interval, err = parseStreamInterval(v); err != nil {
+			apiError(rw, "Invalid interval", err.Error(), http.StatusBadRequest)
+			return
+		}
+	}
+
+	ctx := r.Context()
+	var w streamWriter
+	if websocket.IsWebSocketUpgrade(r) {
+		conn, err := streamUpgrader.Upgrade(rw, r, nil)
+		if err != nil {
+			return // Upgrade() has already replied with an error
+		}
+		defer func() { _ = conn.Close() }()
+
+		// Control messages are only processed while reading, and a failed read means the
+		// client is gone
+		var cancel func()
+		ctx, cancel = context.WithCancel(ctx)
+		defer cancel()
+		go func() {
+			defer cancel()
+			for {
+				if _, _, err := conn.NextReader(); err != nil {
+					return
+				}
+			}
+		}()
+		w = wsWriter{conn}
+	} else {
+		flusher, ok := rw.(http.Flusher)
+		if !ok {
+			apiError(rw, "Streaming unsupported", "The connection doesn't support streaming", http.StatusInternalServerError)
+			return
+		}
+		rw.Header().Set("Content-Type", "text/event-stream")
+		rw.Header().Set("Cache-Control", "no-cache")
+		rw.Header().Set("Connection", "keep-alive")
+		rw.WriteHeader(http.StatusOK)
+		w = sseWriter{rw, flusher}
+	}
+
+	changes, unsubscribe := engine.SubscribeThresholds()
+	defer unsubscribe()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	err := writeMetricsEvent(w, engine)
+	for err == nil {
+		select {
+		case <-ticker.C:
+			err = writeMetricsEvent(w, engine)
+		case change := <-changes:
+			data, _ := json.Marshal(ThresholdEvent{change.Metric, change.Tainted, types.Duration(change.Time)})
+			err = w.WriteEvent("threshold", data)
+		case <-ctx.Done():
+			return
+		}
+	}
+	log.WithError(err).Debug("Metrics stream closed")
+}
+
+func writeMetricsEvent(w streamWriter, engine *core.Engine) error {
+	data, err := jsonapi.Marshal(getMetrics(engine))
+	if err != nil {
+		return err
+	}
+	return w.WriteEvent("metrics", data)
+}
+
+func parseStreamInterval(v string) (time.Duration, error) {
+	interval, err := time.ParseDuration(v)
+	if err != nil {
+		secs, ferr := strconv.ParseFloat(v, 64)
+		if ferr != nil {
+			return 0, err
+		}
+		interval = time.Duration(secs * float64(time.Second))
+	}
+	if interval < 100*time.Millisecond {
+		return 0, fmt.Errorf("the interval must be at least 100ms, not %s", interval)
+	}
+	return interval, nil
+}
@@ -21,16 +21,22 @@
 package v1
 
 import (
+	"bufio"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"strings"
 	"testing"
+	"time"
 
+	"github.com/gorilla/websocket"
+	"github.com/loadimpact/k6/api/common"
 	"github.com/loadimpact/k6/core"
 	"github.com/loadimpact/k6/lib"
 	"github.com/loadimpact/k6/stats"
 	"github.com/manyminds/api2go/jsonapi"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	null "gopkg.in/guregu/null.v3"
 )
 
@@ -117,3 +123,77 @@ func TestGetMetric(t *testing.T) {
 		})
 	})
 }
+
+func TestStreamMetrics(t *testing.T) {
+	engine, err := core.NewEngine(nil, lib.Options{})
+	require.NoError(t, err)
+	engine.Metrics = map[string]*stats.Metric{
+		"my_metric": stats.New("my_metric", stats.Trend, stats.Time),
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		NewHandler().ServeHTTP(rw, r.WithContext(common.WithEngine(r.Context(), engine)))
+	}))
+	defer srv.Close()
+
+	checkMetrics := func(t *testing.T, data []byte) {
+		var metrics []Metric
+		require.NoError(t, jsonapi.Unmarshal(data, &metrics))
+		require.Len(t, metrics, 1)
+		assert.Equal(t, "my_metric", metrics[0].Name)
+	}
+
+	t.Run("SSE", func(t *testing.T) {
+		res, err := http.Get(srv.URL + "/v1/metrics/stream?interval=100ms")
+		require.NoError(t, err)
+		defer func() { _ = res.Body.Close() }()
+		assert.Equal(t, http.StatusOK, res.StatusCode)
+		assert.Equal(t, "text/event-stream", res.Header.Get("Content-Type"))
+
+		start := time.Now()
+		r := bufio.NewReader(res.Body)
+		for i := 0; i < 2; i++ {
+			line, err := r.ReadString('\n')
+			require.NoError(t, err)
+			assert.Equal(t, "event: metrics\n", line)
+			line, err = r.ReadString('\n')
+			require.NoError(t, err)
+			require.True(t, strings.HasPrefix(line, "data: "))
+			checkMetrics(t, []byte(strings.TrimPrefix(line, "data: ")))
+			line, err = r.ReadString('\n')
+			require.NoError(t, err)
+			assert.Equal(t, "\n", line)
+		}
+		assert.True(t, time.Since(start) >= 100*time.Millisecond)
+	})
+
+	t.Run("WebSocket", func(t *testing.T) {
+		conn, _, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/v1/metrics/stream", nil)
+		require.NoError(t, err)
+		defer func() { _ = conn.Close() }()
+
+		var msg struct {
+			Event string          `json:"event"`
+			Data  json.RawMessage `json:"data"`
+		}
+		require.NoError(t, conn.ReadJSON(&msg))
+		assert.Equal(t, "metrics", msg.Event)
+		checkMetrics(t, msg.Data)
+	})
+
+	t.Run("WebSocket from another origin", func(t *testing.T) {
+		header := http.Header{"Origin": []string{"http://example.com"}}
+		_, res, err := websocket.DefaultDialer.Dial("ws"+strings.TrimPrefix(srv.URL, "http")+"/v1/metrics/stream", header)
+		assert.Error(t, err)
+		require.NotNil(t, res)
+		assert.Equal(t, http.StatusForbidden, res.StatusCode)
+	})
+
+	t.Run("invalid interval", func(t *testing.T) {
+		for _, interval := range []string{"abc", "10ms", "0.01"} {
+			rw := httptest.NewRecorder()
+			NewHandler().ServeHTTP(rw, newRequestWithEngine(engine, "GET", "/v1/metrics/stream?interval="+interval, nil))
+			assert.Equal(t, http.StatusBadRequest, rw.Result().StatusCode, interval)
+		}
+	})
+}
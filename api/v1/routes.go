@@ -33,7 +33,24 @@ func NewHandler() http.Handler {
 	router.PATCH("/v1/status", HandlePatchStatus)
 
 	router.GET("/v1/metrics", HandleGetMetrics)
-	router.GET("/v1/metrics/:id", HandleGetMetric)
+	router.GET("/v1/metrics/:id", func(rw http.ResponseWriter, r *http.Request, p httprouter.Params) {
+		// httprouter doesn't allow a static route to share a path segment with a parameter
+		if p.ByName("id") == "stream" {
+			HandleStreamMetrics(rw, r, p)
+			return
+		}
+		HandleGetMetric(rw, r, p)
+	})
+
+	router.GET("/v1/thresholds", HandleGetThresholds)
+	router.POST("/v1/thresholds", HandlePostThresholds)
+	router.DELETE("/v1/thresholds/:id", HandleDeleteThresholds)
+
+	router.GET("/v1/tags", HandleGetTags)
+	router.PATCH("/v1/tags", HandlePatchTags)
+
+	router.GET("/v1/events", HandleGetEvents)
+	router.POST("/v1/events", HandlePostEvents)
 
 	router.GET("/v1/groups", HandleGetGroups)
 	router.GET("/v1/groups/:id", HandleGetGroup)
@@ -44,5 +61,9 @@ func NewHandler() http.Handler {
 
 	router.POST("/v1/teardown", HandleRunTeardown)
 
+	router.POST("/v1/start", HandlePostStart)
+
+	router.GET("/v1/summary", HandleGetSummary)
+
 	return router
 }
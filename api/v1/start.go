@@ -0,0 +1,43 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package v1
+
+import (
+	"time"
+)
+
+// Start is when a test run with --wait-for-start is (or will be) started.
+type Start struct {
+	// Defaults to the current time when starting the test.
+	Time time.Time `json:"time" yaml:"time"`
+}
+
+func (s Start) GetName() string {
+	return "start"
+}
+
+func (s Start) GetID() string {
+	return "default"
+}
+
+func (s *Start) SetID(id string) error {
+	return nil
+}
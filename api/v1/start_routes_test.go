@@ -0,0 +1,97 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package v1
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/loadimpact/k6/core"
+	"github.com/loadimpact/k6/lib"
+	"github.com/manyminds/api2go/jsonapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPostStart(t *testing.T) {
+	t.Run("not waiting", func(t *testing.T) {
+		engine, err := core.NewEngine(nil, lib.Options{})
+		require.NoError(t, err)
+
+		rw := httptest.NewRecorder()
+		NewHandler().ServeHTTP(rw, newRequestWithEngine(engine, "POST", "/v1/start", nil))
+		assert.Equal(t, http.StatusConflict, rw.Result().StatusCode)
+	})
+
+	t.Run("now", func(t *testing.T) {
+		engine, err := core.NewEngine(nil, lib.Options{})
+		require.NoError(t, err)
+		engine.WaitForStart = true
+
+		before := time.Now()
+		rw := httptest.NewRecorder()
+		NewHandler().ServeHTTP(rw, newRequestWithEngine(engine, "POST", "/v1/start", nil))
+		assert.Equal(t, http.StatusOK, rw.Result().StatusCode)
+
+		var start Start
+		require.NoError(t, jsonapi.Unmarshal(rw.Body.Bytes(), &start))
+		assert.False(t, start.Time.Before(before))
+		startTime, ok := engine.GetStartTime()
+		assert.True(t, ok)
+		assert.True(t, startTime.Equal(start.Time))
+
+		rw = httptest.NewRecorder()
+		NewHandler().ServeHTTP(rw, newRequestWithEngine(engine, "POST", "/v1/start", nil))
+		assert.Equal(t, http.StatusConflict, rw.Result().StatusCode)
+	})
+
+	t.Run("scheduled", func(t *testing.T) {
+		engine, err := core.NewEngine(nil, lib.Options{})
+		require.NoError(t, err)
+		engine.WaitForStart = true
+
+		at := time.Now().Add(time.Hour).Truncate(time.Second)
+		body, err := jsonapi.Marshal(Start{Time: at})
+		require.NoError(t, err)
+		rw := httptest.NewRecorder()
+		NewHandler().ServeHTTP(rw, newRequestWithEngine(engine, "POST", "/v1/start", bytes.NewReader(body)))
+		assert.Equal(t, http.StatusOK, rw.Result().StatusCode)
+
+		startTime, ok := engine.GetStartTime()
+		assert.True(t, ok)
+		assert.True(t, startTime.Equal(at))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		engine, err := core.NewEngine(nil, lib.Options{})
+		require.NoError(t, err)
+		engine.WaitForStart = true
+
+		rw := httptest.NewRecorder()
+		NewHandler().ServeHTTP(rw, newRequestWithEngine(engine, "POST", "/v1/start", bytes.NewReader([]byte("{"))))
+		assert.Equal(t, http.StatusBadRequest, rw.Result().StatusCode)
+		_, ok := engine.GetStartTime()
+		assert.False(t, ok)
+	})
+}
@@ -21,7 +21,12 @@
 package v1
 
 import (
+	"sort"
+	"time"
+
 	"github.com/loadimpact/k6/core"
+	"github.com/loadimpact/k6/lib"
+	"github.com/loadimpact/k6/lib/types"
 	"gopkg.in/guregu/null.v3"
 )
 
@@ -31,18 +36,70 @@ type Status struct {
 	VUsMax null.Int  `json:"vus-max" yaml:"vus-max"`
 
 	// Readonly.
-	Running bool `json:"running" yaml:"running"`
-	Tainted bool `json:"tainted" yaml:"tainted"`
+	Initialized bool `json:"initialized" yaml:"initialized"`
+	Running     bool `json:"running" yaml:"running"`
+	Tainted     bool `json:"tainted" yaml:"tainted"`
+
+	// The phase of the test, or how it ended, see lib.RunStatus.String(), and the exit code k6
+	// is going to exit with once it's known.
+	RunStatus string   `json:"run-status" yaml:"run-status"`
+	ExitCode  null.Int `json:"exit-code" yaml:"exit-code"`
+
+	// When the test started and ended, how long it has been running, not counting pauses, and
+	// how long it's planned to run, if it has an end time or stages.
+	StartTime       null.Time          `json:"start-time" yaml:"start-time"`
+	EndTime         null.Time          `json:"end-time" yaml:"end-time"`
+	Elapsed         types.Duration     `json:"elapsed" yaml:"elapsed"`
+	PlannedDuration types.NullDuration `json:"planned-duration" yaml:"planned-duration"`
+
+	Schedulers []SchedulerProgress `json:"schedulers" yaml:"schedulers"`
+}
+
+// SchedulerProgress is how far along a configured scheduler of the test is, from 0 to 1.
+type SchedulerProgress struct {
+	Name     string  `json:"name" yaml:"name"`
+	Type     string  `json:"type" yaml:"type"`
+	Progress float64 `json:"progress" yaml:"progress"`
 }
 
 func NewStatus(engine *core.Engine) Status {
-	return Status{
-		Paused:  null.BoolFrom(engine.Executor.IsPaused()),
-		VUs:     null.IntFrom(engine.Executor.GetVUs()),
-		VUsMax:  null.IntFrom(engine.Executor.GetVUsMax()),
-		Running: engine.Executor.IsRunning(),
-		Tainted: engine.IsTainted(),
+	status := Status{
+		Paused:      null.BoolFrom(engine.Executor.IsPaused()),
+		VUs:         null.IntFrom(engine.Executor.GetVUs()),
+		VUsMax:      null.IntFrom(engine.Executor.GetVUsMax()),
+		Initialized: engine.IsInitialized(),
+		Running:     engine.Executor.IsRunning(),
+		Tainted:     engine.IsTainted(),
+
+		RunStatus:       engine.GetRunStatus().String(),
+		ExitCode:        engine.GetExitCode(),
+		Elapsed:         types.Duration(engine.Executor.GetTime()),
+		PlannedDuration: lib.GetPlannedDuration(engine.Executor),
+	}
+	if t, ok := engine.GetStartTime(); ok && !t.After(time.Now()) {
+		status.StartTime = null.TimeFrom(t)
+	}
+	if t, ok := engine.GetEndTime(); ok {
+		status.EndTime = null.TimeFrom(t)
+	}
+
+	// Only the scheduler that runs the iterations is executed, see lib.Options.SchedulerName(),
+	// so that's the one the executor's progress belongs to; any others never make progress.
+	running := engine.Options.SchedulerName()
+	progress := lib.GetProgress(engine.Executor)
+	if engine.GetRunStatus() == lib.RunStatusFinished {
+		progress = 1
+	}
+	status.Schedulers = make([]SchedulerProgress, 0, len(engine.Options.Execution))
+	for name, conf := range engine.Options.Execution {
+		sp := SchedulerProgress{Name: name, Type: conf.GetBaseConfig().Type}
+		if name == running {
+			sp.Progress = progress
+		}
+		status.Schedulers = append(status.Schedulers, sp)
 	}
+	sort.Slice(status.Schedulers, func(i, j int) bool { return status.Schedulers[i].Name < status.Schedulers[j].Name })
+	return status
 }
 
 func (s Status) GetName() string {
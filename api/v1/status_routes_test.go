@@ -22,15 +22,20 @@ package v1
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
 
 	"github.com/loadimpact/k6/core"
 	"github.com/loadimpact/k6/lib"
+	"github.com/loadimpact/k6/lib/scheduler"
+	"github.com/loadimpact/k6/lib/types"
 	"github.com/manyminds/api2go/jsonapi"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gopkg.in/guregu/null.v3"
 )
 
@@ -59,9 +64,80 @@ func TestGetStatus(t *testing.T) {
 		assert.True(t, status.VUs.Valid)
 		assert.True(t, status.VUsMax.Valid)
 		assert.False(t, status.Tainted)
+		assert.Equal(t, "initializing", status.RunStatus)
+		assert.False(t, status.ExitCode.Valid)
+		assert.False(t, status.StartTime.Valid)
+		assert.False(t, status.EndTime.Valid)
+		assert.False(t, status.PlannedDuration.Valid)
+		assert.Empty(t, status.Schedulers)
 	})
 }
 
+func TestGetStatusLifecycle(t *testing.T) {
+	engine, err := core.NewEngine(nil, lib.Options{
+		VUs:        null.IntFrom(1),
+		VUsMax:     null.IntFrom(1),
+		Iterations: null.IntFrom(1),
+		Duration:   types.NullDurationFrom(time.Minute),
+		Execution: scheduler.ConfigMap{
+			lib.DefaultSchedulerName: scheduler.NewSharedIterationsConfig(lib.DefaultSchedulerName),
+		},
+	})
+	require.NoError(t, err)
+	require.NoError(t, engine.Run(context.Background()))
+	engine.SetExitCode(0)
+
+	rw := httptest.NewRecorder()
+	NewHandler().ServeHTTP(rw, newRequestWithEngine(engine, "GET", "/v1/status", nil))
+	assert.Equal(t, http.StatusOK, rw.Result().StatusCode)
+
+	var status Status
+	require.NoError(t, jsonapi.Unmarshal(rw.Body.Bytes(), &status))
+	assert.Equal(t, "finished", status.RunStatus)
+	assert.Equal(t, null.IntFrom(0), status.ExitCode)
+	assert.True(t, status.StartTime.Valid)
+	assert.True(t, status.EndTime.Valid)
+	assert.False(t, status.EndTime.Time.Before(status.StartTime.Time))
+	assert.Equal(t, types.NullDurationFrom(time.Minute), status.PlannedDuration)
+	assert.Equal(t, []SchedulerProgress{
+		{Name: lib.DefaultSchedulerName, Type: "shared-iterations", Progress: 1},
+	}, status.Schedulers)
+}
+
+func TestGetStatusSchedulers(t *testing.T) {
+	engine, err := core.NewEngine(nil, lib.Options{
+		VUs:        null.IntFrom(1),
+		VUsMax:     null.IntFrom(1),
+		Iterations: null.IntFrom(1),
+		Execution: scheduler.ConfigMap{
+			"other":                  scheduler.NewConstantLoopingVUsConfig("other"),
+			lib.DefaultSchedulerName: scheduler.NewSharedIterationsConfig(lib.DefaultSchedulerName),
+		},
+	})
+	require.NoError(t, err)
+
+	getSchedulers := func() []SchedulerProgress {
+		rw := httptest.NewRecorder()
+		NewHandler().ServeHTTP(rw, newRequestWithEngine(engine, "GET", "/v1/status", nil))
+		require.Equal(t, http.StatusOK, rw.Result().StatusCode)
+		var status Status
+		require.NoError(t, jsonapi.Unmarshal(rw.Body.Bytes(), &status))
+		return status.Schedulers
+	}
+
+	assert.Equal(t, []SchedulerProgress{
+		{Name: lib.DefaultSchedulerName, Type: "shared-iterations", Progress: 0},
+		{Name: "other", Type: "constant-looping-vus", Progress: 0},
+	}, getSchedulers())
+
+	// Only the scheduler that runs the iterations makes progress
+	require.NoError(t, engine.Run(context.Background()))
+	assert.Equal(t, []SchedulerProgress{
+		{Name: lib.DefaultSchedulerName, Type: "shared-iterations", Progress: 1},
+		{Name: "other", Type: "constant-looping-vus", Progress: 0},
+	}, getSchedulers())
+}
+
 func TestPatchStatus(t *testing.T) {
 	testdata := map[string]struct {
 		StatusCode int
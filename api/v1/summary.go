@@ -0,0 +1,47 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package v1
+
+import (
+	"github.com/loadimpact/k6/lib/types"
+)
+
+// Summary is the data of the end-of-test summary, as far as the test has gotten.
+type Summary struct {
+	Time    types.Duration    `json:"time" yaml:"time"`
+	Tainted bool              `json:"tainted" yaml:"tainted"`
+	Metrics map[string]Metric `json:"metrics" yaml:"metrics"`
+
+	// All groups, with their checks and live stats, see FlattenGroup().
+	Groups []*Group `json:"groups" yaml:"groups"`
+}
+
+func (s Summary) GetName() string {
+	return "summary"
+}
+
+func (s Summary) GetID() string {
+	return "default"
+}
+
+func (s *Summary) SetID(id string) error {
+	return nil
+}
@@ -0,0 +1,70 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package v1
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/loadimpact/k6/core"
+	"github.com/loadimpact/k6/core/local"
+	"github.com/loadimpact/k6/lib"
+	"github.com/loadimpact/k6/stats"
+	"github.com/manyminds/api2go/jsonapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetSummary(t *testing.T) {
+	root, err := lib.NewGroup("", nil)
+	require.NoError(t, err)
+	group, err := root.Group("login")
+	require.NoError(t, err)
+	check, err := group.Check("logged in")
+	require.NoError(t, err)
+	check.Passes = 3
+
+	engine, err := core.NewEngine(local.New(&lib.MiniRunner{Group: root}), lib.Options{})
+	require.NoError(t, err)
+	m := stats.New("my_metric", stats.Trend, stats.Time)
+	m.Sink.Add(stats.Sample{Value: 100})
+	m.Sink.Add(stats.Sample{Value: 200})
+	engine.Metrics[m.Name] = m
+
+	rw := httptest.NewRecorder()
+	NewHandler().ServeHTTP(rw, newRequestWithEngine(engine, "GET", "/v1/summary", nil))
+	assert.Equal(t, http.StatusOK, rw.Result().StatusCode)
+
+	var summary Summary
+	require.NoError(t, jsonapi.Unmarshal(rw.Body.Bytes(), &summary))
+	assert.False(t, summary.Tainted)
+	if assert.Contains(t, summary.Metrics, "my_metric") {
+		assert.Equal(t, stats.Trend, summary.Metrics["my_metric"].Type.Type)
+		assert.Equal(t, 150.0, summary.Metrics["my_metric"].Sample["avg"])
+	}
+	if assert.Len(t, summary.Groups, 2) {
+		assert.Equal(t, "::login", summary.Groups[1].Path)
+		if assert.Len(t, summary.Groups[1].Checks, 1) {
+			assert.Equal(t, int64(3), summary.Groups[1].Checks[0].Passes)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package v1
+
+import (
+	"gopkg.in/guregu/null.v3"
+)
+
+// Tags are added to all samples emitted by the test from now on, unless the samples already
+// have tags with the same names. When patching, a null value removes the tag.
+type Tags struct {
+	Tags map[string]null.String `json:"tags" yaml:"tags"`
+}
+
+// NewTags converts a plain tag set to Tags.
+func NewTags(tags map[string]string) Tags {
+	t := Tags{Tags: make(map[string]null.String, len(tags))}
+	for k, v := range tags {
+		t.Tags[k] = null.StringFrom(v)
+	}
+	return t
+}
+
+func (t Tags) GetName() string {
+	return "tags"
+}
+
+func (t Tags) GetID() string {
+	return "default"
+}
+
+func (t Tags) SetID(id string) error {
+	return nil
+}
@@ -0,0 +1,84 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package v1
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/loadimpact/k6/core"
+	"github.com/loadimpact/k6/lib"
+	"github.com/manyminds/api2go/jsonapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v3"
+)
+
+func TestGetTags(t *testing.T) {
+	engine, err := core.NewEngine(nil, lib.Options{})
+	require.NoError(t, err)
+	engine.UpdateTags(map[string]string{"phase": "warmup"}, nil)
+
+	rw := httptest.NewRecorder()
+	NewHandler().ServeHTTP(rw, newRequestWithEngine(engine, "GET", "/v1/tags", nil))
+	res := rw.Result()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	var tags Tags
+	require.NoError(t, jsonapi.Unmarshal(rw.Body.Bytes(), &tags))
+	assert.Equal(t, map[string]null.String{"phase": null.StringFrom("warmup")}, tags.Tags)
+}
+
+func TestPatchTags(t *testing.T) {
+	testdata := map[string]struct {
+		StatusCode int
+		Patch      map[string]null.String
+		Tags       map[string]string
+	}{
+		"nothing": {200, nil, map[string]string{"phase": "warmup", "build": "123"}},
+		"set": {200, map[string]null.String{"phase": null.StringFrom("peak")},
+			map[string]string{"phase": "peak", "build": "123"}},
+		"add": {200, map[string]null.String{"region": null.StringFrom("eu")},
+			map[string]string{"phase": "warmup", "build": "123", "region": "eu"}},
+		"remove": {200, map[string]null.String{"build": null.String{}},
+			map[string]string{"phase": "warmup"}},
+		"empty name": {400, map[string]null.String{"": null.StringFrom("x")},
+			map[string]string{"phase": "warmup", "build": "123"}},
+	}
+
+	for name, indata := range testdata {
+		t.Run(name, func(t *testing.T) {
+			engine, err := core.NewEngine(nil, lib.Options{})
+			require.NoError(t, err)
+			engine.UpdateTags(map[string]string{"phase": "warmup", "build": "123"}, nil)
+
+			body, err := jsonapi.Marshal(Tags{Tags: indata.Patch})
+			require.NoError(t, err)
+
+			rw := httptest.NewRecorder()
+			NewHandler().ServeHTTP(rw, newRequestWithEngine(engine, "PATCH", "/v1/tags", bytes.NewReader(body)))
+			assert.Equal(t, indata.StatusCode, rw.Result().StatusCode)
+			assert.Equal(t, indata.Tags, engine.GetTags())
+		})
+	}
+}
@@ -0,0 +1,59 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package v1
+
+import (
+	"github.com/loadimpact/k6/core"
+	"github.com/loadimpact/k6/stats"
+	"gopkg.in/guregu/null.v3"
+)
+
+// Thresholds are the thresholds of a metric, or of a submetric like "http_req_duration{status:200}".
+type Thresholds struct {
+	Metric     string           `json:"-" yaml:"metric"`
+	Thresholds stats.Thresholds `json:"thresholds" yaml:"-"`
+
+	// Readonly, null if the thresholds haven't been evaluated yet.
+	Tainted null.Bool `json:"tainted" yaml:"tainted"`
+}
+
+// NewThresholds returns the thresholds of the named metric, which the engine's metrics lock
+// must be held for.
+func NewThresholds(engine *core.Engine, name string, ths stats.Thresholds) Thresholds {
+	t := Thresholds{Metric: name, Thresholds: ths}
+	if m, ok := engine.Metrics[name]; ok {
+		t.Tainted = m.Tainted
+	}
+	return t
+}
+
+func (t Thresholds) GetName() string {
+	return "thresholds"
+}
+
+func (t Thresholds) GetID() string {
+	return t.Metric
+}
+
+func (t *Thresholds) SetID(id string) error {
+	t.Metric = id
+	return nil
+}
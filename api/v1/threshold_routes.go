@@ -0,0 +1,102 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package v1
+
+import (
+	"io/ioutil"
+	"net/http"
+	"sort"
+
+	"github.com/julienschmidt/httprouter"
+	"github.com/loadimpact/k6/api/common"
+	"github.com/manyminds/api2go/jsonapi"
+)
+
+func HandleGetThresholds(rw http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	engine := common.GetEngine(r.Context())
+
+	all := engine.GetThresholds()
+	names := make([]string, 0, len(all))
+	for name := range all {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	thresholds := make([]Thresholds, 0, len(names))
+	engine.MetricsLock.Lock()
+	for _, name := range names {
+		thresholds = append(thresholds, NewThresholds(engine, name, all[name]))
+	}
+	engine.MetricsLock.Unlock()
+
+	data, err := jsonapi.Marshal(thresholds)
+	if err != nil {
+		apiError(rw, "Encoding error", err.Error(), http.StatusInternalServerError)
+		return
+	}
+	_, _ = rw.Write(data)
+}
+
+// HandlePostThresholds sets the thresholds of a metric, replacing any existing ones.
+func HandlePostThresholds(rw http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	engine := common.GetEngine(r.Context())
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		apiError(rw, "Couldn't read request", err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	var thresholds Thresholds
+	if err := jsonapi.Unmarshal(body, &thresholds); err != nil {
+		apiError(rw, "Invalid data", err.Error(), http.StatusBadRequest)
+		return
+	}
+	if thresholds.Metric == "" {
+		apiError(rw, "Invalid data", "The ID of the thresholds must be the metric name", http.StatusBadRequest)
+		return
+	}
+	if len(thresholds.Thresholds.Thresholds) == 0 {
+		apiError(rw, "Invalid data", "No thresholds given, use DELETE to remove them", http.StatusBadRequest)
+		return
+	}
+
+	engine.SetThresholds(thresholds.Metric, thresholds.Thresholds)
+
+	engine.MetricsLock.Lock()
+	data, err := jsonapi.Marshal(NewThresholds(engine, thresholds.Metric, thresholds.Thresholds))
+	engine.MetricsLock.Unlock()
+	if err != nil {
+		apiError(rw, "Encoding error", err.Error(), http.StatusInternalServerError)
+		return
+	}
+	_, _ = rw.Write(data)
+}
+
+func HandleDeleteThresholds(rw http.ResponseWriter, r *http.Request, p httprouter.Params) {
+	engine := common.GetEngine(r.Context())
+
+	if !engine.DeleteThresholds(p.ByName("id")) {
+		apiError(rw, "Not Found", "No thresholds for that metric were found", http.StatusNotFound)
+		return
+	}
+	rw.WriteHeader(http.StatusNoContent)
+}
@@ -0,0 +1,128 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package v1
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/loadimpact/k6/core"
+	"github.com/loadimpact/k6/lib"
+	"github.com/loadimpact/k6/stats"
+	"github.com/manyminds/api2go/jsonapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetThresholds(t *testing.T) {
+	ths, err := stats.NewThresholds([]string{"rate<0.01"})
+	require.NoError(t, err)
+	engine, err := core.NewEngine(nil, lib.Options{
+		Thresholds: map[string]stats.Thresholds{"errors": ths},
+	})
+	require.NoError(t, err)
+
+	rw := httptest.NewRecorder()
+	NewHandler().ServeHTTP(rw, newRequestWithEngine(engine, "GET", "/v1/thresholds", nil))
+	res := rw.Result()
+	require.Equal(t, http.StatusOK, res.StatusCode)
+
+	var thresholds []Thresholds
+	require.NoError(t, jsonapi.Unmarshal(rw.Body.Bytes(), &thresholds))
+	require.Len(t, thresholds, 1)
+	assert.Equal(t, "errors", thresholds[0].Metric)
+	require.Len(t, thresholds[0].Thresholds.Thresholds, 1)
+	assert.Equal(t, "rate<0.01", thresholds[0].Thresholds.Thresholds[0].Source)
+	assert.False(t, thresholds[0].Tainted.Valid)
+}
+
+func TestPostThresholds(t *testing.T) {
+	ths, err := stats.NewThresholds([]string{"p(95)<500"})
+	require.NoError(t, err)
+
+	testdata := map[string]struct {
+		StatusCode int
+		Thresholds Thresholds
+	}{
+		"metric":    {200, Thresholds{Metric: "http_req_duration", Thresholds: ths}},
+		"submetric": {200, Thresholds{Metric: "http_req_duration{status:200}", Thresholds: ths}},
+		"no metric": {400, Thresholds{Thresholds: ths}},
+		"empty":     {400, Thresholds{Metric: "http_req_duration"}},
+	}
+
+	for name, indata := range testdata {
+		t.Run(name, func(t *testing.T) {
+			engine, err := core.NewEngine(nil, lib.Options{})
+			require.NoError(t, err)
+
+			body, err := jsonapi.Marshal(indata.Thresholds)
+			require.NoError(t, err)
+
+			rw := httptest.NewRecorder()
+			NewHandler().ServeHTTP(rw, newRequestWithEngine(engine, "POST", "/v1/thresholds", bytes.NewReader(body)))
+			res := rw.Result()
+			require.Equal(t, indata.StatusCode, res.StatusCode)
+			if indata.StatusCode != 200 {
+				return
+			}
+
+			var thresholds Thresholds
+			require.NoError(t, jsonapi.Unmarshal(rw.Body.Bytes(), &thresholds))
+			assert.Equal(t, indata.Thresholds.Metric, thresholds.Metric)
+
+			set, ok := engine.GetThresholds()[indata.Thresholds.Metric]
+			require.True(t, ok)
+			require.Len(t, set.Thresholds, 1)
+			assert.Equal(t, "p(95)<500", set.Thresholds[0].Source)
+		})
+	}
+
+	t.Run("invalid", func(t *testing.T) {
+		engine, err := core.NewEngine(nil, lib.Options{})
+		require.NoError(t, err)
+
+		body := []byte(`{"data":{"type":"thresholds","id":"http_req_duration","attributes":{"thresholds":["p(95)<"]}}}`)
+		rw := httptest.NewRecorder()
+		NewHandler().ServeHTTP(rw, newRequestWithEngine(engine, "POST", "/v1/thresholds", bytes.NewReader(body)))
+		assert.Equal(t, http.StatusBadRequest, rw.Result().StatusCode)
+		assert.Empty(t, engine.GetThresholds())
+	})
+}
+
+func TestDeleteThresholds(t *testing.T) {
+	ths, err := stats.NewThresholds([]string{"rate<0.01"})
+	require.NoError(t, err)
+	engine, err := core.NewEngine(nil, lib.Options{
+		Thresholds: map[string]stats.Thresholds{"errors": ths},
+	})
+	require.NoError(t, err)
+
+	rw := httptest.NewRecorder()
+	NewHandler().ServeHTTP(rw, newRequestWithEngine(engine, "DELETE", "/v1/thresholds/errors", nil))
+	assert.Equal(t, http.StatusNoContent, rw.Result().StatusCode)
+	assert.Empty(t, engine.GetThresholds())
+
+	rw = httptest.NewRecorder()
+	NewHandler().ServeHTTP(rw, newRequestWithEngine(engine, "DELETE", "/v1/thresholds/errors", nil))
+	assert.Equal(t, http.StatusNotFound, rw.Result().StatusCode)
+}
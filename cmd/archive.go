@@ -29,7 +29,10 @@ import (
 	"github.com/spf13/pflag"
 )
 
-var archiveOut = "archive.tar"
+var (
+	archiveOut               = "archive.tar"
+	archiveIncludeTranspiled = false
+)
 
 // archiveCmd represents the pause command
 var archiveCmd = &cobra.Command{
@@ -37,7 +40,11 @@ var archiveCmd = &cobra.Command{
 	Short: "Create an archive",
 	Long: `Create an archive.
 
-An archive is a fully self-contained test run, and can be executed identically elsewhere.`,
+An archive is a fully self-contained test run, and can be executed identically elsewhere.
+
+With --include-transpiled, the archive also contains the ES5 code that the ES6 sources were
+transpiled to, so running it with the same k6 version starts faster, since they don't need to
+be transpiled again. This matters most when the archive is run on many machines.`,
 	Example: `
   # Archive a test run.
   k6 archive -u 10 -d 10s -O myarchive.tar script.js
@@ -88,6 +95,9 @@ An archive is a fully self-contained test run, and can be executed identically e
 
 		// Archive.
 		arc := r.MakeArchive()
+		if !archiveIncludeTranspiled {
+			arc.Transpiled = nil
+		}
 		f, err := os.Create(archiveOut)
 		if err != nil {
 			return err
@@ -103,6 +113,8 @@ func archiveCmdFlagSet() *pflag.FlagSet {
 	flags.AddFlagSet(runtimeOptionFlagSet(false))
 	//TODO: figure out a better way to handle the CLI flags - global variables are not very testable... :/
 	flags.StringVarP(&archiveOut, "archive-out", "O", archiveOut, "archive output filename")
+	flags.BoolVar(&archiveIncludeTranspiled, "include-transpiled", archiveIncludeTranspiled,
+		"include the transpiled code of ES6 sources, to start faster with the same k6 version")
 	return flags
 }
 
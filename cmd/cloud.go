@@ -29,7 +29,6 @@ import (
 	"syscall"
 	"time"
 
-	"github.com/kelseyhightower/envconfig"
 	"github.com/loadimpact/k6/lib"
 	"github.com/loadimpact/k6/lib/consts"
 	"github.com/loadimpact/k6/loader"
@@ -108,8 +107,8 @@ This will execute the test on the Load Impact cloud service. Use "k6 login cloud
 		}
 
 		// Cloud config
-		cloudConfig := cloud.NewConfig().Apply(derivedConf.Collectors.Cloud)
-		if err := envconfig.Process("k6", &cloudConfig); err != nil {
+		cloudConfig, err := cloud.GetConsolidatedConfig(derivedConf.Collectors[collectorCloud], "")
+		if err != nil {
 			return err
 		}
 		if !cloudConfig.Token.Valid {
@@ -117,6 +116,9 @@ This will execute the test on the Load Impact cloud service. Use "k6 login cloud
 		}
 
 		arc := r.MakeArchive()
+		// The cloud doesn't necessarily run the same k6 version, so the transpiled code would only
+		// make the upload bigger.
+		arc.Transpiled = nil
 		// TODO: Fix this
 		// We reuse cloud.Config for parsing options.ext.loadimpact, but this probably shouldn't be
 		// done as the idea of options.ext is that they are extensible without touching k6. But in
@@ -24,32 +24,23 @@ import (
 	"fmt"
 	"strings"
 
-	"gopkg.in/guregu/null.v3"
-
-	"github.com/kelseyhightower/envconfig"
 	"github.com/loadimpact/k6/lib"
-	"github.com/loadimpact/k6/lib/consts"
 	"github.com/loadimpact/k6/loader"
-	"github.com/loadimpact/k6/stats/cloud"
-	"github.com/loadimpact/k6/stats/datadog"
-	"github.com/loadimpact/k6/stats/influxdb"
-	jsonc "github.com/loadimpact/k6/stats/json"
-	"github.com/loadimpact/k6/stats/kafka"
-	"github.com/loadimpact/k6/stats/statsd"
-	"github.com/loadimpact/k6/stats/statsd/common"
-	"github.com/pkg/errors"
+	statsoutput "github.com/loadimpact/k6/stats/output"
 	"github.com/spf13/afero"
-)
 
-const (
-	collectorInfluxDB = "influxdb"
-	collectorJSON     = "json"
-	collectorKafka    = "kafka"
-	collectorCloud    = "cloud"
-	collectorStatsD   = "statsd"
-	collectorDatadog  = "datadog"
+	// The built-in outputs, they register themselves in the output registry
+	_ "github.com/loadimpact/k6/stats/cloud"
+	_ "github.com/loadimpact/k6/stats/datadog"
+	_ "github.com/loadimpact/k6/stats/http"
+	_ "github.com/loadimpact/k6/stats/influxdb"
+	_ "github.com/loadimpact/k6/stats/json"
+	_ "github.com/loadimpact/k6/stats/kafka"
+	_ "github.com/loadimpact/k6/stats/statsd"
 )
 
+const collectorCloud = "cloud"
+
 func parseCollector(s string) (t, arg string) {
 	parts := strings.SplitN(s, "=", 2)
 	switch len(parts) {
@@ -63,61 +54,11 @@ func parseCollector(s string) (t, arg string) {
 }
 
 func newCollector(collectorName, arg string, src *loader.SourceData, conf Config) (lib.Collector, error) {
-	getCollector := func() (lib.Collector, error) {
-		switch collectorName {
-		case collectorJSON:
-			return jsonc.New(afero.NewOsFs(), arg)
-		case collectorInfluxDB:
-			config := influxdb.NewConfig().Apply(conf.Collectors.InfluxDB)
-			if err := envconfig.Process("k6", &config); err != nil {
-				return nil, err
-			}
-			urlConfig, err := influxdb.ParseURL(arg)
-			if err != nil {
-				return nil, err
-			}
-			config = config.Apply(urlConfig)
-			return influxdb.New(config)
-		case collectorCloud:
-			config := cloud.NewConfig().Apply(conf.Collectors.Cloud)
-			if err := envconfig.Process("k6", &config); err != nil {
-				return nil, err
-			}
-			if arg != "" {
-				config.Name = null.StringFrom(arg)
-			}
-			return cloud.New(config, src, conf.Options, consts.Version)
-		case collectorKafka:
-			config := kafka.NewConfig().Apply(conf.Collectors.Kafka)
-			if err := envconfig.Process("k6", &config); err != nil {
-				return nil, err
-			}
-			if arg != "" {
-				cmdConfig, err := kafka.ParseArg(arg)
-				if err != nil {
-					return nil, err
-				}
-				config = config.Apply(cmdConfig)
-			}
-			return kafka.New(config)
-		case collectorStatsD:
-			config := common.NewConfig().Apply(conf.Collectors.StatsD)
-			if err := envconfig.Process("k6_statsd", &config); err != nil {
-				return nil, err
-			}
-			return statsd.New(config)
-		case collectorDatadog:
-			config := datadog.NewConfig().Apply(conf.Collectors.Datadog)
-			if err := envconfig.Process("k6_datadog", &config); err != nil {
-				return nil, err
-			}
-			return datadog.New(config)
-		default:
-			return nil, errors.Errorf("unknown output type: %s", collectorName)
-		}
-	}
-
-	collector, err := getCollector()
+	collector, err := statsoutput.New(collectorName, conf.Collectors[collectorName], arg, statsoutput.Params{
+		FS:      afero.NewOsFs(),
+		Options: conf.Options,
+		Source:  src,
+	})
 	if err != nil {
 		return collector, err
 	}
@@ -33,11 +33,6 @@ import (
 	"github.com/loadimpact/k6/lib"
 	"github.com/loadimpact/k6/lib/scheduler"
 	"github.com/loadimpact/k6/lib/types"
-	"github.com/loadimpact/k6/stats/cloud"
-	"github.com/loadimpact/k6/stats/datadog"
-	"github.com/loadimpact/k6/stats/influxdb"
-	"github.com/loadimpact/k6/stats/kafka"
-	"github.com/loadimpact/k6/stats/statsd/common"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/afero"
 	"github.com/spf13/pflag"
@@ -65,13 +60,9 @@ type Config struct {
 	NoThresholds  null.Bool `json:"noThresholds" envconfig:"no_thresholds"`
 	NoSummary     null.Bool `json:"noSummary" envconfig:"no_summary"`
 
-	Collectors struct {
-		InfluxDB influxdb.Config `json:"influxdb"`
-		Kafka    kafka.Config    `json:"kafka"`
-		Cloud    cloud.Config    `json:"cloud"`
-		StatsD   common.Config   `json:"statsd"`
-		Datadog  datadog.Config  `json:"datadog"`
-	} `json:"collectors"`
+	// Collectors holds the raw JSON config of every output, by name. It's parsed by the outputs
+	// themselves, see the stats/output package.
+	Collectors map[string]json.RawMessage `json:"collectors" ignored:"true"`
 }
 
 func (c Config) Apply(cfg Config) Config {
@@ -91,11 +82,17 @@ func (c Config) Apply(cfg Config) Config {
 	if cfg.NoSummary.Valid {
 		c.NoSummary = cfg.NoSummary
 	}
-	c.Collectors.InfluxDB = c.Collectors.InfluxDB.Apply(cfg.Collectors.InfluxDB)
-	c.Collectors.Cloud = c.Collectors.Cloud.Apply(cfg.Collectors.Cloud)
-	c.Collectors.Kafka = c.Collectors.Kafka.Apply(cfg.Collectors.Kafka)
-	c.Collectors.StatsD = c.Collectors.StatsD.Apply(cfg.Collectors.StatsD)
-	c.Collectors.Datadog = c.Collectors.Datadog.Apply(cfg.Collectors.Datadog)
+	if len(cfg.Collectors) > 0 {
+		// Copy the map, so the receiver's isn't modified
+		collectors := make(map[string]json.RawMessage, len(c.Collectors)+len(cfg.Collectors))
+		for name, conf := range c.Collectors {
+			collectors[name] = conf
+		}
+		for name, conf := range cfg.Collectors {
+			collectors[name] = conf
+		}
+		c.Collectors = collectors
+	}
 	return c
 }
 
@@ -166,18 +163,32 @@ func writeDiskConfig(fs afero.Fs, configPath string, conf Config) error {
 	return afero.WriteFile(fs, configPath, data, 0644)
 }
 
+// getCollectorConfig decodes the JSON config of the named output into dst, if there is any. Unlike
+// the output's own config consolidation, the environment variables aren't taken into account.
+func (c Config) getCollectorConfig(name string, dst interface{}) error {
+	data, ok := c.Collectors[name]
+	if !ok {
+		return nil
+	}
+	return json.Unmarshal(data, dst)
+}
+
+// setCollectorConfig replaces the JSON config of the named output.
+func (c *Config) setCollectorConfig(name string, conf interface{}) error {
+	data, err := json.Marshal(conf)
+	if err != nil {
+		return err
+	}
+	*c = c.Apply(Config{Collectors: map[string]json.RawMessage{name: data}})
+	return nil
+}
+
 // Reads configuration variables from the environment.
 func readEnvConfig() (conf Config, err error) {
 	// TODO: replace envconfig and refactor the whole configuration from the groun up :/
-	for _, err := range []error{
-		envconfig.Process("k6", &conf),
-		envconfig.Process("k6", &conf.Collectors.Cloud),
-		envconfig.Process("k6", &conf.Collectors.InfluxDB),
-		envconfig.Process("k6", &conf.Collectors.Kafka),
-	} {
-		return conf, err
-	}
-	return conf, nil
+	// The outputs read their own environment variables when their config is consolidated.
+	err = envconfig.Process("k6", &conf)
+	return conf, err
 }
 
 type executionConflictConfigError string
@@ -280,10 +291,6 @@ func deriveExecutionConfig(conf Config) (Config, error) {
 // TODO: add better validation, more explicit default values and improve consistency between formats
 // TODO: accumulate all errors and differentiate between the layers?
 func getConsolidatedConfig(fs afero.Fs, cliConf Config, runner lib.Runner) (conf Config, err error) {
-	cliConf.Collectors.InfluxDB = influxdb.NewConfig().Apply(cliConf.Collectors.InfluxDB)
-	cliConf.Collectors.Cloud = cloud.NewConfig().Apply(cliConf.Collectors.Cloud)
-	cliConf.Collectors.Kafka = kafka.NewConfig().Apply(cliConf.Collectors.Kafka)
-
 	fileConf, _, err := readDiskConfig(fs)
 	if err != nil {
 		return conf, err
@@ -207,6 +207,11 @@ func resetStickyGlobalVars() {
 	runType = ""
 	runNoSetup = false
 	runNoTeardown = false
+	runWaitForStart = false
+	runDashboard = false
+	runAPIReadAuth = ""
+	runAPITLSCert = ""
+	runAPITLSKey = ""
 }
 
 // Something that makes the test also be a valid io.Writer, useful for passing it
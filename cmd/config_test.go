@@ -21,11 +21,15 @@
 package cmd
 
 import (
+	"encoding/json"
 	"os"
 	"testing"
 
 	"github.com/kelseyhightower/envconfig"
+	"github.com/loadimpact/k6/lib"
+	"github.com/loadimpact/k6/stats/influxdb"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gopkg.in/guregu/null.v3"
 )
 
@@ -131,4 +135,37 @@ func TestConfigApply(t *testing.T) {
 		conf = Config{}.Apply(Config{Out: []string{"influxdb", "json"}})
 		assert.Equal(t, []string{"influxdb", "json"}, conf.Out)
 	})
+	t.Run("Collectors", func(t *testing.T) {
+		base := Config{Collectors: map[string]json.RawMessage{
+			"influxdb": json.RawMessage(`{"db":"base"}`),
+			"kafka":    json.RawMessage(`{"topic":"base"}`),
+		}}
+		conf := base.Apply(Config{Collectors: map[string]json.RawMessage{
+			"influxdb": json.RawMessage(`{"db":"override"}`),
+			"custom":   json.RawMessage(`{}`),
+		}})
+		assert.Equal(t, map[string]json.RawMessage{
+			"influxdb": json.RawMessage(`{"db":"override"}`),
+			"kafka":    json.RawMessage(`{"topic":"base"}`),
+			"custom":   json.RawMessage(`{}`),
+		}, conf.Collectors)
+		assert.Equal(t, json.RawMessage(`{"db":"base"}`), base.Collectors["influxdb"])
+	})
+}
+
+func TestNewCollector(t *testing.T) {
+	conf := Config{Collectors: map[string]json.RawMessage{
+		"influxdb": json.RawMessage(`{"addr":"http://example.com:8086","db":"fromjson"}`),
+	}}
+	conf.SystemTags = lib.GetTagSet(lib.DefaultSystemTagList...)
+
+	collector, err := newCollector("influxdb", "http://example.com:8086/fromarg", nil, conf)
+	require.NoError(t, err)
+	assert.Equal(t, null.StringFrom("fromarg"), collector.(*influxdb.Collector).Config.DB)
+
+	_, err = newCollector("nonexistent", "", nil, conf)
+	assert.EqualError(t, err, "unknown output type: nonexistent")
+
+	_, err = newCollector("http", "", nil, conf)
+	assert.EqualError(t, err, "the http output needs a URL to send the samples to")
 }
@@ -67,7 +67,11 @@ This will set the default token used when just "k6 run -o cloud" is passed.`,
 		reset := getNullBool(cmd.Flags(), "reset")
 		token := getNullString(cmd.Flags(), "token")
 
-		newCloudConf := cloud.NewConfig().Apply(currentDiskConf.Collectors.Cloud)
+		diskCloudConf := cloud.Config{}
+		if err := currentDiskConf.getCollectorConfig(collectorCloud, &diskCloudConf); err != nil {
+			return err
+		}
+		newCloudConf := cloud.NewConfig().Apply(diskCloudConf)
 
 		switch {
 		case reset.Valid:
@@ -96,7 +100,11 @@ This will set the default token used when just "k6 run -o cloud" is passed.`,
 			email := vals["Email"].(string)
 			password := vals["Password"].(string)
 
-			client := cloud.NewClient("", k6Conf.Collectors.Cloud.Host.String, consts.Version)
+			cloudConf, err := cloud.GetConsolidatedConfig(k6Conf.Collectors[collectorCloud], "")
+			if err != nil {
+				return err
+			}
+			client := cloud.NewClient("", cloudConf.Host.String, consts.Version)
 			res, err := client.Login(email, password)
 			if err != nil {
 				return err
@@ -109,7 +117,9 @@ This will set the default token used when just "k6 run -o cloud" is passed.`,
 			newCloudConf.Token = null.StringFrom(res.Token)
 		}
 
-		currentDiskConf.Collectors.Cloud = newCloudConf
+		if err := currentDiskConf.setCollectorConfig(collectorCloud, newCloudConf); err != nil {
+			return err
+		}
 		if err := writeDiskConfig(fs, configPath, currentDiskConf); err != nil {
 			return err
 		}
@@ -47,7 +47,11 @@ This will set the default server used when just "-o influxdb" is passed.`,
 			return err
 		}
 
-		conf := influxdb.NewConfig().Apply(config.Collectors.InfluxDB)
+		diskConf := influxdb.Config{}
+		if err := config.getCollectorConfig("influxdb", &diskConf); err != nil {
+			return err
+		}
+		conf := influxdb.NewConfig().Apply(diskConf)
 		if len(args) > 0 {
 			urlConf, err := influxdb.ParseURL(args[0])
 			if err != nil {
@@ -104,7 +108,9 @@ This will set the default server used when just "-o influxdb" is passed.`,
 			return err
 		}
 
-		config.Collectors.InfluxDB = conf
+		if err := config.setCollectorConfig("influxdb", conf); err != nil {
+			return err
+		}
 		return writeDiskConfig(fs, configPath, config)
 	},
 }
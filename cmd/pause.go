@@ -24,7 +24,6 @@ import (
 	"context"
 
 	"github.com/loadimpact/k6/api/v1"
-	"github.com/loadimpact/k6/api/v1/client"
 	"github.com/loadimpact/k6/ui"
 	"github.com/spf13/cobra"
 	"gopkg.in/guregu/null.v3"
@@ -38,7 +37,7 @@ var pauseCmd = &cobra.Command{
 
   Use the global --address flag to specify the URL to the API server.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		c, err := client.New(address)
+		c, err := newAPIClient()
 		if err != nil {
 			return err
 		}
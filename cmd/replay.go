@@ -0,0 +1,272 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/loadimpact/k6/core"
+	"github.com/loadimpact/k6/lib"
+	"github.com/loadimpact/k6/lib/metrics"
+	"github.com/loadimpact/k6/loader"
+	"github.com/loadimpact/k6/stats"
+	jsonc "github.com/loadimpact/k6/stats/json"
+	"github.com/loadimpact/k6/ui"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+	"github.com/spf13/afero"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// replayCmd represents the replay command
+var replayCmd = &cobra.Command{
+	Use:     "replay [results] [script]",
+	Aliases: []string{"report"},
+	Short:   "Re-aggregate the results of a previous test run",
+	Long: `Re-aggregate the results of a previous test run.
+
+Reads the samples saved with "--out json" and replays them through the metric
+aggregation and the thresholds, printing the end-of-test summary and exiting
+with the same code the original run would have. If a script or an archive is
+given, its options (e.g. thresholds) are used, and the replayed samples can also
+be sent to any of the usual outputs.`,
+	Example: `
+  # Recompute the summary with different trend stats.
+  k6 replay --summary-trend-stats="avg,p(99),p(99.9)" results.json
+
+  # Re-evaluate the thresholds defined in the script.
+  k6 replay results.json script.js
+
+  # Send previously recorded results to an influxdb server.
+  k6 replay -o influxdb=http://1.2.3.4:8086/k6 results.json`[1:],
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var r lib.Runner
+		if len(args) > 1 {
+			pwd, err := os.Getwd()
+			if err != nil {
+				return err
+			}
+			filesystems := loader.CreateFilesystems()
+			src, err := loader.ReadSource(args[1], pwd, filesystems, os.Stdin)
+			if err != nil {
+				return err
+			}
+			runtimeOptions, err := getRuntimeOptions(cmd.Flags())
+			if err != nil {
+				return err
+			}
+			if r, err = newRunner(src, runType, filesystems, runtimeOptions); err != nil {
+				return err
+			}
+		}
+
+		cliConf, err := getConfig(cmd.Flags())
+		if err != nil {
+			return err
+		}
+		conf, err := getConsolidatedConfig(afero.NewOsFs(), cliConf, r)
+		if err != nil {
+			return err
+		}
+
+		if len(conf.SummaryTrendStats) > 0 {
+			ui.UpdateTrendColumns(conf.SummaryTrendStats)
+		}
+
+		var in io.Reader = os.Stdin
+		if args[0] != "-" {
+			f, err := os.Open(args[0])
+			if err != nil {
+				return err
+			}
+			defer func() { _ = f.Close() }()
+			in = f
+		}
+
+		// The engine is never started, it's only used for its metric and threshold processing.
+		engine, err := core.NewEngine(nil, conf.Options)
+		if err != nil {
+			return err
+		}
+		if conf.NoThresholds.Valid {
+			engine.NoThresholds = conf.NoThresholds.Bool
+		}
+		if conf.NoSummary.Valid {
+			engine.NoSummary = conf.NoSummary.Bool
+		}
+		for _, out := range conf.Out {
+			t, arg := parseCollector(out)
+			if t == collectorCloud {
+				return errors.New("results can't be replayed to the cloud output")
+			}
+			collector, err := newCollector(t, arg, nil, conf)
+			if err != nil {
+				return err
+			}
+			if err := collector.Init(); err != nil {
+				return err
+			}
+			engine.Collectors = append(engine.Collectors, collector)
+		}
+
+		root, err := lib.NewGroup("", nil)
+		if err != nil {
+			return err
+		}
+
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		sigC := make(chan os.Signal, 1)
+		signal.Notify(sigC, os.Interrupt, syscall.SIGINT, syscall.SIGTERM)
+		defer signal.Stop(sigC)
+		go func() {
+			select {
+			case sig := <-sigC:
+				log.WithField("sig", sig).Debug("Exiting in response to signal")
+				cancel()
+			case <-ctx.Done():
+			}
+		}()
+
+		samples := make(chan stats.SampleContainer, conf.MetricSamplesBufferSize.Int64)
+		errC := make(chan error, 1)
+		go func() {
+			defer close(samples)
+			errC <- readReplaySamples(ctx, jsonc.NewReader(in), root, samples)
+		}()
+
+		t := engine.Replay(ctx, samples)
+		if err := <-errC; err != nil {
+			return errors.Wrap(err, args[0])
+		}
+
+		if !conf.NoSummary.Bool {
+			fprintf(stdout, "\n")
+			ui.Summarize(stdout, "", ui.SummaryData{
+				Opts:    conf.Options,
+				Root:    root,
+				Metrics: engine.Metrics,
+				Time:    t,
+				Events:  engine.GetEvents(),
+
+				IterationErrors: engine.GetIterationErrors(),
+			})
+			fprintf(stdout, "\n")
+		}
+
+		if engine.IsTainted() {
+			return ExitCode{errors.New("some thresholds have failed"), thresholdHaveFailedErroCode}
+		}
+		return nil
+	},
+}
+
+// readReplaySamples pushes all samples and events from the reader into out, rebuilding the group
+// and check tree from the check samples along the way, since it isn't otherwise saved in the results.
+func readReplaySamples(ctx context.Context, r *jsonc.Reader, root *lib.Group, out chan<- stats.SampleContainer) error {
+	sentEvents := 0
+	for {
+		sample, err := r.Next()
+
+		// The events read on the way to the sample (or the end) come before it
+		for events := r.Events(); sentEvents < len(events); sentEvents++ {
+			select {
+			case out <- events[sentEvents]:
+			case <-ctx.Done():
+				return nil
+			}
+		}
+
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if sample.Metric.Name == metrics.Checks.Name {
+			if err := replayCheck(root, sample); err != nil {
+				return err
+			}
+		}
+
+		select {
+		case out <- sample:
+		case <-ctx.Done():
+			return nil
+		}
+	}
+}
+
+func replayCheck(root *lib.Group, sample stats.Sample) error {
+	name, ok := sample.Tags.Get("check")
+	if !ok {
+		return nil
+	}
+
+	group := root
+	path, _ := sample.Tags.Get("group")
+	for _, part := range strings.Split(path, lib.GroupSeparator) {
+		if part == "" {
+			continue
+		}
+		g, err := group.Group(part)
+		if err != nil {
+			return err
+		}
+		group = g
+	}
+
+	check, err := group.Check(name)
+	if err != nil {
+		return err
+	}
+	if sample.Value != 0 {
+		check.Passes++
+	} else {
+		check.Fails++
+	}
+	return nil
+}
+
+func replayCmdFlagSet() *pflag.FlagSet {
+	flags := pflag.NewFlagSet("", pflag.ContinueOnError)
+	flags.SortFlags = false
+	flags.AddFlagSet(optionFlagSet())
+	flags.AddFlagSet(runtimeOptionFlagSet(false))
+	flags.AddFlagSet(configFlagSet())
+	flags.StringVarP(&runType, "type", "t", runType, "override script file `type`, \"js\" or \"archive\"")
+	flags.Lookup("type").DefValue = ""
+	return flags
+}
+
+func init() {
+	RootCmd.AddCommand(replayCmd)
+	replayCmd.Flags().SortFlags = false
+	replayCmd.Flags().AddFlagSet(replayCmdFlagSet())
+}
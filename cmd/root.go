@@ -23,15 +23,20 @@ package cmd
 import (
 	"fmt"
 	"io"
+	"io/ioutil"
 	golog "log"
 	"os"
 	"path/filepath"
+	"strings"
 	"sync"
 
 	"github.com/fatih/color"
+	"github.com/loadimpact/k6/js/compiler"
 	"github.com/loadimpact/k6/lib/consts"
+	"github.com/loadimpact/k6/lib/logging"
 	"github.com/mattn/go-colorable"
 	"github.com/mattn/go-isatty"
+	"github.com/pkg/errors"
 	"github.com/shibukawa/configdir"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/cobra"
@@ -58,11 +63,21 @@ var configFilePath = os.Getenv("K6_CONFIG") // Overridden by `-c`/`--config` fla
 
 var (
 	//TODO: have environment variables for configuring these? hopefully after we move away from global vars though...
-	verbose bool
-	quiet   bool
-	noColor bool
-	logFmt  string
-	address string
+	verbose   bool
+	quiet     bool
+	noColor   bool
+	logFmt    string
+	logOutput string
+	address   string
+)
+
+// logOutputCloser flushes and closes the log output that was set up by setupLoggers(), if needed.
+var logOutputCloser func() error //nolint:gochecknoglobals
+
+//nolint:gochecknoglobals
+var (
+	apiAuth = os.Getenv("K6_API_AUTH")
+	apiCA   = os.Getenv("K6_API_CA")
 )
 
 // RootCmd represents the base command when called without any subcommands.
@@ -72,20 +87,30 @@ var RootCmd = &cobra.Command{
 	Long:          BannerColor.Sprintf("\n%s", consts.Banner),
 	SilenceUsage:  true,
 	SilenceErrors: true,
-	PersistentPreRun: func(cmd *cobra.Command, args []string) {
-		setupLoggers(logFmt)
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
 		if noColor {
 			stdout.Writer = colorable.NewNonColorable(os.Stdout)
 			stderr.Writer = colorable.NewNonColorable(os.Stderr)
 		}
+		if err := setupLoggers(logFmt, logOutput); err != nil {
+			return err
+		}
 		golog.SetOutput(log.StandardLogger().Writer())
+		return nil
 	},
 }
 
 // Execute adds all child commands to the root command sets flags appropriately.
 // This is called by main.main(). It only needs to happen once to the rootCmd.
 func Execute() {
-	if err := RootCmd.Execute(); err != nil {
+	err := RootCmd.Execute()
+	if logOutputCloser != nil {
+		if cerr := logOutputCloser(); cerr != nil {
+			fprintf(stderr, "%s\n", cerr)
+		}
+		log.SetOutput(stderr)
+	}
+	if err != nil {
 		log.Error(err.Error())
 		if e, ok := err.(ExitCode); ok {
 			os.Exit(e.Code)
@@ -100,8 +125,16 @@ func rootCmdPersistentFlagSet() *pflag.FlagSet {
 	flags.BoolVarP(&verbose, "verbose", "v", false, "enable debug logging")
 	flags.BoolVarP(&quiet, "quiet", "q", false, "disable progress updates")
 	flags.BoolVar(&noColor, "no-color", false, "disable colored output")
+	flags.StringVar(&logOutput, "log-output", "stderr",
+		"where to send the logs, \"stderr\", \"file=path\", \"loki=url\" or \"syslog\"")
+	flags.StringVar(&logFmt, "log-format", "", "log output format, \"text\", \"json\" or \"raw\"")
 	flags.StringVar(&logFmt, "logformat", "", "log output format")
+	must(flags.MarkDeprecated("logformat", "use --log-format instead"))
 	flags.StringVarP(&address, "address", "a", "localhost:6565", "address for the api server")
+	flags.StringVar(&apiAuth, "api-auth", apiAuth, "api server credentials, a token or `user:password`")
+	flags.Lookup("api-auth").DefValue = ""
+	flags.StringVar(&apiCA, "api-ca", apiCA, "CA certificate `file` to verify an https api server with")
+	flags.Lookup("api-ca").DefValue = ""
 
 	//TODO: Fix... This default value needed, so both CLI flags and environment variables work
 	flags.StringVarP(&configFilePath, "config", "c", configFilePath, "JSON config file")
@@ -120,6 +153,9 @@ func init() {
 	if len(configFolders) > 0 {
 		defaultConfigFilePath = filepath.Join(configFolders[0].Path, defaultConfigFileName)
 	}
+	if cacheFolder := configDirs.QueryCacheFolder(); cacheFolder != nil {
+		compiler.CacheDir = filepath.Join(cacheFolder.Path, "babel")
+	}
 
 	RootCmd.PersistentFlags().AddFlagSet(rootCmdPersistentFlagSet())
 }
@@ -133,30 +169,71 @@ func fprintf(w io.Writer, format string, a ...interface{}) (n int) {
 	return n
 }
 
-// RawFormatter it does nothing with the message just prints it
-type RawFormater struct{}
-
-// Format renders a single log entry
-func (f RawFormater) Format(entry *log.Entry) ([]byte, error) {
-	return append([]byte(entry.Message), '\n'), nil
-}
-
-func setupLoggers(logFmt string) {
+// setupLoggers configures the format and the output of the standard logger, which is used by the
+// engine, and also by the console of the scripts unless it's redirected with --console-output.
+func setupLoggers(logFmt, logOutput string) error {
 	if verbose {
 		log.SetLevel(log.DebugLevel)
 	}
-	log.SetOutput(stderr)
 
+	output, arg := logOutput, ""
+	if i := strings.IndexByte(logOutput, '='); i >= 0 {
+		output, arg = logOutput[:i], logOutput[i+1:]
+	}
+
+	var formatter log.Formatter
 	switch logFmt {
 	case "raw":
-		log.SetFormatter(&RawFormater{})
-		log.Debug("Logger format: RAW")
+		formatter = &logging.RawFormatter{}
 	case "json":
-		log.SetFormatter(&log.JSONFormatter{})
-		log.Debug("Logger format: JSON")
+		formatter = &log.JSONFormatter{}
+	case "text", "":
+		if output == "stderr" {
+			formatter = &log.TextFormatter{ForceColors: stderrTTY, DisableColors: noColor}
+		} else {
+			formatter = &log.TextFormatter{DisableColors: true}
+		}
+	default:
+		return errors.Errorf("unsupported log format '%s'", logFmt)
+	}
+	log.SetFormatter(formatter)
+
+	switch output {
+	case "stderr":
+		log.SetOutput(stderr)
+	case "file":
+		if arg == "" {
+			return errors.New("the file log output needs a path, e.g. file=k6.log")
+		}
+		f, err := os.OpenFile(arg, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0644)
+		if err != nil {
+			return err
+		}
+		log.SetOutput(f)
+		logOutputCloser = f.Close
+	case "loki":
+		if arg == "" {
+			return errors.New("the loki log output needs a URL, e.g. loki=http://localhost:3100")
+		}
+		hook, err := logging.NewLokiHook(arg, formatter)
+		if err != nil {
+			return err
+		}
+		log.AddHook(hook)
+		log.SetOutput(ioutil.Discard)
+		logOutputCloser = hook.Close
+	case "syslog":
+		hook, err := logging.NewSyslogHook(formatter)
+		if err != nil {
+			return err
+		}
+		log.AddHook(hook)
+		log.SetOutput(ioutil.Discard)
+		logOutputCloser = hook.Close
 	default:
-		log.SetFormatter(&log.TextFormatter{ForceColors: stderrTTY, DisableColors: noColor})
-		log.Debug("Logger format: TEXT")
+		return errors.Errorf("unsupported log output '%s'", logOutput)
 	}
 
+	log.WithFields(log.Fields{"format": logFmt, "output": output}).Debug("Logger configured")
+	return nil
 }
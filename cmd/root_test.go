@@ -0,0 +1,65 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package cmd
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetupLoggers(t *testing.T) {
+	defer func() {
+		log.SetOutput(stderr)
+		log.SetFormatter(&log.TextFormatter{})
+		logOutputCloser = nil
+	}()
+
+	for _, tc := range [][2]string{
+		{"xml", "stderr"},
+		{"json", "console"},
+		{"json", "file"},
+		{"json", "loki"},
+		{"json", "loki=localhost:3100"},
+	} {
+		assert.Error(t, setupLoggers(tc[0], tc[1]), "%s %s", tc[0], tc[1])
+	}
+
+	dir, err := ioutil.TempDir("", "k6-log")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	path := filepath.Join(dir, "k6.log")
+
+	require.NoError(t, setupLoggers("json", "file="+path))
+	log.WithField("vu", 1).Warn("hello")
+	require.NotNil(t, logOutputCloser)
+	require.NoError(t, logOutputCloser())
+
+	data, err := ioutil.ReadFile(path)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"level":"warning","msg":"hello"`)
+	assert.Contains(t, string(data), `"vu":1`)
+}
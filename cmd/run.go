@@ -26,6 +26,7 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"net"
 	"net/http"
 	"os"
 	"os/signal"
@@ -68,6 +69,13 @@ var (
 	runType       = os.Getenv("K6_TYPE")
 	runNoSetup    = os.Getenv("K6_NO_SETUP") != ""
 	runNoTeardown = os.Getenv("K6_NO_TEARDOWN") != ""
+	runDashboard  = os.Getenv("K6_DASHBOARD") != ""
+
+	runWaitForStart = os.Getenv("K6_WAIT_FOR_START") != ""
+
+	runAPIReadAuth = os.Getenv("K6_API_READ_AUTH")
+	runAPITLSCert  = os.Getenv("K6_API_TLS_CERT")
+	runAPITLSKey   = os.Getenv("K6_API_TLS_KEY")
 )
 
 // runCmd represents the run command.
@@ -77,7 +85,14 @@ var runCmd = &cobra.Command{
 	Long: `Start a load test.
 
 This also exposes a REST API to interact with it. Various k6 subcommands offer
-a commandline interface for interacting with it.`,
+a commandline interface for interacting with it.
+
+With --wait-for-start, k6 initializes the VUs and then waits for the test to be
+started through the REST API, with a POST to /v1/start, which can also schedule
+the start at an exact time. setup() and teardown() aren't run automatically in
+this mode, they're left to whatever drives the test, through the /v1/setup and
+/v1/teardown endpoints, and k6 keeps running after the test is done, so its
+/v1/summary can be fetched, until it's interrupted with a signal.`,
 	Example: `
   # Run a single VU, once.
   k6 run script.js
@@ -194,13 +209,40 @@ a commandline interface for interacting with it.`,
 		if runNoTeardown {
 			ex.SetRunTeardown(false)
 		}
+		if runWaitForStart {
+			ex.SetRunSetup(false)
+			ex.SetRunTeardown(false)
+		}
 
-		// Create an engine.
+		// Create an engine, which initializes the VUs. Since that can take a while for heavy
+		// scripts, show how many of them are ready in the meantime.
 		fprintf(stdout, "%s   engine\r", initBar.String())
+		initVUsDone, initVUsProgressDone := make(chan struct{}), make(chan struct{})
+		go func() {
+			defer close(initVUsProgressDone)
+			bar := initBar
+			ticker := time.NewTicker(100 * time.Millisecond)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-initVUsDone:
+					return
+				case <-ticker.C:
+					done, total := ex.GetInitProgress()
+					if total > 0 {
+						bar.Progress = float64(done) / float64(total)
+						fprintf(stdout, "%s   engine (%d/%d VUs)\r", bar.String(), done, total)
+					}
+				}
+			}
+		}()
 		engine, err := core.NewEngine(ex, conf.Options)
+		close(initVUsDone)
+		<-initVUsProgressDone
 		if err != nil {
 			return err
 		}
+		fprintf(stdout, "%s   engine%20s\r", initBar.String(), "")
 
 		// Configure the engine.
 		if conf.NoThresholds.Valid {
@@ -209,6 +251,7 @@ a commandline interface for interacting with it.`,
 		if conf.NoSummary.Valid {
 			engine.NoSummary = conf.NoSummary.Bool
 		}
+		engine.WaitForStart = runWaitForStart
 
 		// Create a collector and assign it to the engine if requested.
 		fprintf(stdout, "%s   collector\r", initBar.String())
@@ -226,8 +269,18 @@ a commandline interface for interacting with it.`,
 
 		// Create an API server.
 		fprintf(stdout, "%s   server\r", initBar.String())
+		apiOpts := api.Options{
+			Dashboard:   runDashboard,
+			ControlAuth: apiAuth,
+			ReadAuth:    runAPIReadAuth,
+			TLSCert:     runAPITLSCert,
+			TLSKey:      runAPITLSKey,
+		}
+		if err := apiOpts.Validate(); err != nil {
+			return err
+		}
 		go func() {
-			if err := api.ListenAndServe(address, engine); err != nil {
+			if err := api.ListenAndServe(address, engine, apiOpts); err != nil {
 				log.WithError(err).Warn("Error from API server")
 			}
 		}()
@@ -253,6 +306,9 @@ a commandline interface for interacting with it.`,
 			fprintf(stdout, "  execution: %s\n", ui.ValueColor.Sprint("local"))
 			fprintf(stdout, "     output: %s%s\n", ui.ValueColor.Sprint(out), ui.ExtraColor.Sprint(link))
 			fprintf(stdout, "     script: %s\n", ui.ValueColor.Sprint(filename))
+			if runDashboard {
+				fprintf(stdout, "  dashboard: %s\n", ui.ValueColor.Sprint(dashboardURL(address, apiOpts.TLSCert != "")))
+			}
 			fprintf(stdout, "\n")
 
 			duration := ui.GrayColor.Sprint("-")
@@ -283,6 +339,9 @@ a commandline interface for interacting with it.`,
 		ctx, cancel := context.WithCancel(context.Background())
 		errC := make(chan error)
 		go func() { errC <- engine.Run(ctx) }()
+		if runWaitForStart {
+			log.Info("Waiting for the test to be started through the API...")
+		}
 
 		// Trap Interrupts, SIGINTs and SIGTERMs.
 		sigC := make(chan os.Signal, 1)
@@ -322,11 +381,19 @@ a commandline interface for interacting with it.`,
 		progress := ui.ProgressBar{
 			Width: 60,
 			Left: func() string {
-				if engine.Executor.IsPaused() {
-					return "  paused"
-				} else if engine.Executor.IsRunning() {
+				switch engine.GetRunStatus() {
+				case lib.RunStatusInitializing, lib.RunStatusQueued:
+					return " waiting"
+				case lib.RunStatusSetup:
+					return "   setup"
+				case lib.RunStatusTeardown:
+					return "teardown"
+				case lib.RunStatusRunning:
+					if engine.Executor.IsPaused() {
+						return "  paused"
+					}
 					return " running"
-				} else {
+				default:
 					return "    done"
 				}
 			},
@@ -336,12 +403,7 @@ a commandline interface for interacting with it.`,
 				}
 				precision := 100 * time.Millisecond
 				atT := engine.Executor.GetTime()
-				stagesEndT := lib.SumStages(engine.Executor.GetStages())
-				endT := engine.Executor.GetEndTime()
-				if !endT.Valid || (stagesEndT.Valid && endT.Duration > stagesEndT.Duration) {
-					endT = stagesEndT
-				}
-				if endT.Valid {
+				if endT := lib.GetPlannedDuration(engine.Executor); endT.Valid {
 					return fmt.Sprintf("%s / %s",
 						(atT/precision)*precision,
 						(time.Duration(endT.Duration)/precision)*precision,
@@ -373,28 +435,11 @@ a commandline interface for interacting with it.`,
 					if quiet {
 						fn = l.Debug
 					}
-					if engine.Executor.IsPaused() {
-						fn("Paused")
-					} else {
-						fn("Running")
-					}
+					fn(strings.Title(strings.TrimSpace(progress.Left())))
 					break
 				}
 
-				var prog float64
-				if endIt := engine.Executor.GetEndIterations(); endIt.Valid {
-					prog = float64(engine.Executor.GetIterations()) / float64(endIt.Int64)
-				} else {
-					stagesEndT := lib.SumStages(engine.Executor.GetStages())
-					endT := engine.Executor.GetEndTime()
-					if !endT.Valid || (stagesEndT.Valid && endT.Duration > stagesEndT.Duration) {
-						endT = stagesEndT
-					}
-					if endT.Valid {
-						prog = float64(engine.Executor.GetTime()) / float64(endT.Duration)
-					}
-				}
-				progress.Progress = prog
+				progress.Progress = lib.GetProgress(engine.Executor)
 				fprintf(stdout, "%s\x1b[0K\r", progress.String())
 			case err := <-errC:
 				cancel()
@@ -403,23 +448,26 @@ a commandline interface for interacting with it.`,
 					break mainLoop
 				}
 
+				var exitErr ExitCode
 				switch e := errors.Cause(err).(type) {
 				case lib.TimeoutError:
 					switch string(e) {
 					case "setup":
 						log.WithError(err).Error("Setup timeout")
-						return ExitCode{errors.New("Setup timeout"), setupTimeoutErrorCode}
+						exitErr = ExitCode{errors.New("Setup timeout"), setupTimeoutErrorCode}
 					case "teardown":
 						log.WithError(err).Error("Teardown timeout")
-						return ExitCode{errors.New("Teardown timeout"), teardownTimeoutErrorCode}
+						exitErr = ExitCode{errors.New("Teardown timeout"), teardownTimeoutErrorCode}
 					default:
 						log.WithError(err).Error("Engine timeout")
-						return ExitCode{errors.New("Engine timeout"), genericTimeoutErrorCode}
+						exitErr = ExitCode{errors.New("Engine timeout"), genericTimeoutErrorCode}
 					}
 				default:
 					log.WithError(err).Error("Engine error")
-					return ExitCode{errors.New("Engine Error"), genericEngineErrorCode}
+					exitErr = ExitCode{errors.New("Engine Error"), genericEngineErrorCode}
 				}
+				engine.SetExitCode(exitErr.Code)
+				return exitErr
 			case sig := <-sigC:
 				log.WithField("sig", sig).Debug("Exiting in response to signal")
 				cancel()
@@ -445,6 +493,12 @@ a commandline interface for interacting with it.`,
 			log.Warn("No data generated, because no script iterations finished, consider making the test duration longer")
 		}
 
+		if engine.IsTainted() {
+			engine.SetExitCode(thresholdHaveFailedErroCode)
+		} else {
+			engine.SetExitCode(0)
+		}
+
 		// Print the end-of-test summary.
 		if !conf.NoSummary.Bool {
 			fprintf(stdout, "\n")
@@ -453,6 +507,9 @@ a commandline interface for interacting with it.`,
 				Root:    engine.Executor.GetRunner().GetDefaultGroup(),
 				Metrics: engine.Metrics,
 				Time:    engine.Executor.GetTime(),
+				Events:  engine.GetEvents(),
+
+				IterationErrors: engine.GetIterationErrors(),
 			})
 			fprintf(stdout, "\n")
 		}
@@ -490,9 +547,37 @@ func runCmdFlagSet() *pflag.FlagSet {
 	flags.Lookup("no-setup").DefValue = falseStr
 	flags.BoolVar(&runNoTeardown, "no-teardown", runNoTeardown, "don't run teardown()")
 	flags.Lookup("no-teardown").DefValue = falseStr
+	flags.BoolVar(&runWaitForStart, "wait-for-start", runWaitForStart,
+		"initialize the VUs, then wait for the test to be started through the api, see above")
+	flags.Lookup("wait-for-start").DefValue = falseStr
+	flags.BoolVar(&runDashboard, "dashboard", runDashboard, "serve a live dashboard from the API server")
+	flags.Lookup("dashboard").DefValue = falseStr
+	flags.StringVar(&runAPIReadAuth, "api-read-auth", runAPIReadAuth,
+		"read-only api server credentials, a token or `user:password` (--api-auth gives full access)")
+	flags.Lookup("api-read-auth").DefValue = ""
+	flags.StringVar(&runAPITLSCert, "api-tls-cert", runAPITLSCert, "certificate `file` to serve the api over https with")
+	flags.Lookup("api-tls-cert").DefValue = ""
+	flags.StringVar(&runAPITLSKey, "api-tls-key", runAPITLSKey, "private key `file` for --api-tls-cert")
+	flags.Lookup("api-tls-key").DefValue = ""
 	return flags
 }
 
+// dashboardURL returns the URL of the dashboard served by the API server listening on addr.
+func dashboardURL(addr string, https bool) string {
+	scheme := "http://"
+	if https {
+		scheme = "https://"
+	}
+	host, port, err := net.SplitHostPort(addr)
+	if err != nil {
+		return scheme + addr + "/dashboard/"
+	}
+	if host == "" || host == "0.0.0.0" || host == "::" {
+		host = "localhost"
+	}
+	return scheme + net.JoinHostPort(host, port) + "/dashboard/"
+}
+
 func init() {
 	RootCmd.AddCommand(runCmd)
 
@@ -0,0 +1,42 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDashboardURL(t *testing.T) {
+	testdata := map[string]string{
+		"localhost:6565": "http://localhost:6565/dashboard/",
+		":6565":          "http://localhost:6565/dashboard/",
+		"0.0.0.0:6565":   "http://localhost:6565/dashboard/",
+		"10.0.0.1:8080":  "http://10.0.0.1:8080/dashboard/",
+		"[::1]:6565":     "http://[::1]:6565/dashboard/",
+		"example.com":    "http://example.com/dashboard/",
+	}
+	for addr, url := range testdata {
+		assert.Equal(t, url, dashboardURL(addr, false), addr)
+	}
+	assert.Equal(t, "https://localhost:6565/dashboard/", dashboardURL(":6565", true))
+}
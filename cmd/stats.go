@@ -22,6 +22,7 @@ package cmd
 
 import (
 	"context"
+	"io"
 
 	"github.com/loadimpact/k6/api/v1/client"
 	"github.com/loadimpact/k6/ui"
@@ -34,12 +35,20 @@ var statsCmd = &cobra.Command{
 	Short: "Show test metrics",
 	Long: `Show test metrics.
 
+  With --watch, the status, the current value of every metric and the state of
+  the thresholds are refreshed until q is pressed, and the test can be paused,
+  resumed and scaled with the keys listed at the bottom of the screen.
+
   Use the global --address flag to specify the URL to the API server.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		c, err := client.New(address)
+		c, err := newAPIClient()
 		if err != nil {
 			return err
 		}
+		if watch, _ := cmd.Flags().GetBool("watch"); watch {
+			interval, _ := cmd.Flags().GetDuration("interval")
+			return runWatch(c, interval, printLiveStats)
+		}
 		metrics, err := c.Metrics(context.Background())
 		if err != nil {
 			return err
@@ -49,6 +58,36 @@ var statsCmd = &cobra.Command{
 	},
 }
 
+// printLiveStats prints a compact overview of the test for the --watch screen.
+func printLiveStats(ctx context.Context, c *client.Client, w io.Writer) error {
+	status, err := c.Status(ctx)
+	if err != nil {
+		return err
+	}
+	printStatusLine(w, status)
+
+	metrics, err := c.Metrics(ctx)
+	if err != nil {
+		return err
+	}
+	fprintf(w, "\n")
+	printMetrics(w, metrics)
+
+	thresholds, err := c.Thresholds(ctx)
+	if err != nil {
+		return err
+	}
+	if len(thresholds) > 0 {
+		fprintf(w, "\nthresholds:\n")
+		for _, ths := range thresholds {
+			fprintf(w, "  ")
+			printThresholds(w, ths)
+		}
+	}
+	return nil
+}
+
 func init() {
 	RootCmd.AddCommand(statsCmd)
+	statsCmd.Flags().AddFlagSet(watchFlagSet())
 }
@@ -22,8 +22,14 @@ package cmd
 
 import (
 	"context"
+	"fmt"
+	"io"
+	"strings"
 
+	"github.com/loadimpact/k6/api/v1"
 	"github.com/loadimpact/k6/api/v1/client"
+	"github.com/loadimpact/k6/lib"
+	"github.com/loadimpact/k6/lib/metrics"
 	"github.com/loadimpact/k6/ui"
 	"github.com/spf13/cobra"
 )
@@ -34,21 +40,74 @@ var statusCmd = &cobra.Command{
 	Short: "Show test status",
 	Long: `Show test status.
 
+  Along with the status, the groups and checks of the test are listed, with how
+  many times each group has run, its duration, the number of HTTP requests made
+  in it and how many times each check has passed and failed.
+
+  With --watch, the output is refreshed until q is pressed, and the test can be
+  paused, resumed and scaled with the keys listed at the bottom of the screen.
+
   Use the global --address flag to specify the URL to the API server.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		c, err := client.New(address)
+		c, err := newAPIClient()
 		if err != nil {
 			return err
 		}
-		status, err := c.Status(context.Background())
-		if err != nil {
-			return err
+		if watch, _ := cmd.Flags().GetBool("watch"); watch {
+			interval, _ := cmd.Flags().GetDuration("interval")
+			return runWatch(c, interval, printStatus)
 		}
-		ui.Dump(stdout, status)
-		return nil
+		return printStatus(context.Background(), c, stdout)
 	},
 }
 
+func printStatus(ctx context.Context, c *client.Client, w io.Writer) error {
+	status, err := c.Status(ctx)
+	if err != nil {
+		return err
+	}
+	ui.Dump(w, status)
+
+	groups, err := c.Groups(ctx)
+	if err != nil {
+		return err
+	}
+	printGroups(w, groups)
+	return nil
+}
+
+// printGroups prints the group tree with the live stats of every group and check, skipping the
+// root group if it's all there is.
+func printGroups(w io.Writer, groups []v1.Group) {
+	if len(groups) == 0 || (len(groups) == 1 && len(groups[0].Checks) == 0) {
+		return
+	}
+
+	fprintf(w, "groups:\n")
+	for _, g := range groups {
+		indent := "  " + strings.Repeat("  ", strings.Count(g.Path, lib.GroupSeparator))
+		if g.Path != "" {
+			var details []string
+			if g.Runs > 0 {
+				details = append(details, fmt.Sprintf("%d runs, avg=%s max=%s", g.Runs,
+					metrics.GroupDuration.HumanizeValue(g.Duration["avg"], ""),
+					metrics.GroupDuration.HumanizeValue(g.Duration["max"], "")))
+			}
+			details = append(details, fmt.Sprintf("%d http reqs", g.HTTPReqs))
+			fprintf(w, "%s%s %s — %s\n", indent[2:], ui.GroupPrefix, g.Name, strings.Join(details, ", "))
+		}
+		for _, c := range g.Checks {
+			mark, color := ui.SuccMark, ui.SuccColor
+			if c.Fails > 0 {
+				mark, color = ui.FailMark, ui.FailColor
+			}
+			fprintf(w, "%s%s ", indent, color.Sprint(mark))
+			fprintf(w, "%s — %s %d / %s %d\n", c.Name, ui.SuccMark, c.Passes, ui.FailMark, c.Fails)
+		}
+	}
+}
+
 func init() {
 	RootCmd.AddCommand(statusCmd)
+	statusCmd.Flags().AddFlagSet(watchFlagSet())
 }
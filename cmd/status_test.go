@@ -0,0 +1,56 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/loadimpact/k6/api/v1"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPrintGroups(t *testing.T) {
+	t.Run("root only", func(t *testing.T) {
+		var buf bytes.Buffer
+		printGroups(&buf, []v1.Group{{Path: "", HTTPReqs: 10}})
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("tree", func(t *testing.T) {
+		var buf bytes.Buffer
+		printGroups(&buf, []v1.Group{
+			{Path: "", HTTPReqs: 10, Checks: []v1.Check{{Name: "is up", Passes: 5}}},
+			{
+				Path: "::login", Name: "login", Runs: 4, HTTPReqs: 8,
+				Duration: map[string]float64{"avg": 250, "max": 1500},
+				Checks:   []v1.Check{{Name: "logged in", Passes: 3, Fails: 1}},
+			},
+			{Path: "::login::form", Name: "form", HTTPReqs: 0},
+		})
+		assert.Equal(t, `groups:
+  ✓ is up — ✓ 5 / ✗ 0
+  █ login — 4 runs, avg=250ms max=1.5s, 8 http reqs
+    ✗ logged in — ✓ 3 / ✗ 1
+    █ form — 0 http reqs
+`, buf.String())
+	})
+}
@@ -0,0 +1,126 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/loadimpact/k6/api/v1"
+	"github.com/pkg/errors"
+	"github.com/spf13/cobra"
+	"gopkg.in/guregu/null.v3"
+)
+
+// tagsCmd represents the tags command
+var tagsCmd = &cobra.Command{
+	Use:   "tags",
+	Short: "Show or change the tags added to the samples of a running test",
+	Long: `Show or change the tags added to the samples of a running test.
+
+  The tags are added to all samples emitted from then on, unless they already
+  have a tag with the same name.
+
+  Use the global --address flag to specify the URL to the API server.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := newAPIClient()
+		if err != nil {
+			return err
+		}
+		tags, err := c.Tags(context.Background())
+		if err != nil {
+			return err
+		}
+		printTags(stdout, tags)
+		return nil
+	},
+}
+
+var tagsSetCmd = &cobra.Command{
+	Use:     "set NAME=VALUE...",
+	Short:   "Add or change tags",
+	Example: "  k6 tags set phase=peak build=1234",
+	Args:    cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		patch, err := parseTagArgs(args)
+		if err != nil {
+			return err
+		}
+		return updateTags(patch)
+	},
+}
+
+var tagsUnsetCmd = &cobra.Command{
+	Use:   "unset NAME...",
+	Short: "Remove tags",
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		patch := v1.Tags{Tags: make(map[string]null.String, len(args))}
+		for _, name := range args {
+			patch.Tags[name] = null.String{}
+		}
+		return updateTags(patch)
+	},
+}
+
+func parseTagArgs(args []string) (v1.Tags, error) {
+	patch := v1.Tags{Tags: make(map[string]null.String, len(args))}
+	for _, arg := range args {
+		kv := strings.SplitN(arg, "=", 2)
+		if len(kv) != 2 || kv[0] == "" {
+			return patch, errors.Errorf("invalid tag '%s', expected NAME=VALUE", arg)
+		}
+		patch.Tags[kv[0]] = null.StringFrom(kv[1])
+	}
+	return patch, nil
+}
+
+func updateTags(patch v1.Tags) error {
+	c, err := newAPIClient()
+	if err != nil {
+		return err
+	}
+	tags, err := c.UpdateTags(context.Background(), patch)
+	if err != nil {
+		return err
+	}
+	printTags(stdout, tags)
+	return nil
+}
+
+func printTags(w io.Writer, tags v1.Tags) {
+	names := make([]string, 0, len(tags.Tags))
+	for name := range tags.Tags {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fprintf(w, "%s=%s\n", name, tags.Tags[name].String)
+	}
+}
+
+func init() {
+	RootCmd.AddCommand(tagsCmd)
+	tagsCmd.AddCommand(tagsSetCmd, tagsUnsetCmd)
+}
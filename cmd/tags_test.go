@@ -0,0 +1,44 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package cmd
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v3"
+)
+
+func TestParseTagArgs(t *testing.T) {
+	patch, err := parseTagArgs([]string{"phase=peak", "query=a=b", "empty="})
+	require.NoError(t, err)
+	assert.Equal(t, map[string]null.String{
+		"phase": null.StringFrom("peak"),
+		"query": null.StringFrom("a=b"),
+		"empty": null.StringFrom(""),
+	}, patch.Tags)
+
+	for _, arg := range []string{"phase", "=peak"} {
+		_, err := parseTagArgs([]string{arg})
+		assert.Error(t, err, arg)
+	}
+}
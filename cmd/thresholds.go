@@ -0,0 +1,115 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package cmd
+
+import (
+	"context"
+	"io"
+	"strings"
+
+	"github.com/loadimpact/k6/api/v1"
+	"github.com/loadimpact/k6/stats"
+	"github.com/spf13/cobra"
+)
+
+// thresholdsCmd represents the thresholds command
+var thresholdsCmd = &cobra.Command{
+	Use:   "thresholds",
+	Short: "Show or change the thresholds of a running test",
+	Long: `Show or change the thresholds of a running test.
+
+  Use the global --address flag to specify the URL to the API server.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := newAPIClient()
+		if err != nil {
+			return err
+		}
+		thresholds, err := c.Thresholds(context.Background())
+		if err != nil {
+			return err
+		}
+		for _, ths := range thresholds {
+			printThresholds(stdout, ths)
+		}
+		return nil
+	},
+}
+
+var thresholdsSetCmd = &cobra.Command{
+	Use:   "set METRIC EXPRESSION...",
+	Short: "Set the thresholds of a metric",
+	Long: `Set the thresholds of a metric, replacing any it already has.
+
+  The metric can also be a submetric, e.g. "http_req_duration{status:200}".`,
+	Example: `
+  k6 thresholds set http_req_duration "p(95)<500" "avg<200"`[1:],
+	Args: cobra.MinimumNArgs(2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ths, err := stats.NewThresholds(args[1:])
+		if err != nil {
+			return err
+		}
+		c, err := newAPIClient()
+		if err != nil {
+			return err
+		}
+		set, err := c.SetThresholds(context.Background(), v1.Thresholds{Metric: args[0], Thresholds: ths})
+		if err != nil {
+			return err
+		}
+		printThresholds(stdout, set)
+		return nil
+	},
+}
+
+var thresholdsDeleteCmd = &cobra.Command{
+	Use:   "delete METRIC",
+	Short: "Remove the thresholds of a metric",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		c, err := newAPIClient()
+		if err != nil {
+			return err
+		}
+		return c.DeleteThresholds(context.Background(), args[0])
+	},
+}
+
+func printThresholds(w io.Writer, ths v1.Thresholds) {
+	sources := make([]string, len(ths.Thresholds.Thresholds))
+	for i, th := range ths.Thresholds.Thresholds {
+		sources[i] = th.Source
+	}
+	state := ""
+	if ths.Tainted.Valid {
+		state = " (passing)"
+		if ths.Tainted.Bool {
+			state = " (failing)"
+		}
+	}
+	fprintf(w, "%s: %s%s\n", ths.Metric, strings.Join(sources, ", "), state)
+}
+
+func init() {
+	RootCmd.AddCommand(thresholdsCmd)
+	thresholdsCmd.AddCommand(thresholdsSetCmd, thresholdsDeleteCmd)
+}
@@ -0,0 +1,209 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/loadimpact/k6/api/v1"
+	"github.com/loadimpact/k6/api/v1/client"
+	"github.com/loadimpact/k6/stats"
+	"github.com/loadimpact/k6/ui"
+	"github.com/pkg/errors"
+	"github.com/spf13/pflag"
+	"golang.org/x/crypto/ssh/terminal"
+	"gopkg.in/guregu/null.v3"
+)
+
+const watchHelp = "p pause · r resume · +/- scale by 1 VU · ]/[ scale by 10 VUs · q quit"
+
+// watchRenderer writes a snapshot of the test to w, fetched through the client.
+type watchRenderer func(ctx context.Context, c *client.Client, w io.Writer) error
+
+func watchFlagSet() *pflag.FlagSet {
+	flags := pflag.NewFlagSet("", pflag.ContinueOnError)
+	flags.BoolP("watch", "w", false, "keep refreshing the output, with keyboard shortcuts to control the test")
+	flags.Duration("interval", time.Second, "how often to refresh the output with --watch")
+	return flags
+}
+
+// runWatch takes over the terminal and redraws the output of render on every interval, until
+// q or Ctrl+C is pressed. Some other keys control the test, see watchHelp.
+func runWatch(c *client.Client, interval time.Duration, render watchRenderer) error {
+	if interval <= 0 {
+		return errors.New("the --watch interval must be positive")
+	}
+	fd := int(os.Stdin.Fd())
+	if !stdoutTTY || !terminal.IsTerminal(fd) {
+		return errors.New("--watch needs an interactive terminal")
+	}
+	oldState, err := terminal.MakeRaw(fd)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = terminal.Restore(fd, oldState) }()
+
+	// The console writer adds its own line endings, which don't work in raw mode
+	screen := ui.Screen{W: stdout.Writer}
+	if err := screen.Open(); err != nil {
+		return err
+	}
+	defer func() { _ = screen.Close() }()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	message := ""
+	draw := func() error {
+		var buf bytes.Buffer
+		if err := render(ctx, c, &buf); err != nil {
+			buf.Reset()
+			_, _ = ui.ErrorColor.Fprintf(&buf, "Error: %s\n", err)
+		}
+		fprintf(&buf, "\n%s\n", ui.ExtraColor.Sprint(watchHelp))
+		if message != "" {
+			fprintf(&buf, "%s\n", message)
+		}
+		return screen.Draw(buf.String())
+	}
+
+	keys := ui.ReadKeys(os.Stdin)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		if err := draw(); err != nil {
+			return err
+		}
+		select {
+		case <-ticker.C:
+		case key, ok := <-keys:
+			if !ok || key == 'q' || key == 3 || key == 4 { // Ctrl+C and Ctrl+D don't send signals in raw mode
+				return nil
+			}
+			message = handleWatchKey(ctx, c, key)
+		}
+	}
+}
+
+// handleWatchKey changes the test according to the pressed key, and returns a message saying
+// what happened, or nothing if the key doesn't do anything.
+func handleWatchKey(ctx context.Context, c *client.Client, key byte) string {
+	var patch v1.Status
+	switch key {
+	case 'p':
+		patch.Paused = null.BoolFrom(true)
+	case 'r':
+		patch.Paused = null.BoolFrom(false)
+	case '+', '-', ']', '[':
+		delta := map[byte]int64{'+': 1, '-': -1, ']': 10, '[': -10}[key]
+		status, err := c.Status(ctx)
+		if err != nil {
+			return ui.ErrorColor.Sprintf("Error: %s", err)
+		}
+		vus := status.VUs.Int64 + delta
+		if vus < 0 {
+			vus = 0
+		}
+		patch.VUs = null.IntFrom(vus)
+	default:
+		return ""
+	}
+
+	status, err := c.SetStatus(ctx, patch)
+	if err != nil {
+		return ui.ErrorColor.Sprintf("Error: %s", err)
+	}
+	switch {
+	case patch.Paused.Valid && status.Paused.Bool:
+		return "Paused the test"
+	case patch.Paused.Valid:
+		return "Resumed the test"
+	default:
+		return fmt.Sprintf("Scaled to %d VUs", status.VUs.Int64)
+	}
+}
+
+// printStatusLine prints a one-line summary of the status.
+func printStatusLine(w io.Writer, status v1.Status) {
+	state := strings.Replace(status.RunStatus, "_", " ", -1)
+	if status.Paused.Bool && status.Running {
+		state = "paused"
+	}
+	thresholds := ui.SuccColor.Sprint(ui.SuccMark + " thresholds passing")
+	if status.Tainted {
+		thresholds = ui.FailColor.Sprint(ui.FailMark + " thresholds failing")
+	}
+	fprintf(w, "%s · %s/%s VUs · %s\n", ui.ValueColor.Sprint(state),
+		ui.ValueColor.Sprint(status.VUs.Int64), ui.ValueColor.Sprint(status.VUsMax.Int64), thresholds)
+}
+
+// printMetrics prints the current values of all metrics, one per line and sorted by name, with
+// a mark for the ones that have thresholds.
+func printMetrics(w io.Writer, metrics []v1.Metric) {
+	sort.Slice(metrics, func(i, j int) bool { return metrics[i].Name < metrics[j].Name })
+	width := 0
+	for _, m := range metrics {
+		if l := ui.StrWidth(m.Name); l > width {
+			width = l
+		}
+	}
+
+	for _, m := range metrics {
+		mark := " "
+		if m.Tainted.Valid {
+			mark = ui.SuccColor.Sprint(ui.SuccMark)
+			if m.Tainted.Bool {
+				mark = ui.FailColor.Sprint(ui.FailMark)
+			}
+		}
+		fprintf(w, "%s %s%s %s\n", mark, m.Name, strings.Repeat(".", width-ui.StrWidth(m.Name)+3),
+			formatMetricSample(m))
+	}
+}
+
+// formatMetricSample formats the values of a metric the same way the end-of-test summary does.
+func formatMetricSample(m v1.Metric) string {
+	sm := &stats.Metric{Type: m.Type.Type, Contains: m.Contains.Type}
+	switch m.Type.Type {
+	case stats.Counter:
+		return fmt.Sprintf("%s %s/s", ui.ValueColor.Sprint(sm.HumanizeValue(m.Sample["count"], "")),
+			ui.ExtraColor.Sprint(sm.HumanizeValue(m.Sample["rate"], "")))
+	case stats.Gauge:
+		return ui.ValueColor.Sprint(sm.HumanizeValue(m.Sample["value"], ""))
+	case stats.Rate:
+		return ui.ValueColor.Sprint(sm.HumanizeValue(m.Sample["rate"], ""))
+	case stats.Trend:
+		parts := make([]string, len(ui.TrendColumns))
+		for i, col := range ui.TrendColumns {
+			parts[i] = col.Key + "=" + ui.ValueColor.Sprint(sm.HumanizeValue(m.Sample[col.Key], ""))
+		}
+		return strings.Join(parts, " ")
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,144 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/loadimpact/k6/api/v1"
+	"github.com/loadimpact/k6/api/v1/client"
+	"github.com/loadimpact/k6/stats"
+	"github.com/manyminds/api2go/jsonapi"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/guregu/null.v3"
+)
+
+func TestHandleWatchKey(t *testing.T) {
+	status := v1.Status{Paused: null.BoolFrom(false), VUs: null.IntFrom(5), VUsMax: null.IntFrom(20)}
+	srv := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		if r.Method == "PATCH" {
+			body, err := ioutil.ReadAll(r.Body)
+			require.NoError(t, err)
+			var patch v1.Status
+			require.NoError(t, jsonapi.Unmarshal(body, &patch))
+			if patch.Paused.Valid {
+				status.Paused = patch.Paused
+			}
+			if patch.VUs.Valid {
+				status.VUs = patch.VUs
+			}
+		}
+		data, err := jsonapi.Marshal(status)
+		require.NoError(t, err)
+		_, _ = rw.Write(data)
+	}))
+	defer srv.Close()
+
+	c, err := client.New(srv.URL)
+	require.NoError(t, err)
+
+	testdata := []struct {
+		key     byte
+		message string
+		paused  bool
+		vus     int64
+	}{
+		{'x', "", false, 5},
+		{'p', "Paused the test", true, 5},
+		{'r', "Resumed the test", false, 5},
+		{'+', "Scaled to 6 VUs", false, 6},
+		{']', "Scaled to 16 VUs", false, 16},
+		{'-', "Scaled to 15 VUs", false, 15},
+		{'[', "Scaled to 5 VUs", false, 5},
+		{'[', "Scaled to 0 VUs", false, 0},
+	}
+	for _, data := range testdata {
+		assert.Equal(t, data.message, handleWatchKey(context.Background(), c, data.key), string(data.key))
+		assert.Equal(t, data.paused, status.Paused.Bool, string(data.key))
+		assert.Equal(t, data.vus, status.VUs.Int64, string(data.key))
+	}
+}
+
+func TestFormatMetricSample(t *testing.T) {
+	testdata := map[string]struct {
+		metric   v1.Metric
+		expected string
+	}{
+		"counter": {
+			v1.Metric{
+				Type:   v1.NullMetricType{Type: stats.Counter, Valid: true},
+				Sample: map[string]float64{"count": 10, "rate": 2.5},
+			},
+			"10 2.5/s",
+		},
+		"gauge": {
+			v1.Metric{
+				Type:   v1.NullMetricType{Type: stats.Gauge, Valid: true},
+				Sample: map[string]float64{"value": 3},
+			},
+			"3",
+		},
+		"rate": {
+			v1.Metric{
+				Type:   v1.NullMetricType{Type: stats.Rate, Valid: true},
+				Sample: map[string]float64{"rate": 0.5},
+			},
+			"50.00%",
+		},
+		"trend": {
+			v1.Metric{
+				Type:     v1.NullMetricType{Type: stats.Trend, Valid: true},
+				Contains: v1.NullValueType{Type: stats.Time, Valid: true},
+				Sample: map[string]float64{
+					"avg": 150, "min": 100, "med": 150, "max": 200, "p(90)": 190, "p(95)": 195,
+				},
+			},
+			"avg=150ms min=100ms med=150ms max=200ms p(90)=190ms p(95)=195ms",
+		},
+	}
+	for name, data := range testdata {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, data.expected, formatMetricSample(data.metric))
+		})
+	}
+}
+
+func TestPrintStatusLine(t *testing.T) {
+	var buf bytes.Buffer
+	printStatusLine(&buf, v1.Status{
+		Paused: null.BoolFrom(true), VUs: null.IntFrom(2), VUsMax: null.IntFrom(10), Running: true, RunStatus: "running",
+	})
+	assert.Equal(t, "paused · 2/10 VUs · ✓ thresholds passing\n", buf.String())
+
+	buf.Reset()
+	printStatusLine(&buf, v1.Status{VUs: null.IntFrom(1), VUsMax: null.IntFrom(1), Running: true, Tainted: true, RunStatus: "running"})
+	assert.Equal(t, "running · 1/1 VUs · ✗ thresholds failing\n", buf.String())
+
+	buf.Reset()
+	printStatusLine(&buf, v1.Status{VUs: null.IntFrom(0), VUsMax: null.IntFrom(1), RunStatus: "aborted_by_threshold", Tainted: true})
+	assert.Equal(t, "aborted by threshold · 0/1 VUs · ✗ thresholds failing\n", buf.String())
+}
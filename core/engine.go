@@ -22,6 +22,7 @@ package core
 
 import (
 	"context"
+	"sort"
 	"strings"
 	"sync"
 	"time"
@@ -29,7 +30,10 @@ import (
 	"github.com/loadimpact/k6/core/local"
 	"github.com/loadimpact/k6/lib"
 	"github.com/loadimpact/k6/lib/metrics"
+	"github.com/loadimpact/k6/lib/netext"
+	"github.com/loadimpact/k6/lib/netext/httpext"
 	"github.com/loadimpact/k6/stats"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	"gopkg.in/guregu/null.v3"
 )
@@ -55,8 +59,23 @@ type Engine struct {
 	NoThresholds bool
 	NoSummary    bool
 
+	// If set, Run() doesn't start the executor until Start() is called, e.g. through the API, and
+	// keeps processing samples after it's done, until its context is cancelled. This leaves setup
+	// and teardown to be driven from the outside, around the actual test.
+	WaitForStart bool
+
 	logger *log.Logger
 
+	// Whether Run() has been called, when the executor was (or will be) started, and how and
+	// when the test ended, once it has.
+	initialized bool
+	startTime   time.Time
+	startC      chan struct{}
+	runStatus   lib.RunStatus
+	endTime     time.Time
+	exitCode    null.Int
+	statusLock  sync.RWMutex
+
 	Metrics     map[string]*stats.Metric
 	MetricsLock sync.Mutex
 
@@ -66,8 +85,62 @@ type Engine struct {
 	thresholds map[string]stats.Thresholds
 	submetrics map[string][]*stats.Submetric
 
+	// Added to all samples that don't already have them, see UpdateTags().
+	tags map[string]string
+
+	// All events recorded so far, for the end-of-test summary.
+	events []stats.Event
+
+	// Aggregates of the samples within each group, by the group's path.
+	groupSinks map[string]*groupSinks
+
+	// Number of iterations aborted by each error, from the samples of the errors metric.
+	iterationErrors map[lib.IterationErrorKey]int64
+
 	// Are thresholds tainted?
 	thresholdsTainted bool
+
+	// Subscribers to threshold state changes.
+	thresholdSubs     map[chan ThresholdChange]struct{}
+	thresholdSubsLock sync.Mutex
+}
+
+// ThresholdChange is sent to the subscribers when the thresholds of a metric start or stop failing.
+type ThresholdChange struct {
+	Metric  string
+	Tainted bool
+	Time    time.Duration
+}
+
+// GroupStats are live aggregates of the samples emitted within a group, which is known from their
+// "group" tag, so they're only available if that system tag is enabled.
+type GroupStats struct {
+	// The group's "min", "max" and "avg" durations, nil if it hasn't been run, and how many times
+	// it was run.
+	Duration map[string]float64
+	Count    uint64
+
+	// The number of HTTP requests made directly in the group, not in any of its subgroups.
+	HTTPReqs int64
+}
+
+// groupSinks only keeps bounded aggregates, so that the memory used by a group doesn't grow with
+// the number of times it's run. The full group_duration trend is still in the metric's sink.
+type groupSinks struct {
+	count         uint64
+	min, max, sum float64
+	httpReqs      int64
+}
+
+func (s *groupSinks) addDuration(value float64) {
+	if s.count == 0 || value < s.min {
+		s.min = value
+	}
+	if s.count == 0 || value > s.max {
+		s.max = value
+	}
+	s.sum += value
+	s.count++
 }
 
 func NewEngine(ex lib.Executor, o lib.Options) (*Engine, error) {
@@ -80,6 +153,10 @@ func NewEngine(ex lib.Executor, o lib.Options) (*Engine, error) {
 		Options:  o,
 		Metrics:  make(map[string]*stats.Metric),
 		Samples:  make(chan stats.SampleContainer, o.MetricSamplesBufferSize.Int64),
+		startC:   make(chan struct{}),
+
+		groupSinks:      make(map[string]*groupSinks),
+		iterationErrors: make(map[lib.IterationErrorKey]int64),
 	}
 	e.SetLogger(log.StandardLogger())
 
@@ -94,7 +171,11 @@ func NewEngine(ex lib.Executor, o lib.Options) (*Engine, error) {
 	ex.SetEndTime(o.Duration)
 	ex.SetEndIterations(o.Iterations)
 
-	e.thresholds = o.Thresholds
+	// Copied, since they can be changed while the test is running
+	e.thresholds = make(map[string]stats.Thresholds, len(o.Thresholds))
+	for name, ths := range o.Thresholds {
+		e.thresholds[name] = ths
+	}
 	e.submetrics = make(map[string][]*stats.Submetric)
 	for name := range e.thresholds {
 		if !strings.Contains(name, "{") {
@@ -108,10 +189,16 @@ func NewEngine(ex lib.Executor, o lib.Options) (*Engine, error) {
 	return e, nil
 }
 
+// setRunStatus records how the test ended, unless that's already known, and passes it on to the
+// collectors.
 func (e *Engine) setRunStatus(status lib.RunStatus) {
-	if len(e.Collectors) == 0 {
+	e.statusLock.Lock()
+	defer e.statusLock.Unlock()
+	if e.runStatus.IsFinal() {
 		return
 	}
+	e.runStatus = status
+	e.endTime = time.Now()
 
 	for _, c := range e.Collectors {
 		c.SetRunStatus(status)
@@ -122,6 +209,10 @@ func (e *Engine) Run(ctx context.Context) error {
 	e.runLock.Lock()
 	defer e.runLock.Unlock()
 
+	e.statusLock.Lock()
+	e.initialized = true
+	e.statusLock.Unlock()
+
 	e.logger.Debug("Engine: Starting with parameters...")
 	for i, st := range e.Executor.GetStages() {
 		fields := make(log.Fields)
@@ -180,6 +271,11 @@ func (e *Engine) Run(ctx context.Context) error {
 	errC := make(chan error)
 	subwg.Add(1)
 	go func() {
+		if !e.waitForStart(subctx) {
+			errC <- nil
+			subwg.Done()
+			return
+		}
 		errC <- e.Executor.Run(subctx, e.Samples)
 		e.logger.Debug("Engine: Executor terminated")
 		subwg.Done()
@@ -235,11 +331,20 @@ func (e *Engine) Run(ctx context.Context) error {
 			errC = nil
 			if err != nil {
 				e.logger.WithError(err).Debug("run: executor returned an error")
-				e.setRunStatus(lib.RunStatusAbortedSystem)
+				if _, ok := errors.Cause(err).(lib.TimeoutError); ok {
+					e.setRunStatus(lib.RunStatusTimedOut)
+				} else {
+					// Setup, teardown or the init context of a new VU threw an exception
+					e.setRunStatus(lib.RunStatusAbortedScriptError)
+				}
 				return err
 			}
 			e.logger.Debug("run: executor terminated")
-			return nil
+			e.setRunStatus(lib.RunStatusFinished)
+			if !e.WaitForStart {
+				return nil
+			}
+			// Keep going, so teardown can still be run and the summary fetched through the API
 		case <-ctx.Done():
 			e.logger.Debug("run: context expired; exiting...")
 			e.setRunStatus(lib.RunStatusAbortedUser)
@@ -248,6 +353,175 @@ func (e *Engine) Run(ctx context.Context) error {
 	}
 }
 
+// Replay feeds previously recorded samples through the metric aggregation, the collectors and
+// the thresholds, the same way Run does for samples generated by the executor. It returns when
+// the samples channel is closed or the context is cancelled, along with the time spanned by the
+// replayed samples, which is what thresholds and the end-of-test summary are evaluated against.
+func (e *Engine) Replay(ctx context.Context, samples <-chan stats.SampleContainer) time.Duration {
+	e.runLock.Lock()
+	defer e.runLock.Unlock()
+
+	collectorwg := sync.WaitGroup{}
+	collectorctx, collectorcancel := context.WithCancel(context.Background())
+	for _, collector := range e.Collectors {
+		collectorwg.Add(1)
+		go func(collector lib.Collector) {
+			collector.Run(collectorctx)
+			collectorwg.Done()
+		}(collector)
+	}
+
+	var start, end time.Time
+	sampleContainers := []stats.SampleContainer{}
+	flush := func() {
+		if len(sampleContainers) > 0 {
+			e.processSamples(sampleContainers)
+			sampleContainers = []stats.SampleContainer{}
+		}
+	}
+
+loop:
+	for {
+		select {
+		case sc, ok := <-samples:
+			if !ok {
+				break loop
+			}
+			for _, s := range sc.GetSamples() {
+				if start.IsZero() || s.Time.Before(start) {
+					start = s.Time
+				}
+				if s.Time.After(end) {
+					end = s.Time
+				}
+			}
+			sampleContainers = append(sampleContainers, sc)
+			if len(sampleContainers) >= cap(e.Samples) {
+				flush()
+			}
+		case <-ctx.Done():
+			e.logger.Debug("replay: context expired; exiting...")
+			e.setRunStatus(lib.RunStatusAbortedUser)
+			break loop
+		}
+	}
+	flush()
+
+	t := end.Sub(start)
+	if !e.NoThresholds {
+		e.processThresholdsAt(t, nil)
+	}
+
+	collectorcancel()
+	collectorwg.Wait()
+	return t
+}
+
+// Start schedules the start of a test run with WaitForStart at the given time, or right away if
+// it's zero or in the past.
+func (e *Engine) Start(at time.Time) (time.Time, error) {
+	e.statusLock.Lock()
+	defer e.statusLock.Unlock()
+
+	if !e.WaitForStart {
+		return time.Time{}, errors.New("the test isn't waiting to be started")
+	}
+	if !e.startTime.IsZero() {
+		return e.startTime, errors.New("the test has already been started")
+	}
+	if now := time.Now(); at.Before(now) {
+		at = now
+	}
+	e.startTime = at
+	close(e.startC)
+	return at, nil
+}
+
+// GetStartTime returns when the test was or will be started, if that's known yet.
+func (e *Engine) GetStartTime() (time.Time, bool) {
+	e.statusLock.RLock()
+	defer e.statusLock.RUnlock()
+	return e.startTime, !e.startTime.IsZero()
+}
+
+// IsInitialized returns whether the engine has been started, so its VUs are ready to run the
+// test, even if it's still waiting to be started.
+func (e *Engine) IsInitialized() bool {
+	e.statusLock.RLock()
+	defer e.statusLock.RUnlock()
+	return e.initialized
+}
+
+// GetRunStatus returns the phase the test is in, or how it ended.
+func (e *Engine) GetRunStatus() lib.RunStatus {
+	e.statusLock.RLock()
+	defer e.statusLock.RUnlock()
+	switch {
+	case e.runStatus.IsFinal():
+		return e.runStatus
+	case !e.initialized:
+		return lib.RunStatusInitializing
+	case e.startTime.IsZero() || e.startTime.After(time.Now()):
+		return lib.RunStatusQueued
+	default:
+		if ex, ok := e.Executor.(lib.RunStatusGetter); ok {
+			return ex.GetRunStatus()
+		}
+		return lib.RunStatusRunning
+	}
+}
+
+// GetEndTime returns when the test ended, if it has.
+func (e *Engine) GetEndTime() (time.Time, bool) {
+	e.statusLock.RLock()
+	defer e.statusLock.RUnlock()
+	return e.endTime, !e.endTime.IsZero()
+}
+
+// SetExitCode records the exit code k6 is going to exit with, for anything watching the test
+// through the API, e.g. with --linger.
+func (e *Engine) SetExitCode(code int) {
+	e.statusLock.Lock()
+	defer e.statusLock.Unlock()
+	e.exitCode = null.IntFrom(int64(code))
+}
+
+// GetExitCode returns the exit code k6 is going to exit with, if it's known yet.
+func (e *Engine) GetExitCode() null.Int {
+	e.statusLock.RLock()
+	defer e.statusLock.RUnlock()
+	return e.exitCode
+}
+
+// waitForStart blocks until it's time to start the executor, returning false if the context
+// was cancelled first.
+func (e *Engine) waitForStart(ctx context.Context) bool {
+	if !e.WaitForStart {
+		e.statusLock.Lock()
+		e.startTime = time.Now()
+		e.statusLock.Unlock()
+		return true
+	}
+
+	e.logger.Debug("Engine: Waiting to be started...")
+	select {
+	case <-e.startC:
+	case <-ctx.Done():
+		return false
+	}
+
+	start, _ := e.GetStartTime()
+	e.logger.WithField("at", start).Debug("Engine: Starting the executor")
+	timer := time.NewTimer(time.Until(start))
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
 func (e *Engine) IsTainted() bool {
 	return e.thresholdsTainted
 }
@@ -261,6 +535,288 @@ func (e *Engine) GetLogger() *log.Logger {
 	return e.logger
 }
 
+// SubscribeThresholds returns a channel that receives every threshold state change, and a function
+// that ends the subscription. Changes are dropped if the channel's buffer is full, so a slow
+// subscriber can't hold up the threshold processing.
+func (e *Engine) SubscribeThresholds() (<-chan ThresholdChange, func()) {
+	ch := make(chan ThresholdChange, 100)
+
+	e.thresholdSubsLock.Lock()
+	if e.thresholdSubs == nil {
+		e.thresholdSubs = make(map[chan ThresholdChange]struct{})
+	}
+	e.thresholdSubs[ch] = struct{}{}
+	e.thresholdSubsLock.Unlock()
+
+	return ch, func() {
+		e.thresholdSubsLock.Lock()
+		delete(e.thresholdSubs, ch)
+		e.thresholdSubsLock.Unlock()
+	}
+}
+
+func (e *Engine) publishThresholdChange(change ThresholdChange) {
+	e.thresholdSubsLock.Lock()
+	defer e.thresholdSubsLock.Unlock()
+	for ch := range e.thresholdSubs {
+		select {
+		case ch <- change:
+		default:
+			e.logger.WithField("m", change.Metric).Warn("Threshold change dropped, a subscriber is too slow")
+		}
+	}
+}
+
+// GetThresholds returns the thresholds of all metrics and submetrics, including the ones without
+// any samples yet.
+func (e *Engine) GetThresholds() map[string]stats.Thresholds {
+	e.MetricsLock.Lock()
+	defer e.MetricsLock.Unlock()
+
+	thresholds := make(map[string]stats.Thresholds, len(e.thresholds))
+	for name, ths := range e.thresholds {
+		thresholds[name] = ths
+	}
+	return thresholds
+}
+
+// SetThresholds replaces the thresholds of a metric, or of a submetric like
+// "http_req_duration{status:200}", while the test is running. A new submetric only receives the
+// samples emitted after it was added.
+func (e *Engine) SetThresholds(name string, ths stats.Thresholds) {
+	e.MetricsLock.Lock()
+	defer e.MetricsLock.Unlock()
+
+	e.thresholds[name] = ths
+	if m, ok := e.Metrics[name]; ok {
+		m.Thresholds = ths
+		return
+	}
+
+	parent, sm := stats.NewSubmetric(name)
+	if parent == name {
+		return // the metric will get its thresholds with its first sample
+	}
+	for _, existing := range e.submetrics[parent] {
+		if existing.Name == name {
+			return
+		}
+	}
+	e.submetrics[parent] = append(e.submetrics[parent], sm)
+	if m, ok := e.Metrics[parent]; ok {
+		m.Submetrics = e.submetrics[parent]
+	}
+}
+
+// DeleteThresholds removes the thresholds of a metric or submetric, returning false if it
+// didn't have any. The metric itself, and a submetric's samples, are kept.
+func (e *Engine) DeleteThresholds(name string) bool {
+	e.MetricsLock.Lock()
+	defer e.MetricsLock.Unlock()
+
+	if _, ok := e.thresholds[name]; !ok {
+		return false
+	}
+	delete(e.thresholds, name)
+
+	if m, ok := e.Metrics[name]; ok {
+		if m.Tainted.Bool {
+			e.publishThresholdChange(ThresholdChange{Metric: name, Tainted: false, Time: e.Executor.GetTime()})
+		}
+		m.Thresholds = stats.Thresholds{}
+		m.Tainted = null.Bool{}
+	}
+	return true
+}
+
+// GetTags returns the tags that are added to all samples.
+func (e *Engine) GetTags() map[string]string {
+	e.MetricsLock.Lock()
+	defer e.MetricsLock.Unlock()
+
+	tags := make(map[string]string, len(e.tags))
+	for k, v := range e.tags {
+		tags[k] = v
+	}
+	return tags
+}
+
+// UpdateTags sets and removes tags that are added to all samples processed from now on, unless
+// the samples already have tags with the same names. It returns the resulting tags.
+func (e *Engine) UpdateTags(set map[string]string, remove []string) map[string]string {
+	e.MetricsLock.Lock()
+	tags := make(map[string]string, len(e.tags)+len(set))
+	for k, v := range e.tags {
+		tags[k] = v
+	}
+	for k, v := range set {
+		tags[k] = v
+	}
+	for _, k := range remove {
+		delete(tags, k)
+	}
+	e.tags = tags
+	e.MetricsLock.Unlock()
+
+	return e.GetTags()
+}
+
+// AddEvent records an event, which is passed on to the collectors like any other sample container.
+func (e *Engine) AddEvent(event stats.Event) {
+	e.processSamples([]stats.SampleContainer{event})
+}
+
+// GetEvents returns all events recorded so far, from both the script and the API.
+func (e *Engine) GetEvents() []stats.Event {
+	e.MetricsLock.Lock()
+	defer e.MetricsLock.Unlock()
+
+	events := make([]stats.Event, len(e.events))
+	copy(events, e.events)
+	return events
+}
+
+// GetGroupStats returns the stats of all groups that samples have been seen for, by their paths.
+func (e *Engine) GetGroupStats() map[string]GroupStats {
+	e.MetricsLock.Lock()
+	defer e.MetricsLock.Unlock()
+
+	result := make(map[string]GroupStats, len(e.groupSinks))
+	for path, sinks := range e.groupSinks {
+		gs := GroupStats{Count: sinks.count, HTTPReqs: sinks.httpReqs}
+		if sinks.count > 0 {
+			gs.Duration = map[string]float64{
+				"min": sinks.min,
+				"max": sinks.max,
+				"avg": sinks.sum / float64(sinks.count),
+			}
+		}
+		result[path] = gs
+	}
+	return result
+}
+
+// GetIterationErrors returns the number of iterations aborted by each error so far, the most
+// frequent errors first.
+func (e *Engine) GetIterationErrors() []lib.IterationErrorCount {
+	e.MetricsLock.Lock()
+	defer e.MetricsLock.Unlock()
+
+	result := make([]lib.IterationErrorCount, 0, len(e.iterationErrors))
+	for key, count := range e.iterationErrors {
+		result = append(result, lib.IterationErrorCount{Type: key.Type, Message: key.Message, Count: count})
+	}
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Count != result[j].Count {
+			return result[i].Count > result[j].Count
+		}
+		if result[i].Type != result[j].Type {
+			return result[i].Type < result[j].Type
+		}
+		return result[i].Message < result[j].Message
+	})
+	return result
+}
+
+func (e *Engine) processSamplesForErrors(sampleContainers []stats.SampleContainer) {
+	for _, sc := range sampleContainers {
+		for _, sample := range sc.GetSamples() {
+			if sample.Metric.Name != metrics.Errors.Name {
+				continue
+			}
+			errorType, ok := sample.Tags.Get("error_type")
+			if !ok {
+				continue
+			}
+			message, _ := sample.Tags.Get("error")
+			e.iterationErrors[lib.IterationErrorKey{Type: errorType, Message: message}] += int64(sample.Value)
+		}
+	}
+}
+
+func (e *Engine) processSamplesForGroups(sampleContainers []stats.SampleContainer) {
+	for _, sc := range sampleContainers {
+		for _, sample := range sc.GetSamples() {
+			if sample.Metric.Name != metrics.GroupDuration.Name && sample.Metric.Name != metrics.HTTPReqs.Name {
+				continue
+			}
+			path, ok := sample.Tags.Get("group")
+			if !ok {
+				continue
+			}
+			sinks, ok := e.groupSinks[path]
+			if !ok {
+				sinks = &groupSinks{}
+				e.groupSinks[path] = sinks
+			}
+			if sample.Metric.Name == metrics.GroupDuration.Name {
+				sinks.addDuration(sample.Value)
+			} else {
+				sinks.httpReqs += int64(sample.Value)
+			}
+		}
+	}
+}
+
+// addTags returns copies of the sample containers with the engine's tags added to every sample.
+func (e *Engine) addTags(sampleContainers []stats.SampleContainer) []stats.SampleContainer {
+	// Samples in the same container usually share their tags, so they're only merged once
+	merged := make(map[*stats.SampleTags]*stats.SampleTags)
+	mergeTags := func(tags *stats.SampleTags) *stats.SampleTags {
+		if result, ok := merged[tags]; ok {
+			return result
+		}
+		m := tags.CloneTags()
+		for k, v := range e.tags {
+			if _, ok := m[k]; !ok {
+				m[k] = v
+			}
+		}
+		result := stats.IntoSampleTags(&m)
+		merged[tags] = result
+		return result
+	}
+	mergeSamples := func(samples []stats.Sample) []stats.Sample {
+		result := make([]stats.Sample, len(samples))
+		for i, s := range samples {
+			s.Tags = mergeTags(s.Tags)
+			result[i] = s
+		}
+		return result
+	}
+
+	result := make([]stats.SampleContainer, len(sampleContainers))
+	for i, sc := range sampleContainers {
+		switch c := sc.(type) {
+		case stats.Sample:
+			c.Tags = mergeTags(c.Tags)
+			result[i] = c
+		case stats.Samples:
+			result[i] = stats.Samples(mergeSamples(c))
+		case stats.ConnectedSamples:
+			c.Tags = mergeTags(c.Tags)
+			c.Samples = mergeSamples(c.Samples)
+			result[i] = c
+		case *httpext.Trail:
+			trail := *c
+			trail.Tags = mergeTags(c.Tags)
+			trail.Samples = mergeSamples(c.Samples)
+			result[i] = &trail
+		case *netext.NetTrail:
+			trail := *c
+			trail.Tags = mergeTags(c.Tags)
+			trail.Samples = mergeSamples(c.Samples)
+			result[i] = &trail
+		case stats.Event:
+			c.Tags = mergeTags(c.Tags)
+			result[i] = c
+		default:
+			result[i] = stats.Samples(mergeSamples(sc.GetSamples()))
+		}
+	}
+	return result
+}
+
 func (e *Engine) runMetricsEmission(ctx context.Context) {
 	ticker := time.NewTicker(MetricsRate)
 	for {
@@ -308,10 +864,13 @@ func (e *Engine) runThresholds(ctx context.Context, abort func()) {
 }
 
 func (e *Engine) processThresholds(abort func()) {
+	e.processThresholdsAt(e.Executor.GetTime(), abort)
+}
+
+func (e *Engine) processThresholdsAt(t time.Duration, abort func()) {
 	e.MetricsLock.Lock()
 	defer e.MetricsLock.Unlock()
 
-	t := e.Executor.GetTime()
 	abortOnFail := false
 
 	e.thresholdsTainted = false
@@ -319,15 +878,14 @@ func (e *Engine) processThresholds(abort func()) {
 		if len(m.Thresholds.Thresholds) == 0 {
 			continue
 		}
+		wasTainted := m.Tainted.Bool
 		m.Tainted = null.BoolFrom(false)
 
 		e.logger.WithField("m", m.Name).Debug("running thresholds")
 		succ, err := m.Thresholds.Run(m.Sink, t)
 		if err != nil {
 			e.logger.WithField("m", m.Name).WithError(err).Error("Threshold error")
-			continue
-		}
-		if !succ {
+		} else if !succ {
 			e.logger.WithField("m", m.Name).Debug("Thresholds failed")
 			m.Tainted = null.BoolFrom(true)
 			e.thresholdsTainted = true
@@ -335,6 +893,10 @@ func (e *Engine) processThresholds(abort func()) {
 				abortOnFail = true
 			}
 		}
+
+		if m.Tainted.Bool != wasTainted {
+			e.publishThresholdChange(ThresholdChange{Metric: m.Name, Tainted: m.Tainted.Bool, Time: t})
+		}
 	}
 
 	if abortOnFail && abort != nil {
@@ -388,6 +950,18 @@ func (e *Engine) processSamples(sampleCointainers []stats.SampleContainer) {
 	e.MetricsLock.Lock()
 	defer e.MetricsLock.Unlock()
 
+	if len(e.tags) > 0 {
+		sampleCointainers = e.addTags(sampleCointainers)
+	}
+
+	for _, sc := range sampleCointainers {
+		if event, ok := sc.(stats.Event); ok {
+			e.events = append(e.events, event)
+		}
+	}
+	e.processSamplesForGroups(sampleCointainers)
+	e.processSamplesForErrors(sampleCointainers)
+
 	// TODO: run this and the below code in goroutines?
 	if !(e.NoSummary && e.NoThresholds) {
 		e.processSamplesForMetrics(sampleCointainers)
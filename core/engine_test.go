@@ -31,11 +31,13 @@ import (
 	"github.com/loadimpact/k6/js"
 	"github.com/loadimpact/k6/lib"
 	"github.com/loadimpact/k6/lib/metrics"
+	"github.com/loadimpact/k6/lib/netext/httpext"
 	"github.com/loadimpact/k6/lib/testutils"
 	"github.com/loadimpact/k6/lib/types"
 	"github.com/loadimpact/k6/loader"
 	"github.com/loadimpact/k6/stats"
 	"github.com/loadimpact/k6/stats/dummy"
+	"github.com/pkg/errors"
 	log "github.com/sirupsen/logrus"
 	logtest "github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
@@ -296,6 +298,117 @@ func TestEngineRun(t *testing.T) {
 	})
 }
 
+func TestEngineWaitForStart(t *testing.T) {
+	e, err := newTestEngine(nil, lib.Options{
+		VUs:        null.IntFrom(1),
+		VUsMax:     null.IntFrom(1),
+		Iterations: null.IntFrom(1),
+	})
+	require.NoError(t, err)
+	e.WaitForStart = true
+	assert.False(t, e.IsInitialized())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	errC := make(chan error)
+	go func() { errC <- e.Run(ctx) }()
+
+	time.Sleep(50 * time.Millisecond)
+	assert.True(t, e.IsInitialized())
+	assert.Equal(t, int64(0), e.Executor.GetIterations())
+
+	at := time.Now().Add(50 * time.Millisecond)
+	start, err := e.Start(at)
+	require.NoError(t, err)
+	assert.Equal(t, at, start)
+	_, err = e.Start(time.Time{})
+	assert.EqualError(t, err, "the test has already been started")
+
+	// It keeps going after the test is done, until it's cancelled
+	time.Sleep(200 * time.Millisecond)
+	assert.Equal(t, int64(1), e.Executor.GetIterations())
+	select {
+	case err := <-errC:
+		t.Fatalf("the engine stopped early: %v", err)
+	default:
+	}
+	cancel()
+	assert.NoError(t, <-errC)
+}
+
+func TestEngineRunStatus(t *testing.T) {
+	t.Run("finished", func(t *testing.T) {
+		e, err := newTestEngine(nil, lib.Options{
+			VUs:        null.IntFrom(1),
+			VUsMax:     null.IntFrom(1),
+			Iterations: null.IntFrom(1),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, lib.RunStatusInitializing, e.GetRunStatus())
+		_, ended := e.GetEndTime()
+		assert.False(t, ended)
+
+		require.NoError(t, e.Run(context.Background()))
+		assert.Equal(t, lib.RunStatusFinished, e.GetRunStatus())
+		_, ended = e.GetEndTime()
+		assert.True(t, ended)
+	})
+	t.Run("aborted by user", func(t *testing.T) {
+		e, err := newTestEngine(nil, lib.Options{})
+		require.NoError(t, err)
+		c := &dummy.Collector{}
+		e.Collectors = []lib.Collector{c}
+
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		require.NoError(t, e.Run(ctx))
+		assert.Equal(t, lib.RunStatusAbortedUser, e.GetRunStatus())
+		assert.Equal(t, lib.RunStatusAbortedUser, c.RunStatus)
+	})
+	t.Run("aborted by script error", func(t *testing.T) {
+		e, err := newTestEngine(local.New(&lib.MiniRunner{
+			SetupFn: func(ctx context.Context, out chan<- stats.SampleContainer) ([]byte, error) {
+				return nil, errors.New("setup error")
+			},
+		}), lib.Options{})
+		require.NoError(t, err)
+		assert.Error(t, e.Run(context.Background()))
+		assert.Equal(t, lib.RunStatusAbortedScriptError, e.GetRunStatus())
+	})
+	t.Run("timed out", func(t *testing.T) {
+		e, err := newTestEngine(local.New(&lib.MiniRunner{
+			SetupFn: func(ctx context.Context, out chan<- stats.SampleContainer) ([]byte, error) {
+				return nil, lib.NewTimeoutError("setup")
+			},
+		}), lib.Options{})
+		require.NoError(t, err)
+		assert.Error(t, e.Run(context.Background()))
+		assert.Equal(t, lib.RunStatusTimedOut, e.GetRunStatus())
+	})
+	t.Run("waiting for start", func(t *testing.T) {
+		e, err := newTestEngine(nil, lib.Options{})
+		require.NoError(t, err)
+		e.WaitForStart = true
+
+		ctx, cancel := context.WithCancel(context.Background())
+		errC := make(chan error)
+		go func() { errC <- e.Run(ctx) }()
+		time.Sleep(50 * time.Millisecond)
+		assert.Equal(t, lib.RunStatusQueued, e.GetRunStatus())
+		cancel()
+		require.NoError(t, <-errC)
+		assert.Equal(t, lib.RunStatusAbortedUser, e.GetRunStatus())
+	})
+}
+
+func TestEngineExitCode(t *testing.T) {
+	e, err := newTestEngine(nil, lib.Options{})
+	require.NoError(t, err)
+	assert.False(t, e.GetExitCode().Valid)
+	e.SetExitCode(99)
+	assert.Equal(t, null.IntFrom(99), e.GetExitCode())
+}
+
 func TestEngineAtTime(t *testing.T) {
 	e, err := newTestEngine(nil, lib.Options{})
 	assert.NoError(t, err)
@@ -483,6 +596,31 @@ func TestEngine_processThresholds(t *testing.T) {
 	}
 }
 
+func TestEngineReplay(t *testing.T) {
+	metric := stats.New("my_metric", stats.Trend)
+	ths, err := stats.NewThresholds([]string{"max<100"})
+	require.NoError(t, err)
+
+	e, err := newTestEngine(nil, lib.Options{Thresholds: map[string]stats.Thresholds{"my_metric": ths}})
+	require.NoError(t, err)
+	c := &dummy.Collector{}
+	e.Collectors = []lib.Collector{c}
+
+	start := time.Now()
+	samples := make(chan stats.SampleContainer, 3)
+	for i, v := range []float64{10, 20, 150} {
+		samples <- stats.Sample{Metric: metric, Time: start.Add(time.Duration(i) * time.Second), Value: v}
+	}
+	close(samples)
+
+	assert.Equal(t, 2*time.Second, e.Replay(context.Background(), samples))
+	assert.True(t, e.IsTainted())
+	assert.Equal(t, 3, len(c.SampleContainers))
+	if assert.Contains(t, e.Metrics, "my_metric") {
+		assert.Equal(t, 150.0, e.Metrics["my_metric"].Sink.(*stats.TrendSink).Max)
+	}
+}
+
 func getMetricSum(collector *dummy.Collector, name string) (result float64) {
 	for _, sc := range collector.SampleContainers {
 		for _, s := range sc.GetSamples() {
@@ -737,6 +875,7 @@ func TestRunTags(t *testing.T) {
 	systemMetrics := []*stats.Metric{
 		metrics.VUs, metrics.VUsMax, metrics.Iterations, metrics.IterationDuration,
 		metrics.GroupDuration, metrics.DataSent, metrics.DataReceived,
+		metrics.VUInitDuration, metrics.VUInitMemory,
 	}
 
 	getExpectedOverVal := func(metricName string) string {
@@ -967,3 +1106,176 @@ func TestMinIterationDuration(t *testing.T) {
 	// But we expect the custom counter to be added to 4 times
 	assert.Equal(t, 4.0, getMetricSum(collector, "testcounter"))
 }
+
+func TestEngineSubscribeThresholds(t *testing.T) {
+	metric := stats.New("my_metric", stats.Gauge)
+	ths, err := stats.NewThresholds([]string{"value<2"})
+	require.NoError(t, err)
+	e, err := newTestEngine(nil, lib.Options{Thresholds: map[string]stats.Thresholds{metric.Name: ths}})
+	require.NoError(t, err)
+
+	changes, unsubscribe := e.SubscribeThresholds()
+	process := func(value float64, d time.Duration) {
+		e.processSamples([]stats.SampleContainer{stats.Sample{Metric: metric, Value: value}})
+		e.processThresholdsAt(d, nil)
+	}
+
+	process(1, 1*time.Second)
+	process(3, 2*time.Second)
+	process(4, 3*time.Second)
+	process(1, 4*time.Second)
+	unsubscribe()
+	process(3, 5*time.Second)
+
+	assert.Equal(t, ThresholdChange{Metric: "my_metric", Tainted: true, Time: 2 * time.Second}, <-changes)
+	assert.Equal(t, ThresholdChange{Metric: "my_metric", Tainted: false, Time: 4 * time.Second}, <-changes)
+	assert.Len(t, changes, 0)
+}
+
+func TestEngineSetThresholds(t *testing.T) {
+	metric := stats.New("my_metric", stats.Gauge)
+	e, err := newTestEngine(nil, lib.Options{})
+	require.NoError(t, err)
+	process := func(value float64, tags map[string]string) {
+		e.processSamples([]stats.SampleContainer{
+			stats.Sample{Metric: metric, Value: value, Tags: stats.IntoSampleTags(&tags)},
+		})
+		e.processThresholdsAt(time.Second, nil)
+	}
+
+	process(3, map[string]string{"a": "1"})
+	assert.False(t, e.IsTainted())
+
+	ths, err := stats.NewThresholds([]string{"value<2"})
+	require.NoError(t, err)
+	e.SetThresholds("my_metric", ths)
+	process(3, map[string]string{"a": "1"})
+	assert.True(t, e.IsTainted())
+	assert.True(t, e.Metrics["my_metric"].Tainted.Bool)
+
+	assert.True(t, e.DeleteThresholds("my_metric"))
+	assert.False(t, e.DeleteThresholds("my_metric"))
+	assert.False(t, e.Metrics["my_metric"].Tainted.Valid)
+	process(3, map[string]string{"a": "1"})
+	assert.False(t, e.IsTainted())
+
+	// A submetric that didn't exist before only gets the new samples
+	subThs, err := stats.NewThresholds([]string{"value<2"})
+	require.NoError(t, err)
+	e.SetThresholds("my_metric{a:2}", subThs)
+	assert.Contains(t, e.GetThresholds(), "my_metric{a:2}")
+	process(1, map[string]string{"a": "2"})
+	require.Contains(t, e.Metrics, "my_metric{a:2}")
+	assert.Equal(t, 1.0, e.Metrics["my_metric{a:2}"].Sink.(*stats.GaugeSink).Value)
+	assert.False(t, e.IsTainted())
+	process(5, map[string]string{"a": "2"})
+	assert.True(t, e.IsTainted())
+	assert.True(t, e.Metrics["my_metric{a:2}"].Tainted.Bool)
+}
+
+func TestEngineUpdateTags(t *testing.T) {
+	metric := stats.New("my_metric", stats.Counter)
+	e, err := newTestEngine(nil, lib.Options{})
+	require.NoError(t, err)
+	collector := &dummy.Collector{}
+	e.Collectors = []lib.Collector{collector}
+
+	assert.Equal(t, map[string]string{"phase": "deploy", "team": "a"},
+		e.UpdateTags(map[string]string{"phase": "deploy", "team": "a"}, nil))
+	assert.Equal(t, map[string]string{"phase": "deploy"}, e.UpdateTags(nil, []string{"team"}))
+
+	sampleTags := map[string]string{"url": "http://example.com"}
+	trailTags := map[string]string{"phase": "setup"}
+	trail := &httpext.Trail{Tags: stats.IntoSampleTags(&trailTags)}
+	trail.Samples = []stats.Sample{{Metric: metric, Value: 1, Tags: trail.Tags}}
+	e.processSamples([]stats.SampleContainer{
+		stats.Sample{Metric: metric, Value: 1, Tags: stats.IntoSampleTags(&sampleTags)},
+		trail,
+	})
+
+	require.Len(t, collector.Samples, 2)
+	assert.Equal(t, map[string]string{"url": "http://example.com", "phase": "deploy"},
+		collector.Samples[0].Tags.CloneTags())
+	// Tags that the samples already have aren't overwritten
+	assert.Equal(t, map[string]string{"phase": "setup"}, collector.Samples[1].Tags.CloneTags())
+	assert.Equal(t, map[string]string{"phase": "deploy"}, e.GetTags())
+}
+
+func TestEngineAddEvent(t *testing.T) {
+	e, err := newTestEngine(nil, lib.Options{})
+	require.NoError(t, err)
+	collector := &dummy.Collector{}
+	e.Collectors = []lib.Collector{collector}
+	e.UpdateTags(map[string]string{"phase": "peak"}, nil)
+
+	now := time.Now()
+	eventTags := map[string]string{"service": "api"}
+	e.AddEvent(stats.Event{Time: now, Message: "deployed v2", Tags: stats.IntoSampleTags(&eventTags)})
+
+	require.Len(t, collector.SampleContainers, 1)
+	assert.Empty(t, collector.Samples)
+	event, ok := collector.SampleContainers[0].(stats.Event)
+	require.True(t, ok)
+	assert.Equal(t, "deployed v2", event.Message)
+	assert.Equal(t, map[string]string{"service": "api", "phase": "peak"}, event.Tags.CloneTags())
+
+	events := e.GetEvents()
+	require.Len(t, events, 1)
+	assert.Equal(t, event, events[0])
+	assert.Empty(t, e.Metrics)
+}
+
+func TestEngineGroupStats(t *testing.T) {
+	e, err := newTestEngine(nil, lib.Options{})
+	require.NoError(t, err)
+
+	tags := func(group string) *stats.SampleTags {
+		return stats.IntoSampleTags(&map[string]string{"group": group})
+	}
+	e.processSamples([]stats.SampleContainer{
+		stats.Sample{Metric: metrics.GroupDuration, Value: 100, Tags: tags("::login")},
+		stats.Sample{Metric: metrics.GroupDuration, Value: 300, Tags: tags("::login")},
+		stats.Sample{Metric: metrics.HTTPReqs, Value: 1, Tags: tags("::login")},
+		stats.Sample{Metric: metrics.HTTPReqs, Value: 1, Tags: tags("::login")},
+		stats.Sample{Metric: metrics.HTTPReqs, Value: 1, Tags: tags("")},
+		stats.Sample{Metric: metrics.HTTPReqDuration, Value: 50, Tags: tags("::login")},
+		stats.Sample{Metric: metrics.HTTPReqs, Value: 1},
+	})
+
+	groupStats := e.GetGroupStats()
+	require.Len(t, groupStats, 2)
+
+	login := groupStats["::login"]
+	assert.Equal(t, uint64(2), login.Count)
+	assert.Equal(t, 200.0, login.Duration["avg"])
+	assert.Equal(t, 100.0, login.Duration["min"])
+	assert.Equal(t, 300.0, login.Duration["max"])
+	assert.Equal(t, int64(2), login.HTTPReqs)
+
+	root := groupStats[""]
+	assert.Equal(t, uint64(0), root.Count)
+	assert.Equal(t, int64(1), root.HTTPReqs)
+}
+
+func TestEngineIterationErrors(t *testing.T) {
+	e, err := newTestEngine(nil, lib.Options{})
+	require.NoError(t, err)
+
+	tags := func(errorType, message, group string) *stats.SampleTags {
+		return stats.IntoSampleTags(&map[string]string{"error_type": errorType, "error": message, "group": group})
+	}
+	e.processSamples([]stats.SampleContainer{
+		stats.Sample{Metric: metrics.Errors, Value: 1, Tags: tags("TypeError", "oops", "")},
+		stats.Sample{Metric: metrics.Errors, Value: 1, Tags: tags("GoError", "hi", "::login")},
+		stats.Sample{Metric: metrics.Errors, Value: 1, Tags: tags("TypeError", "oops", "::login")},
+		stats.Sample{Metric: metrics.Errors, Value: 1, Tags: tags("Error", "oops", "")},
+		stats.Sample{Metric: metrics.Errors, Value: 1},
+		stats.Sample{Metric: metrics.Checks, Value: 1, Tags: tags("TypeError", "oops", "")},
+	})
+
+	assert.Equal(t, []lib.IterationErrorCount{
+		{Type: "TypeError", Message: "oops", Count: 2},
+		{Type: "Error", Message: "oops", Count: 1},
+		{Type: "GoError", Message: "hi", Count: 1},
+	}, e.GetIterationErrors())
+}
@@ -23,6 +23,7 @@ package local
 import (
 	"context"
 	"fmt"
+	"runtime"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -51,7 +52,7 @@ type vuHandle struct {
 	cancel context.CancelFunc
 }
 
-func (h *vuHandle) run(logger *log.Logger, flow <-chan int64, iterDone chan<- struct{}) {
+func (h *vuHandle) run(flow <-chan int64, iterDone chan<- error) {
 	h.RLock()
 	ctx := h.ctx
 	h.RUnlock()
@@ -72,17 +73,10 @@ func (h *vuHandle) run(logger *log.Logger, flow <-chan int64, iterDone chan<- st
 			case <-ctx.Done():
 			// Don't log errors or emit iterations metrics from cancelled iterations
 			default:
-				if err != nil {
-					if s, ok := err.(fmt.Stringer); ok {
-						logger.Error(s.String())
-					} else {
-						logger.Error(err.Error())
-					}
-				}
-				iterDone <- struct{}{}
+				iterDone <- err
 			}
 		} else {
-			iterDone <- struct{}{}
+			iterDone <- nil
 		}
 	}
 }
@@ -97,6 +91,8 @@ type Executor struct {
 	runSetup    bool
 	runTeardown bool
 
+	status int64 // The current lib.RunStatus
+
 	vus       []*vuHandle
 	vusLock   sync.RWMutex
 	numVUs    int64
@@ -124,11 +120,22 @@ type Executor struct {
 	// Output channel to which VUs send samples.
 	vuOut chan stats.SampleContainer
 
-	// Channel on which VUs sigal that iterations are completed
-	iterDone chan struct{}
+	// Channel on which VUs sigal that iterations are completed, with the error that aborted them
+	iterDone chan error
+
+	// Iteration errors that have been logged already, see logIterationError().
+	loggedErrors map[lib.IterationErrorKey]bool
 
 	// Flow control for VUs; iterations are run only after reading from this channel.
 	flow chan int64
+
+	// Progress of the VU initialization in SetVUsMax(), see GetInitProgress().
+	initVUsDone, initVUsTotal int64
+
+	// Samples of the init-phase metrics that haven't been emitted yet; VUs can be initialized
+	// before the test is started, so they are only sent out from Run().
+	initSamplesLock sync.Mutex
+	initSamples     []stats.SampleContainer
 }
 
 func New(r lib.Runner) *Executor {
@@ -142,10 +149,13 @@ func New(r lib.Runner) *Executor {
 		Logger:      log.StandardLogger(),
 		runSetup:    true,
 		runTeardown: true,
+		status:      int64(lib.RunStatusInitializing),
 		endIters:    -1,
 		endTime:     -1,
 		vuOut:       make(chan stats.SampleContainer, bufferSize),
-		iterDone:    make(chan struct{}),
+		iterDone:    make(chan error),
+
+		loggedErrors: make(map[lib.IterationErrorKey]bool),
 	}
 }
 
@@ -153,11 +163,16 @@ func (e *Executor) Run(parent context.Context, engineOut chan<- stats.SampleCont
 	e.runLock.Lock()
 	defer e.runLock.Unlock()
 
+	defer e.setRunStatus(lib.RunStatusFinished)
+
+	e.flushInitSamples(engineOut)
 	if e.Runner != nil && e.runSetup {
+		e.setRunStatus(lib.RunStatusSetup)
 		if err := e.Runner.Setup(parent, engineOut); err != nil {
 			return err
 		}
 	}
+	e.setRunStatus(lib.RunStatusRunning)
 
 	ctx, cancel := context.WithCancel(parent)
 	vuFlow := make(chan int64)
@@ -171,6 +186,7 @@ func (e *Executor) Run(parent context.Context, engineOut chan<- stats.SampleCont
 	var cutoff time.Time
 	defer func() {
 		if e.Runner != nil && e.runTeardown {
+			e.setRunStatus(lib.RunStatusTeardown)
 			err := e.Runner.Teardown(parent, engineOut)
 			if reterr == nil {
 				reterr = err
@@ -196,8 +212,11 @@ func (e *Executor) Run(parent context.Context, engineOut chan<- stats.SampleCont
 
 		for {
 			select {
-			case <-iterDone:
+			case err := <-iterDone:
 				// Spool through all remaining iterations, do not emit stats since the Run() is over
+				if err != nil {
+					e.logIterationError(lib.NewIterationError(err))
+				}
 			case newSampleContainer := <-vuOut:
 				if cutoff.IsZero() {
 					engineOut <- newSampleContainer
@@ -265,10 +284,12 @@ func (e *Executor) Run(parent context.Context, engineOut chan<- stats.SampleCont
 			atomic.AddInt64(&e.partIters, 1)
 		case t := <-ticker.C:
 			// Every tick, increment the clock, see if we passed the end point, and process stages.
+			// VUs may also have been added through SetVUsMax(), so emit their init-phase metrics.
 			// If the test ends this way, set a cutoff point; any samples collected past the cutoff
 			// point are excluded.
 			d := t.Sub(lastTick)
 			lastTick = t
+			e.flushInitSamples(engineOut)
 
 			end := time.Duration(atomic.LoadInt64(&e.endTime))
 			at := time.Duration(atomic.AddInt64(&e.time, int64(d)))
@@ -294,19 +315,30 @@ func (e *Executor) Run(parent context.Context, engineOut chan<- stats.SampleCont
 			}
 		case sampleContainer := <-vuOut:
 			engineOut <- sampleContainer
-		case <-iterDone:
+		case err := <-iterDone:
 			// Every iteration ends with a write to iterDone. Check if we've hit the end point.
 			// If not, make sure to include an Iterations bump in the list!
 			var tags *stats.SampleTags
 			if e.Runner != nil {
 				tags = e.Runner.GetOptions().RunTags
 			}
+			now := time.Now()
 			engineOut <- stats.Sample{
-				Time:   time.Now(),
+				Time:   now,
 				Metric: metrics.Iterations,
 				Value:  1,
 				Tags:   tags,
 			}
+			if err != nil {
+				ierr := lib.NewIterationError(err)
+				e.logIterationError(ierr)
+				engineOut <- stats.Sample{
+					Time:   now,
+					Metric: metrics.Errors,
+					Value:  1,
+					Tags:   e.iterationErrorTags(ierr),
+				}
+			}
 
 			end := atomic.LoadInt64(&e.endIters)
 			at := atomic.AddInt64(&e.iters, 1)
@@ -324,6 +356,51 @@ func (e *Executor) Run(parent context.Context, engineOut chan<- stats.SampleCont
 	}
 }
 
+// logIterationError logs only the first occurrence of each error that aborts iterations, the
+// others are only counted in the errors metric, so the same error thrown by every iteration
+// doesn't flood the log.
+func (e *Executor) logIterationError(err *lib.IterationError) {
+	var opts lib.Options
+	if e.Runner != nil {
+		opts = e.Runner.GetOptions()
+	}
+	fields := log.Fields{"error_type": err.Type, "scheduler": opts.SchedulerName()}
+	if err.Group != nil {
+		fields["group"] = err.Group.Path
+	}
+	if err.VU != 0 {
+		fields["vu"], fields["iter"] = err.VU, err.Iteration
+	}
+	logger := e.Logger.WithFields(fields)
+
+	key := err.Key()
+	if e.loggedErrors[key] {
+		logger.Debug(err.String())
+		return
+	}
+	e.loggedErrors[key] = true
+	logger.Error(err.String())
+}
+
+// iterationErrorTags returns the tags of the errors metric sample for an iteration error: its
+// type and message, the group it was thrown in and the scheduler of the iteration.
+func (e *Executor) iterationErrorTags(err *lib.IterationError) *stats.SampleTags {
+	var opts lib.Options
+	if e.Runner != nil {
+		opts = e.Runner.GetOptions()
+	}
+	tags := opts.RunTags.CloneTags()
+	if opts.SystemTags["error"] {
+		tags["error"] = err.Message
+	}
+	if opts.SystemTags["group"] && err.Group != nil {
+		tags["group"] = err.Group.Path
+	}
+	tags["error_type"] = err.Type
+	tags["scheduler"] = opts.SchedulerName()
+	return stats.IntoSampleTags(&tags)
+}
+
 func (e *Executor) scale(ctx context.Context, num int64) error {
 	e.Logger.WithField("num", num).Debug("Local: Scaling...")
 
@@ -357,7 +434,7 @@ func (e *Executor) scale(ctx context.Context, num int64) error {
 
 				e.wg.Add(1)
 				go func() {
-					handle.run(e.Logger, flow, iterDone)
+					handle.run(flow, iterDone)
 					e.wg.Done()
 				}()
 			}
@@ -521,25 +598,136 @@ func (e *Executor) SetVUsMax(max int64) error {
 	e.vusLock.Lock()
 	defer e.vusLock.Unlock()
 
-	vus := e.vus
-	for i := numVUsMax; i < max; i++ {
-		var handle vuHandle
-		if e.Runner != nil {
-			vu, err := e.Runner.NewVU(vuOut)
-			if err != nil {
-				return err
-			}
-			handle.vu = vu
-		}
-		vus = append(vus, &handle)
+	handles, err := e.initVUs(max-numVUsMax, vuOut)
+	if err != nil {
+		return err
 	}
-	e.vus = vus
+	e.vus = append(e.vus, handles...)
 
 	atomic.StoreInt64(&e.numVUsMax, max)
 
 	return nil
 }
 
+// initVUs initializes num new VUs, in parallel with up to GOMAXPROCS workers, since instantiating
+// a heavy script can take a while. The time each VU took and an estimate of the memory it uses
+// are recorded in the init-phase metrics. If a VU fails to initialize, the first error is
+// returned and none of the VUs are kept.
+func (e *Executor) initVUs(num int64, vuOut chan<- stats.SampleContainer) ([]*vuHandle, error) {
+	handles := make([]*vuHandle, num)
+	if e.Runner == nil {
+		for i := range handles {
+			handles[i] = &vuHandle{}
+		}
+		return handles, nil
+	}
+
+	atomic.StoreInt64(&e.initVUsDone, 0)
+	atomic.StoreInt64(&e.initVUsTotal, num)
+
+	workers := int64(runtime.GOMAXPROCS(0))
+	if num < workers {
+		workers = num
+	}
+	e.Logger.WithFields(log.Fields{"vus": num, "workers": workers}).Debug("Local: Initializing VUs")
+
+	// The heap is measured after a GC on both ends, so only what the new VUs retain is counted.
+	var memBefore, memAfter runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&memBefore)
+	startTime := time.Now()
+
+	tags := e.Runner.GetOptions().RunTags
+	samples := make(stats.Samples, num, num+1)
+	indexes := make(chan int64)
+	errs := make(chan error, workers)
+	var wg sync.WaitGroup
+	for w := int64(0); w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				vuStartTime := time.Now()
+				vu, err := e.Runner.NewVU(vuOut)
+				if err != nil {
+					errs <- err
+					return
+				}
+				now := time.Now()
+				handles[i] = &vuHandle{vu: vu}
+				samples[i] = stats.Sample{
+					Time:   now,
+					Metric: metrics.VUInitDuration,
+					Value:  stats.D(now.Sub(vuStartTime)),
+					Tags:   tags,
+				}
+				atomic.AddInt64(&e.initVUsDone, 1)
+			}
+		}()
+	}
+
+	var err error
+dispatch:
+	for i := int64(0); i < num; i++ {
+		select {
+		case indexes <- i:
+		case err = <-errs:
+			break dispatch
+		}
+	}
+	close(indexes)
+	wg.Wait()
+	if err == nil {
+		select {
+		case err = <-errs:
+		default:
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	runtime.GC()
+	runtime.ReadMemStats(&memAfter)
+	var memPerVU float64
+	if memAfter.HeapAlloc > memBefore.HeapAlloc && num > 0 {
+		memPerVU = float64(memAfter.HeapAlloc-memBefore.HeapAlloc) / float64(num)
+	}
+	samples = append(samples, stats.Sample{
+		Time:   time.Now(),
+		Metric: metrics.VUInitMemory,
+		Value:  memPerVU,
+		Tags:   tags,
+	})
+	e.Logger.WithFields(log.Fields{
+		"vus": num, "t": time.Since(startTime), "memPerVU": int64(memPerVU),
+	}).Debug("Local: Initialized VUs")
+
+	e.initSamplesLock.Lock()
+	e.initSamples = append(e.initSamples, samples)
+	e.initSamplesLock.Unlock()
+
+	return handles, nil
+}
+
+// flushInitSamples sends out the samples of the init-phase metrics recorded since the last call.
+func (e *Executor) flushInitSamples(out chan<- stats.SampleContainer) {
+	e.initSamplesLock.Lock()
+	samples := e.initSamples
+	e.initSamples = nil
+	e.initSamplesLock.Unlock()
+
+	for _, sc := range samples {
+		out <- sc
+	}
+}
+
+// GetInitProgress returns how many of the VUs that are being initialized by SetVUsMax(), or were
+// by its last call, are ready.
+func (e *Executor) GetInitProgress() (done, total int64) {
+	return atomic.LoadInt64(&e.initVUsDone), atomic.LoadInt64(&e.initVUsTotal)
+}
+
 func (e *Executor) SetRunSetup(r bool) {
 	e.runSetup = r
 }
@@ -547,3 +735,11 @@ func (e *Executor) SetRunSetup(r bool) {
 func (e *Executor) SetRunTeardown(r bool) {
 	e.runTeardown = r
 }
+
+func (e *Executor) GetRunStatus() lib.RunStatus {
+	return lib.RunStatus(atomic.LoadInt64(&e.status))
+}
+
+func (e *Executor) setRunStatus(status lib.RunStatus) {
+	atomic.StoreInt64(&e.status, int64(status))
+}
@@ -35,9 +35,11 @@ import (
 	"github.com/loadimpact/k6/js"
 	"github.com/loadimpact/k6/lib"
 	"github.com/loadimpact/k6/lib/metrics"
+	"github.com/loadimpact/k6/lib/scheduler"
 	"github.com/loadimpact/k6/lib/types"
 	"github.com/loadimpact/k6/stats"
 	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
 	logtest "github.com/sirupsen/logrus/hooks/test"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -145,6 +147,33 @@ func TestExecutorSetupTeardownRun(t *testing.T) {
 	})
 }
 
+func TestExecutorGetRunStatus(t *testing.T) {
+	var statuses []lib.RunStatus
+	var e *Executor
+	e = New(&lib.MiniRunner{
+		SetupFn: func(ctx context.Context, out chan<- stats.SampleContainer) ([]byte, error) {
+			statuses = append(statuses, e.GetRunStatus())
+			return nil, nil
+		},
+		Fn: func(ctx context.Context, out chan<- stats.SampleContainer) error {
+			statuses = append(statuses, e.GetRunStatus())
+			return nil
+		},
+		TeardownFn: func(ctx context.Context, out chan<- stats.SampleContainer) error {
+			statuses = append(statuses, e.GetRunStatus())
+			return nil
+		},
+	})
+	e.SetEndIterations(null.IntFrom(1))
+	assert.NoError(t, e.SetVUsMax(1))
+	assert.NoError(t, e.SetVUs(1))
+
+	assert.Equal(t, lib.RunStatusInitializing, e.GetRunStatus())
+	assert.NoError(t, e.Run(context.Background(), make(chan stats.SampleContainer, 100)))
+	assert.Equal(t, []lib.RunStatus{lib.RunStatusSetup, lib.RunStatusRunning, lib.RunStatusTeardown}, statuses)
+	assert.Equal(t, lib.RunStatusFinished, e.GetRunStatus())
+}
+
 func TestExecutorSetLogger(t *testing.T) {
 	logger, _ := logtest.NewNullLogger()
 	e := New(nil)
@@ -229,12 +258,12 @@ func TestExecutorEndTime(t *testing.T) {
 		e.SetLogger(l)
 
 		startTime := time.Now()
-		assert.NoError(t, e.Run(context.Background(), make(chan stats.SampleContainer, 200)))
+		assert.NoError(t, e.Run(context.Background(), make(chan stats.SampleContainer, 1000)))
 		assert.True(t, time.Now().After(startTime.Add(100*time.Millisecond)), "test did not take 100ms")
 
-		assert.NotEmpty(t, hook.Entries)
-		for _, e := range hook.Entries {
-			assert.Equal(t, "hi", e.Message)
+		// Only the first occurrence of the same error is logged
+		if assert.Len(t, hook.Entries, 1) {
+			assert.Equal(t, "hi", hook.Entries[0].Message)
 		}
 	})
 
@@ -280,10 +309,13 @@ func TestExecutorEndIterations(t *testing.T) {
 	e.SetEndIterations(null.IntFrom(100))
 	assert.Equal(t, null.IntFrom(100), e.GetEndIterations())
 
-	samples := make(chan stats.SampleContainer, 201)
+	samples := make(chan stats.SampleContainer, 202)
 	assert.NoError(t, e.Run(context.Background(), samples))
 	assert.Equal(t, int64(100), e.GetIterations())
 	assert.Equal(t, int64(100), i)
+	initSamples, ok := <-samples
+	require.True(t, ok)
+	assert.Len(t, initSamples.GetSamples(), 2)
 	for i := 0; i < 100; i++ {
 		mySample, ok := <-samples
 		require.True(t, ok)
@@ -297,6 +329,72 @@ func TestExecutorEndIterations(t *testing.T) {
 	}
 }
 
+func TestExecutorIterationErrors(t *testing.T) {
+	root, err := lib.NewGroup("", nil)
+	require.NoError(t, err)
+	group, err := root.Group("group")
+	require.NoError(t, err)
+
+	var i int64
+	e := New(&lib.MiniRunner{
+		Fn: func(ctx context.Context, out chan<- stats.SampleContainer) error {
+			switch atomic.AddInt64(&i, 1) % 3 {
+			case 1:
+				return &lib.IterationError{
+					Err:  errors.New("TypeError: oops at x.js:1:1"),
+					Type: "TypeError", Message: "oops", Group: group, VU: 3, Iteration: 7,
+				}
+			case 2:
+				return errors.New("hi\nthere")
+			}
+			return nil
+		},
+		Options: lib.Options{
+			RunTags:    stats.IntoSampleTags(&map[string]string{"foo": "bar"}),
+			SystemTags: lib.GetTagSet(lib.DefaultSystemTagList...),
+			Execution:  scheduler.ConfigMap{"my-scheduler": scheduler.NewPerVUIterationsConfig("my-scheduler")},
+		},
+	})
+	assert.NoError(t, e.SetVUsMax(1))
+	assert.NoError(t, e.SetVUs(1))
+	e.SetEndIterations(null.IntFrom(9))
+	l, hook := logtest.NewNullLogger()
+	e.SetLogger(l)
+
+	samples := make(chan stats.SampleContainer, 100)
+	assert.NoError(t, e.Run(context.Background(), samples))
+	close(samples)
+
+	errorTags := map[string]int{}
+	iterations := 0
+	for sc := range samples {
+		for _, sample := range sc.GetSamples() {
+			switch sample.Metric {
+			case metrics.Iterations:
+				iterations++
+			case metrics.Errors:
+				assert.Equal(t, float64(1), sample.Value)
+				tags := sample.Tags.CloneTags()
+				assert.Equal(t, "bar", tags["foo"])
+				assert.Equal(t, "my-scheduler", tags["scheduler"])
+				errorTags[tags["error_type"]+"|"+tags["error"]+"|"+tags["group"]]++
+			}
+		}
+	}
+	assert.Equal(t, 9, iterations)
+	assert.Equal(t, map[string]int{"TypeError|oops|::group": 3, "GoError|hi|": 3}, errorTags)
+
+	if assert.Len(t, hook.Entries, 2) {
+		assert.Equal(t, "TypeError: oops at x.js:1:1", hook.Entries[0].Message)
+		assert.Equal(t, log.Fields{
+			"error_type": "TypeError", "scheduler": "my-scheduler", "group": "::group",
+			"vu": int64(3), "iter": int64(7),
+		}, hook.Entries[0].Data)
+		assert.Equal(t, "hi\nthere", hook.Entries[1].Message)
+		assert.Equal(t, log.Fields{"error_type": "GoError", "scheduler": "my-scheduler"}, hook.Entries[1].Data)
+	}
+}
+
 func TestExecutorIsRunning(t *testing.T) {
 	ctx, cancel := context.WithCancel(context.Background())
 	e := New(nil)
@@ -345,6 +443,58 @@ func TestExecutorSetVUsMax(t *testing.T) {
 	})
 }
 
+// failingRunner is a MiniRunner whose VUs fail to initialize after the given number of them.
+type failingRunner struct {
+	lib.MiniRunner
+	okVUs int64
+}
+
+func (r *failingRunner) NewVU(out chan<- stats.SampleContainer) (lib.VU, error) {
+	if atomic.AddInt64(&r.okVUs, -1) < 0 {
+		return nil, errors.New("init error")
+	}
+	return r.MiniRunner.NewVU(out)
+}
+
+func TestExecutorSetVUsMaxInit(t *testing.T) {
+	t.Run("Metrics", func(t *testing.T) {
+		e := New(&lib.MiniRunner{})
+		require.NoError(t, e.SetVUsMax(10))
+		assert.Len(t, e.vus, 10)
+		for i, handle := range e.vus {
+			assert.NotNil(t, handle.vu, "vu %d lacks impl", i)
+		}
+		done, total := e.GetInitProgress()
+		assert.Equal(t, int64(10), done)
+		assert.Equal(t, int64(10), total)
+
+		require.NoError(t, e.SetVUsMax(15))
+		done, total = e.GetInitProgress()
+		assert.Equal(t, int64(5), done)
+		assert.Equal(t, int64(5), total)
+
+		out := make(chan stats.SampleContainer, 10)
+		e.flushInitSamples(out)
+		close(out)
+		counts := make(map[*stats.Metric]int)
+		for sc := range out {
+			for _, s := range sc.GetSamples() {
+				counts[s.Metric]++
+				assert.True(t, s.Value >= 0)
+			}
+		}
+		assert.Equal(t, map[*stats.Metric]int{metrics.VUInitDuration: 15, metrics.VUInitMemory: 2}, counts)
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		e := New(&failingRunner{okVUs: 5})
+		assert.EqualError(t, e.SetVUsMax(10), "init error")
+		assert.Equal(t, int64(0), e.GetVUsMax())
+		assert.Len(t, e.vus, 0)
+		assert.Empty(t, e.initSamples)
+	})
+}
+
 func TestExecutorSetVUs(t *testing.T) {
 	t.Run("Negative", func(t *testing.T) {
 		assert.EqualError(t, New(nil).SetVUs(-1), "vu count can't be negative")
@@ -564,7 +714,18 @@ func TestRealTimeAndSetupTeardownMetrics(t *testing.T) {
 		return netext.NewDialer(net.Dialer{}).GetTrail(time.Now(), time.Now(), true, getTags("group", group))
 	}
 
-	// Initially give a long time (5s) for the executor to start
+	// The init-phase metrics of the VU are emitted first, before setup() is run
+	select {
+	case sampleContainer := <-sampleContainers:
+		initSamples := sampleContainer.GetSamples()
+		if assert.Len(t, initSamples, 2) {
+			assert.Equal(t, metrics.VUInitDuration, initSamples[0].Metric)
+			assert.Equal(t, metrics.VUInitMemory, initSamples[1].Metric)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatalf("Did not receive the init-phase metrics")
+	}
+
 	expectIn(0, 5000, getSample(1, testCounter, "group", "::setup", "place", "setupBeforeSleep"))
 	expectIn(900, 1100, getSample(2, testCounter, "group", "::setup", "place", "setupAfterSleep"))
 	expectIn(0, 100, getDummyTrail("::setup"))
@@ -53,9 +53,10 @@ type Bundle struct {
 
 // A BundleInstance is a self-contained instance of a Bundle.
 type BundleInstance struct {
-	Runtime *goja.Runtime
-	Context *context.Context
-	Default goja.Callable
+	Runtime   *goja.Runtime
+	Context   *context.Context
+	Default   goja.Callable
+	EventLoop *common.EventLoop
 }
 
 // NewBundle creates a new bundle from a source file and a filesystem.
@@ -80,7 +81,7 @@ func NewBundle(src *loader.SourceData, filesystems map[string]afero.Fs, rtOpts l
 		BaseInitContext: NewInitContext(rt, compiler, new(context.Context), filesystems, loader.Dir(src.URL)),
 		Env:             rtOpts.Env,
 	}
-	if err := bundle.instantiate(rt, bundle.BaseInitContext); err != nil {
+	if err := bundle.instantiate(rt, bundle.BaseInitContext, common.NewEventLoop()); err != nil {
 		return nil, err
 	}
 
@@ -138,6 +139,9 @@ func NewBundleFromArchive(arc *lib.Archive, rtOpts lib.RuntimeOptions) (*Bundle,
 		return nil, errors.Errorf("expected bundle type 'js', got '%s'", arc.Type)
 	}
 
+	// Sources that were transpiled when the archive was made don't need to be transpiled again.
+	compiler.AddTranspiled(arc.Transpiled)
+
 	pgm, _, err := compiler.Compile(string(arc.Data), arc.FilenameURL.String(), "", "", true)
 	if err != nil {
 		return nil, err
@@ -162,7 +166,7 @@ func NewBundleFromArchive(arc *lib.Archive, rtOpts lib.RuntimeOptions) (*Bundle,
 		BaseInitContext: initctx,
 		Env:             env,
 	}
-	if err := bundle.instantiate(bundle.BaseInitContext.runtime, bundle.BaseInitContext); err != nil {
+	if err := bundle.instantiate(bundle.BaseInitContext.runtime, bundle.BaseInitContext, common.NewEventLoop()); err != nil {
 		return nil, err
 	}
 	return bundle, nil
@@ -185,9 +189,29 @@ func (b *Bundle) makeArchive() *lib.Archive {
 		arc.Env[k] = v
 	}
 
+	arc.Transpiled = b.transpiled()
 	return arc
 }
 
+// transpiled returns the output of Babel for the main file and the modules that needed it.
+func (b *Bundle) transpiled() map[string]json.RawMessage {
+	srcs := []string{b.Source}
+	for _, pgm := range b.BaseInitContext.programs {
+		srcs = append(srcs, pgm.src)
+	}
+
+	var transpiled map[string]json.RawMessage
+	for _, src := range srcs {
+		if key, data, ok := b.BaseInitContext.compiler.Transpiled(src); ok {
+			if transpiled == nil {
+				transpiled = make(map[string]json.RawMessage)
+			}
+			transpiled[key] = data
+		}
+	}
+	return transpiled
+}
+
 // Instantiate creates a new runtime from this bundle.
 func (b *Bundle) Instantiate() (bi *BundleInstance, instErr error) {
 	// Placeholder for a real context.
@@ -197,7 +221,8 @@ func (b *Bundle) Instantiate() (bi *BundleInstance, instErr error) {
 	// runtime, but no state, to allow module-provided types to function within the init context.
 	rt := goja.New()
 	init := newBoundInitContext(b.BaseInitContext, ctxPtr, rt)
-	if err := b.instantiate(rt, init); err != nil {
+	loop := common.NewEventLoop()
+	if err := b.instantiate(rt, init, loop); err != nil {
 		return nil, err
 	}
 
@@ -223,21 +248,26 @@ func (b *Bundle) Instantiate() (bi *BundleInstance, instErr error) {
 	})
 
 	return &BundleInstance{
-		Runtime: rt,
-		Context: ctxPtr,
-		Default: def,
+		Runtime:   rt,
+		Context:   ctxPtr,
+		Default:   def,
+		EventLoop: loop,
 	}, instErr
 }
 
 // Instantiates the bundle into an existing runtime. Not public because it also messes with a bunch
 // of other things, will potentially thrash data and makes a mess in it if the operation fails.
-func (b *Bundle) instantiate(rt *goja.Runtime, init *InitContext) error {
+func (b *Bundle) instantiate(rt *goja.Runtime, init *InitContext, loop *common.EventLoop) error {
 	rt.SetFieldNameMapper(common.FieldNameMapper{})
 	rt.SetRandSource(common.NewRandSource())
 
 	if _, err := rt.RunProgram(jslib.GetCoreJS()); err != nil {
 		return err
 	}
+	if _, err := rt.RunProgram(jslib.GetRegeneratorRuntime()); err != nil {
+		return err
+	}
+	bindTimers(rt, loop)
 
 	exports := rt.NewObject()
 	rt.Set("exports", exports)
@@ -247,9 +277,17 @@ func (b *Bundle) instantiate(rt *goja.Runtime, init *InitContext) error {
 
 	rt.Set("__ENV", b.Env)
 
-	*init.ctxPtr = common.WithRuntime(context.Background(), rt)
+	*init.ctxPtr = common.WithInitEnv(
+		common.WithEventLoop(common.WithRuntime(context.Background(), rt), loop),
+		initEnvironment{init},
+	)
 	unbindInit := common.BindToGlobal(rt, common.Bind(rt, init, init.ctxPtr))
-	if _, err := rt.RunProgram(b.Program); err != nil {
+	// Any timers and promises in the init context are done with before the VU is ready.
+	err := loop.Run(context.Background(), func() error {
+		_, err := rt.RunProgram(b.Program)
+		return err
+	})
+	if err != nil {
 		return err
 	}
 	unbindInit()
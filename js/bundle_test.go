@@ -21,6 +21,7 @@
 package js
 
 import (
+	"context"
 	"crypto/tls"
 	"fmt"
 	"io/ioutil"
@@ -415,6 +416,7 @@ func TestNewBundleFromArchive(t *testing.T) {
 	assert.NoError(t, err)
 	assert.Equal(t, `hi`, string(fileData))
 	assert.Equal(t, consts.Version, arc.K6Version)
+	assert.Len(t, arc.Transpiled, 2, "both the script and the module need to be transpiled")
 
 	b2, err := NewBundleFromArchive(arc, lib.RuntimeOptions{})
 	if !assert.NoError(t, err) {
@@ -433,6 +435,12 @@ func TestNewBundleFromArchive(t *testing.T) {
 	assert.Equal(t, "hi!", v2.Export())
 }
 
+func TestBundleMakeArchiveES5(t *testing.T) {
+	b, err := getSimpleBundle("/script.js", `module.exports.default = function() {};`)
+	require.NoError(t, err)
+	assert.Nil(t, b.makeArchive().Transpiled)
+}
+
 func TestOpen(t *testing.T) {
 	var testCases = [...]struct {
 		name           string
@@ -586,6 +594,53 @@ func TestOpen(t *testing.T) {
 	}
 }
 
+func TestOpenStream(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/path/to", 0755))
+	require.NoError(t, afero.WriteFile(fs, "/path/to/file.txt", []byte("a\nb\nc\n"), 0644))
+
+	sourceBundle, err := getSimpleBundleWithFs("/path/to/script.js", `
+		import fs from "k6/experimental/fs";
+		let file = fs.open("./file.txt");
+		export default function() { return file.readLine(); };
+	`, afero.NewReadOnlyFs(fs))
+	require.NoError(t, err)
+
+	arcBundle, err := NewBundleFromArchive(sourceBundle.makeArchive(), lib.RuntimeOptions{})
+	require.NoError(t, err)
+
+	for source, b := range map[string]*Bundle{"source": sourceBundle, "archive": arcBundle} {
+		b := b
+		t.Run(source, func(t *testing.T) {
+			for i := 0; i < 2; i++ {
+				// Each instance reads the file from the start
+				bi, err := b.Instantiate()
+				require.NoError(t, err)
+				var lines []interface{}
+				for j := 0; j < 4; j++ {
+					v, err := bi.Default(goja.Undefined())
+					require.NoError(t, err)
+					lines = append(lines, v.Export())
+				}
+				assert.Equal(t, []interface{}{"a", "b", "c", nil}, lines)
+			}
+		})
+	}
+
+	t.Run("NotInInitContext", func(t *testing.T) {
+		b, err := getSimpleBundleWithFs("/path/to/script.js", `
+			import fs from "k6/experimental/fs";
+			export default function() { fs.open("./file.txt"); };
+		`, afero.NewReadOnlyFs(fs))
+		require.NoError(t, err)
+		bi, err := b.Instantiate()
+		require.NoError(t, err)
+		*bi.Context = context.Background()
+		_, err = bi.Default(goja.Undefined())
+		assert.Contains(t, err.Error(), "files can only be opened in the init context")
+	})
+}
+
 func TestBundleInstantiate(t *testing.T) {
 	b, err := getSimpleBundle("/script.js", `
 		let val = true;
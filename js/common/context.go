@@ -24,14 +24,26 @@ import (
 	"context"
 
 	"github.com/dop251/goja"
+	"github.com/spf13/afero"
 )
 
 type ctxKey int
 
 const (
 	ctxKeyRuntime ctxKey = iota
+	ctxKeyEventLoop
+	ctxKeyInitEnv
 )
 
+// InitEnvironment gives builtin modules access to what's only available in the init context.
+type InitEnvironment interface {
+	// OpenFile opens a file the same way open() does: relative to the script or module whose init
+	// code is running, and through the filesystem that's saved in archives. Unlike with open(), a
+	// file on the disk isn't copied to the memory, it's read as it's needed and only its path is
+	// kept until an archive is written.
+	OpenFile(filename string) (afero.File, error)
+}
+
 func WithRuntime(ctx context.Context, rt *goja.Runtime) context.Context {
 	return context.WithValue(ctx, ctxKeyRuntime, rt)
 }
@@ -43,3 +55,32 @@ func GetRuntime(ctx context.Context) *goja.Runtime {
 	}
 	return v.(*goja.Runtime)
 }
+
+// WithEventLoop attaches the event loop of the VU's runtime to the context, for modules that need
+// to schedule callbacks in it, e.g. to resolve promises once an asynchronous operation is done.
+func WithEventLoop(ctx context.Context, loop *EventLoop) context.Context {
+	return context.WithValue(ctx, ctxKeyEventLoop, loop)
+}
+
+// GetEventLoop returns the event loop attached to the context, or nil.
+func GetEventLoop(ctx context.Context) *EventLoop {
+	v := ctx.Value(ctxKeyEventLoop)
+	if v == nil {
+		return nil
+	}
+	return v.(*EventLoop)
+}
+
+// WithInitEnv attaches the init environment to the context of the init code.
+func WithInitEnv(ctx context.Context, env InitEnvironment) context.Context {
+	return context.WithValue(ctx, ctxKeyInitEnv, env)
+}
+
+// GetInitEnv returns the init environment attached to the context, or nil outside of the init code.
+func GetInitEnv(ctx context.Context) InitEnvironment {
+	v := ctx.Value(ctxKeyInitEnv)
+	if v == nil {
+		return nil
+	}
+	return v.(InitEnvironment)
+}
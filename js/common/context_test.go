@@ -36,3 +36,12 @@ func TestContextRuntime(t *testing.T) {
 func TestContextRuntimeNil(t *testing.T) {
 	assert.Nil(t, GetRuntime(context.Background()))
 }
+
+func TestContextEventLoop(t *testing.T) {
+	loop := NewEventLoop()
+	assert.Equal(t, loop, GetEventLoop(WithEventLoop(context.Background(), loop)))
+}
+
+func TestContextEventLoopNil(t *testing.T) {
+	assert.Nil(t, GetEventLoop(context.Background()))
+}
@@ -0,0 +1,199 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package common
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// An EventLoop runs the callbacks of timers and other asynchronous operations in a VU's runtime.
+// Callbacks can be queued from any goroutine, but they're only ever called from the one that's
+// in Run(), one at a time, since the runtime isn't safe for concurrent use.
+type EventLoop struct {
+	lock    sync.Mutex
+	queue   []func() error
+	pending int // Reserved callbacks and active timers, which keep Run() waiting
+	gen     uint64
+	wakeup  chan struct{}
+
+	timers      map[int64]*time.Timer
+	lastTimerID int64
+}
+
+// NewEventLoop returns a new EventLoop.
+func NewEventLoop() *EventLoop {
+	return &EventLoop{
+		wakeup: make(chan struct{}, 1),
+		timers: make(map[int64]*time.Timer),
+	}
+}
+
+// Run calls fn and then every queued callback, until there are no more callbacks queued, reserved
+// or waiting on a timer, a callback returns an error, or the context is done. Whatever is still
+// pending by then is dropped, so nothing carries over to the next Run().
+func (e *EventLoop) Run(ctx context.Context, fn func() error) error {
+	defer e.reset()
+
+	if err := fn(); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		default:
+		}
+
+		e.lock.Lock()
+		queue, pending := e.queue, e.pending
+		e.queue = nil
+		e.lock.Unlock()
+
+		for _, callback := range queue {
+			if err := callback(); err != nil {
+				return err
+			}
+		}
+		if len(queue) > 0 {
+			continue
+		}
+		if pending == 0 {
+			return nil
+		}
+
+		select {
+		case <-e.wakeup:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Reserve keeps the current Run() going until the returned function is called, which queues the
+// callback that finishes the asynchronous operation, if it isn't nil. It's safe to call from any
+// goroutine, but only the first call counts.
+func (e *EventLoop) Reserve() func(callback func() error) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.pending++
+	gen := e.gen
+
+	var once sync.Once
+	return func(callback func() error) {
+		once.Do(func() {
+			e.lock.Lock()
+			defer e.lock.Unlock()
+			if gen != e.gen {
+				return
+			}
+			e.pending--
+			e.enqueue(callback)
+		})
+	}
+}
+
+// SetTimer calls fn after the delay, or every delay if repeat is set, until the timer is cleared
+// or the current Run() ends. It returns the timer's ID, which is never 0.
+func (e *EventLoop) SetTimer(fn func() error, delay time.Duration, repeat bool) int64 {
+	if delay < 0 {
+		delay = 0
+	}
+	if repeat && delay < time.Millisecond {
+		delay = time.Millisecond // Intervals would starve everything else otherwise
+	}
+
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	e.lastTimerID++
+	id, gen := e.lastTimerID, e.gen
+	e.pending++
+
+	// A timeout stays active until its callback has run, so clearing it after it has fired, but
+	// before the callback had its turn in the loop, still cancels it.
+	callback := func() error {
+		e.lock.Lock()
+		_, active := e.timers[id]
+		if active && !repeat {
+			delete(e.timers, id)
+			e.pending--
+		}
+		e.lock.Unlock()
+		if !active {
+			return nil
+		}
+		return fn()
+	}
+
+	var fire func()
+	fire = func() {
+		e.lock.Lock()
+		defer e.lock.Unlock()
+		if _, active := e.timers[id]; !active || gen != e.gen {
+			return
+		}
+		if repeat {
+			e.timers[id] = time.AfterFunc(delay, fire)
+		}
+		e.enqueue(callback)
+	}
+	e.timers[id] = time.AfterFunc(delay, fire)
+	return id
+}
+
+// ClearTimer stops the timer with the given ID; unknown IDs are ignored.
+func (e *EventLoop) ClearTimer(id int64) {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	if t, ok := e.timers[id]; ok {
+		t.Stop()
+		delete(e.timers, id)
+		e.pending--
+	}
+}
+
+// enqueue must be called with the lock held.
+func (e *EventLoop) enqueue(callback func() error) {
+	if callback != nil {
+		e.queue = append(e.queue, callback)
+	}
+	select {
+	case e.wakeup <- struct{}{}:
+	default:
+	}
+}
+
+func (e *EventLoop) reset() {
+	e.lock.Lock()
+	defer e.lock.Unlock()
+	for _, t := range e.timers {
+		t.Stop()
+	}
+	e.timers = make(map[int64]*time.Timer)
+	e.queue = nil
+	e.pending = 0
+	e.gen++
+	select {
+	case <-e.wakeup:
+	default:
+	}
+}
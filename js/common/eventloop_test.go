@@ -0,0 +1,118 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package common
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventLoopReserve(t *testing.T) {
+	loop := NewEventLoop()
+	var calls []string
+	err := loop.Run(context.Background(), func() error {
+		done := loop.Reserve()
+		go func() {
+			time.Sleep(10 * time.Millisecond)
+			done(func() error {
+				calls = append(calls, "reserved")
+				return nil
+			})
+			done(func() error {
+				calls = append(calls, "twice")
+				return nil
+			})
+		}()
+		loop.Reserve()(nil)
+		calls = append(calls, "fn")
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"fn", "reserved"}, calls)
+}
+
+func TestEventLoopTimers(t *testing.T) {
+	loop := NewEventLoop()
+	var calls []string
+	err := loop.Run(context.Background(), func() error {
+		ticks := 0
+		var interval int64
+		interval = loop.SetTimer(func() error {
+			calls = append(calls, "tick")
+			if ticks++; ticks == 2 {
+				loop.ClearTimer(interval)
+			}
+			return nil
+		}, time.Millisecond, true)
+		loop.SetTimer(func() error {
+			calls = append(calls, "timeout")
+			return nil
+		}, 20*time.Millisecond, false)
+		loop.ClearTimer(loop.SetTimer(func() error {
+			calls = append(calls, "cleared")
+			return nil
+		}, 0, false))
+		loop.ClearTimer(1234)
+		return nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"tick", "tick", "timeout"}, calls)
+}
+
+func TestEventLoopErrors(t *testing.T) {
+	loop := NewEventLoop()
+	err := loop.Run(context.Background(), func() error { return errors.New("fn") })
+	assert.EqualError(t, err, "fn")
+
+	called := false
+	err = loop.Run(context.Background(), func() error {
+		loop.SetTimer(func() error { return errors.New("timer") }, 0, false)
+		loop.SetTimer(func() error {
+			called = true
+			return nil
+		}, 10*time.Millisecond, false)
+		return nil
+	})
+	assert.EqualError(t, err, "timer")
+	assert.False(t, called)
+}
+
+func TestEventLoopCancel(t *testing.T) {
+	loop := NewEventLoop()
+	ctx, cancel := context.WithCancel(context.Background())
+	var done func(func() error)
+	err := loop.Run(ctx, func() error {
+		done = loop.Reserve()
+		loop.SetTimer(func() error { return nil }, time.Millisecond, true)
+		cancel()
+		return nil
+	})
+	assert.Equal(t, context.Canceled, err)
+
+	// Nothing from the cancelled run carries over to the next one
+	done(func() error { return errors.New("stale") })
+	assert.NoError(t, loop.Run(context.Background(), func() error { return nil }))
+}
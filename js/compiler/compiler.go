@@ -23,12 +23,20 @@
 package compiler
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/GeertJohan/go.rice"
 	"github.com/dop251/goja"
 	"github.com/dop251/goja/parser"
+	"github.com/loadimpact/k6/lib/consts"
 	"github.com/mitchellh/mapstructure"
 	log "github.com/sirupsen/logrus"
 )
@@ -37,59 +45,77 @@ var (
 	DefaultOpts = map[string]interface{}{
 		"presets":       []string{"latest"},
 		"ast":           false,
-		"sourceMaps":    false,
+		"sourceMaps":    true,
 		"babelrc":       false,
 		"compact":       false,
 		"retainLines":   true,
 		"highlightCode": false,
 	}
 
+	// CacheDir is where transformed code is saved, so it doesn't have to go through Babel again in
+	// later runs. The files are only ever read back if they match the source, Babel options and k6
+	// version exactly, so the directory can be cleared at any time. It's not used if it's empty.
+	CacheDir string
+
 	compilerInstance *Compiler
 	once             sync.Once
 )
 
 // A Compiler uses Babel to compile ES6 code into something ES5-compatible.
 type Compiler struct {
-	vm *goja.Runtime
-
-	// JS pointers.
+	// JS pointers, only set once Babel is loaded, since that takes a while.
+	vm        *goja.Runtime
 	this      goja.Value
 	transform goja.Callable
-	mutex     sync.Mutex //TODO: cache goja.CompileAST() in an init() function?
+
+	mutex sync.Mutex //TODO: cache goja.CompileAST() in an init() function?
+	cache map[string]transformed
+}
+
+// transformed is the output of Babel for a source file, as saved in the cache.
+type transformed struct {
+	Code      string    `json:"code"`
+	SourceMap SourceMap `json:"map"`
 }
 
 // Constructs a new compiler.
 func New() (*Compiler, error) {
-	var err error
 	once.Do(func() {
-		compilerInstance, err = new()
+		compilerInstance = &Compiler{cache: make(map[string]transformed)}
 	})
 
-	return compilerInstance, err
+	return compilerInstance, nil
 }
 
-func new() (*Compiler, error) {
+// loadBabel loads Babel into the compiler's runtime, if it isn't already; the mutex must be held.
+func (c *Compiler) loadBabel() error {
+	if c.vm != nil {
+		return nil
+	}
+
 	conf := rice.Config{
 		LocateOrder: []rice.LocateMethod{rice.LocateEmbedded},
 	}
-
 	babelSrc := conf.MustFindBox("lib").MustString("babel.min.js")
 
-	c := &Compiler{vm: goja.New()}
-	if _, err := c.vm.RunString(babelSrc); err != nil {
-		return nil, err
+	startTime := time.Now()
+	vm := goja.New()
+	if _, err := vm.RunString(babelSrc); err != nil {
+		return err
 	}
 
-	c.this = c.vm.Get("Babel")
-	thisObj := c.this.ToObject(c.vm)
-	if err := c.vm.ExportTo(thisObj.Get("transform"), &c.transform); err != nil {
-		return nil, err
+	this := vm.Get("Babel")
+	thisObj := this.ToObject(vm)
+	if err := vm.ExportTo(thisObj.Get("transform"), &c.transform); err != nil {
+		return err
 	}
-
-	return c, nil
+	c.vm, c.this = vm, this
+	log.WithField("t", time.Since(startTime)).Debug("Babel: Loaded")
+	return nil
 }
 
-// Transform the given code into ES5.
+// Transform the given code into ES5. The results are cached by the source, in memory and in the
+// CacheDir, if set.
 func (c *Compiler) Transform(src, filename string) (code string, srcmap SourceMap, err error) {
 	opts := make(map[string]interface{})
 	for k, v := range DefaultOpts {
@@ -100,6 +126,22 @@ func (c *Compiler) Transform(src, filename string) (code string, srcmap SourceMa
 	c.mutex.Lock()
 	defer c.mutex.Unlock()
 
+	key, err := cacheKey(src)
+	if err != nil {
+		return code, srcmap, err
+	}
+	if t, ok := c.cache[key]; ok {
+		return t.Code, t.SourceMap, nil
+	}
+	if t, ok := readCache(key); ok {
+		c.cache[key] = t
+		return t.Code, t.SourceMap, nil
+	}
+
+	if err := c.loadBabel(); err != nil {
+		return code, srcmap, err
+	}
+
 	startTime := time.Now()
 	v, err := c.transform(c.this, c.vm.ToValue(src), c.vm.ToValue(opts))
 	if err != nil {
@@ -120,9 +162,120 @@ func (c *Compiler) Transform(src, filename string) (code string, srcmap SourceMa
 		return code, srcmap, err
 	}
 
+	t := transformed{Code: code, SourceMap: srcmap}
+	c.cache[key] = t
+	writeCache(key, t)
 	return code, srcmap, nil
 }
 
+// Transpiled returns the cache key and the cached output of Babel for the given source, if it has
+// been transformed, so it can be saved elsewhere and added back with AddTranspiled().
+func (c *Compiler) Transpiled(src string) (string, json.RawMessage, bool) {
+	key, err := cacheKey(src)
+	if err != nil {
+		return "", nil, false
+	}
+
+	c.mutex.Lock()
+	t, ok := c.cache[key]
+	c.mutex.Unlock()
+	if !ok {
+		return "", nil, false
+	}
+	data, err := json.Marshal(t)
+	if err != nil {
+		return "", nil, false
+	}
+	return key, data, true
+}
+
+// AddTranspiled adds the output of Babel saved with Transpiled() to the cache, so the sources it
+// came from don't have to be transformed again. Entries from other k6 versions are never matched,
+// since the version is part of the key, and invalid ones are ignored.
+func (c *Compiler) AddTranspiled(entries map[string]json.RawMessage) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	for key, data := range entries {
+		if _, ok := c.cache[key]; ok {
+			continue
+		}
+		var t transformed
+		if err := json.Unmarshal(data, &t); err != nil {
+			log.WithError(err).Debug("Babel: Ignoring invalid transpiled code")
+			continue
+		}
+		c.cache[key] = t
+	}
+}
+
+// cacheKey hashes everything that the output of Babel depends on, except for the filename, since
+// it's only used in error messages and source maps. The k6 version stands in for the version of
+// Babel itself.
+func cacheKey(src string) (string, error) {
+	optsData, err := json.Marshal(DefaultOpts)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	for _, part := range []string{consts.Version, string(optsData), src} {
+		_, _ = h.Write([]byte(part))
+		_, _ = h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+func readCache(key string) (transformed, bool) {
+	var t transformed
+	if CacheDir == "" {
+		return t, false
+	}
+	data, err := ioutil.ReadFile(filepath.Join(CacheDir, key+".json"))
+	if err != nil {
+		return t, false
+	}
+	if err := json.Unmarshal(data, &t); err != nil {
+		log.WithError(err).Debug("Babel: Ignoring a corrupted cache file")
+		return t, false
+	}
+	return t, true
+}
+
+// writeCache saves the transformed code in the CacheDir. The cache is only an optimization, so
+// errors are logged, but otherwise ignored.
+func writeCache(key string, t transformed) {
+	if CacheDir == "" {
+		return
+	}
+	err := func() error {
+		data, err := json.Marshal(t)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(CacheDir, 0755); err != nil {
+			return err
+		}
+		// Written to a temporary file first, so concurrent runs never read a partial file.
+		f, err := ioutil.TempFile(CacheDir, key+".*.tmp")
+		if err != nil {
+			return err
+		}
+		_, err = f.Write(data)
+		if cerr := f.Close(); err == nil {
+			err = cerr
+		}
+		if err == nil {
+			err = os.Rename(f.Name(), filepath.Join(CacheDir, key+".json"))
+		}
+		if err != nil {
+			_ = os.Remove(f.Name())
+		}
+		return err
+	}()
+	if err != nil {
+		log.WithError(err).Debug("Babel: Couldn't save the transformed code in the cache")
+	}
+}
+
 // Compiles the program, first trying ES5, then ES6.
 func (c *Compiler) Compile(src, filename string, pre, post string, strict bool) (*goja.Program, string, error) {
 	return c.compile(src, filename, pre, post, strict, true)
@@ -133,14 +286,44 @@ func (c *Compiler) compile(src, filename string, pre, post string, strict, tryBa
 	ast, err := parser.ParseFile(nil, filename, code, 0)
 	if err != nil {
 		if tryBabel {
-			code, _, err := c.Transform(src, filename)
+			code, srcmap, err := c.Transform(src, filename)
 			if err != nil {
 				return nil, code, err
 			}
-			return c.compile(code, filename, pre, post, strict, false)
+			return c.compileTransformed(code, srcmap, filename, pre, post, strict)
 		}
 		return nil, src, err
 	}
+	annotateConsoleCalls(ast, filename, pre)
+	pgm, err := goja.CompileAST(ast, strict)
+	return pgm, code, err
+}
+
+// compileTransformed compiles code transformed by Babel with its source map inlined, so the
+// positions in exceptions and stack traces point to the original source. The returned code
+// doesn't include the source map.
+func (c *Compiler) compileTransformed(
+	src string, srcmap SourceMap, filename string, pre, post string, strict bool,
+) (*goja.Program, string, error) {
+	code := pre + src + post
+	comment, err := srcmap.inlineComment(pre)
+	if err != nil {
+		// Errors will just point to the transformed code, which at least has the same lines.
+		log.WithError(err).Debug("Babel: Ignoring an invalid source map")
+	}
+	mapped := code
+	if comment != "" {
+		if !strings.HasSuffix(mapped, "\n") {
+			mapped += "\n"
+		}
+		mapped += comment
+	}
+
+	ast, err := parser.ParseFile(nil, filename, mapped, 0)
+	if err != nil {
+		return nil, src, err
+	}
+	annotateConsoleCalls(ast, filename, pre)
 	pgm, err := goja.CompileAST(ast, strict)
 	return pgm, code, err
 }
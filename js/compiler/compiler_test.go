@@ -20,11 +20,17 @@
 package compiler
 
 import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
 	"strings"
 	"testing"
 
 	"github.com/dop251/goja"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestNew(t *testing.T) {
@@ -77,6 +83,69 @@ func TestTransform(t *testing.T) {
 	})
 }
 
+func TestTransformCache(t *testing.T) {
+	dir, err := ioutil.TempDir("", "k6-babel-cache")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+	defer func(prev string) { CacheDir = prev }(CacheDir)
+	CacheDir = dir
+
+	c := &Compiler{cache: make(map[string]transformed)}
+	src, _, err := c.Transform("let a = () => 1;", "cached.js")
+	require.NoError(t, err)
+	assert.Equal(t, `"use strict";var a = function a() {return 1;};`, src)
+	files, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	require.NoError(t, err)
+	require.Len(t, files, 1)
+
+	t.Run("Memory", func(t *testing.T) {
+		require.NoError(t, os.Remove(files[0]))
+		cached, _, err := c.Transform("let a = () => 1;", "cached.js")
+		require.NoError(t, err)
+		assert.Equal(t, src, cached)
+		require.NoError(t, ioutil.WriteFile(files[0], []byte(`{"code": "cached on disk"}`), 0644))
+	})
+
+	t.Run("Disk", func(t *testing.T) {
+		c := &Compiler{cache: make(map[string]transformed)}
+		cached, _, err := c.Transform("let a = () => 1;", "cached.js")
+		require.NoError(t, err)
+		assert.Equal(t, "cached on disk", cached)
+		assert.Nil(t, c.vm, "babel shouldn't have been loaded")
+
+		other, _, err := c.Transform("let a = () => 2;", "cached.js")
+		require.NoError(t, err)
+		assert.Equal(t, `"use strict";var a = function a() {return 2;};`, other)
+	})
+
+	t.Run("Corrupted", func(t *testing.T) {
+		require.NoError(t, ioutil.WriteFile(files[0], []byte(`{"code":`), 0644))
+		c := &Compiler{cache: make(map[string]transformed)}
+		cached, _, err := c.Transform("let a = () => 1;", "cached.js")
+		require.NoError(t, err)
+		assert.Equal(t, src, cached)
+	})
+}
+
+func TestTranspiled(t *testing.T) {
+	c := &Compiler{cache: make(map[string]transformed)}
+	_, _, ok := c.Transpiled("let a = () => 1;")
+	assert.False(t, ok)
+
+	src, _, err := c.Transform("let a = () => 1;", "script.js")
+	require.NoError(t, err)
+	key, data, ok := c.Transpiled("let a = () => 1;")
+	require.True(t, ok)
+
+	other := &Compiler{cache: make(map[string]transformed)}
+	other.AddTranspiled(map[string]json.RawMessage{key: data, "invalid": json.RawMessage(`[]`)})
+	transpiled, _, err := other.Transform("let a = () => 1;", "other/script.js")
+	require.NoError(t, err)
+	assert.Equal(t, src, transpiled)
+	assert.Nil(t, other.vm, "babel shouldn't have been loaded")
+	assert.Len(t, other.cache, 1)
+}
+
 func TestCompile(t *testing.T) {
 	c, err := New()
 	if !assert.NoError(t, err) {
@@ -160,3 +229,83 @@ func TestCompile(t *testing.T) {
 		})
 	})
 }
+
+func TestCompileSourceMap(t *testing.T) {
+	c, err := New()
+	require.NoError(t, err)
+
+	src := "let a = 1;\nconst f = () => {\n    let x = `t`; throw new Error(\"boom\");\n};\nf();\n"
+	wrappers := map[string][2]string{
+		"NoWrap":      {"", ""},
+		"Wrap":        {"(function(module, exports){", "\n})\n"},
+		"WrapNewline": {"(function(module, exports){\n", "\n})\n"},
+	}
+	for name, wrapper := range wrappers {
+		wrapper := wrapper
+		t.Run(name, func(t *testing.T) {
+			pgm, code, err := c.Compile(src, "file:///script.js", wrapper[0], wrapper[1], true)
+			require.NoError(t, err)
+			assert.NotContains(t, code, "sourceMappingURL")
+
+			v, err := goja.New().RunProgram(pgm)
+			if fn, ok := goja.AssertFunction(v); ok && err == nil {
+				_, err = fn(goja.Undefined())
+			}
+			require.IsType(t, &goja.Exception{}, err)
+			stack := err.(*goja.Exception).String()
+			assert.Contains(t, stack, "at f (file:///script.js:3:24(")
+			assert.Contains(t, stack, "at file:///script.js:5:1(")
+		})
+	}
+}
+
+func TestCompileConsoleCalls(t *testing.T) {
+	c, err := New()
+	require.NoError(t, err)
+
+	scripts := map[string]string{
+		"ES5": "var a = 1;\nconsole.log(a);\nconsole.info(console.warn(a));\nconsole.other(a);\n",
+		"ES6": "let a = 1;\nconsole.log(a);\nconsole.info(console.warn(a));\nconsole.other(a);\n",
+	}
+	for name, src := range scripts {
+		src := src
+		t.Run(name, func(t *testing.T) {
+			pgm, _, err := c.Compile(src, "file:///script.js", "(function(console){\n", "\n})\n", true)
+			require.NoError(t, err)
+
+			rt := goja.New()
+			v, err := rt.RunProgram(pgm)
+			require.NoError(t, err)
+			fn, ok := goja.AssertFunction(v)
+			require.True(t, ok, "not a function")
+
+			var calls []string
+			console := func(pos string) map[string]interface{} {
+				logger := func(method string) func(goja.Value) {
+					return func(v goja.Value) { calls = append(calls, method+"@"+pos+":"+v.String()) }
+				}
+				return map[string]interface{}{
+					"log": logger("log"), "info": logger("info"), "warn": logger("warn"), "other": logger("other"),
+				}
+			}
+			plain := console("")
+			plain["at"] = func(source string, line int64) map[string]interface{} {
+				return console(fmt.Sprintf("%s:%d", source, line))
+			}
+			_, err = fn(goja.Undefined(), rt.ToValue(plain))
+			require.NoError(t, err)
+			assert.Equal(t, []string{
+				"log@file:///script.js:2:1",
+				"warn@file:///script.js:3:1",
+				"info@file:///script.js:3:undefined",
+				"other@:1",
+			}, calls)
+
+			// Consoles without an at() method are called as they are
+			calls = nil
+			_, err = fn(goja.Undefined(), rt.ToValue(console("")))
+			require.NoError(t, err)
+			assert.Equal(t, []string{"log@:1", "warn@:1", "info@:undefined", "other@:1"}, calls)
+		})
+	}
+}
@@ -0,0 +1,143 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package compiler
+
+import (
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/dop251/goja/ast"
+	"github.com/dop251/goja/file"
+)
+
+// The console methods whose calls are annotated with their position.
+//nolint:gochecknoglobals
+var consoleMethods = map[string]bool{"log": true, "debug": true, "info": true, "warn": true, "error": true}
+
+// annotateConsoleCalls makes the console calls of a script log where they're made from. goja
+// doesn't expose the call stack to Go code, or to JS through Error.stack, so the position has to
+// be known at compile time: console.log(x) is compiled as if it was
+//
+//	(console.at ? console.at("file:///script.js", 12) : console).log(x)
+//
+// where console.at() returns a console that logs the given source and line. If the script shadows
+// the console with an object without an at() method, the calls are made as they are.
+func annotateConsoleCalls(prg *ast.Program, filename, pre string) {
+	lineOf := newLineResolver(prg, pre)
+	annotate := func(call *ast.CallExpression) {
+		dot, ok := call.Callee.(*ast.DotExpression)
+		if !ok || !consoleMethods[dot.Identifier.Name] {
+			return
+		}
+		console, ok := dot.Left.(*ast.Identifier)
+		if !ok || console.Name != "console" {
+			return
+		}
+		line := lineOf(console.Idx)
+		if line <= 0 {
+			return
+		}
+		consoleAt := func() *ast.DotExpression {
+			return &ast.DotExpression{
+				Left:       &ast.Identifier{Name: "console", Idx: console.Idx},
+				Identifier: ast.Identifier{Name: "at", Idx: console.Idx},
+			}
+		}
+		dot.Left = &ast.ConditionalExpression{
+			Test: consoleAt(),
+			Consequent: &ast.CallExpression{
+				Callee:          consoleAt(),
+				LeftParenthesis: console.Idx,
+				ArgumentList: []ast.Expression{
+					&ast.StringLiteral{Idx: console.Idx, Literal: strconv.Quote(filename), Value: filename},
+					&ast.NumberLiteral{Idx: console.Idx, Literal: strconv.Itoa(line), Value: int64(line)},
+				},
+				RightParenthesis: console.Idx,
+			},
+			Alternate: console,
+		}
+	}
+	walkCalls(reflect.ValueOf(prg.Body), annotate)
+	walkCalls(reflect.ValueOf(prg.DeclarationList), annotate)
+}
+
+// newLineResolver returns a function that returns the line of the original source at the given
+// index of the parsed code, going by its source map if it has one, or 0 if it's unknown.
+func newLineResolver(prg *ast.Program, pre string) func(idx file.Idx) int {
+	src := prg.File.Source()
+	var lineStarts []int
+	for i := 0; i < len(src); i++ {
+		if src[i] == '\n' {
+			lineStarts = append(lineStarts, i+1)
+		}
+	}
+	// Without a source map, only the lines of pre have to be skipped; with one, see inlineComment()
+	preLines := strings.Count(pre, "\n")
+
+	return func(idx file.Idx) int {
+		offset := int(idx) - prg.File.Base()
+		if offset < 0 || offset > len(src) {
+			return 0
+		}
+		line := sort.SearchInts(lineStarts, offset+1) // the number of lines that start before it
+		lineStart := 0
+		if line > 0 {
+			lineStart = lineStarts[line-1]
+		}
+		if prg.SourceMap != nil {
+			_, _, row, _, ok := prg.SourceMap.Source(line+1, offset-lineStart+1)
+			if !ok {
+				return 0
+			}
+			return row
+		}
+		return line + 1 - preLines
+	}
+}
+
+// walkCalls calls visit with every call expression in the given AST nodes, after visiting the ones
+// in its callee and arguments, so the visited expressions can be safely replaced.
+func walkCalls(v reflect.Value, visit func(*ast.CallExpression)) {
+	switch v.Kind() {
+	case reflect.Interface:
+		if !v.IsNil() {
+			walkCalls(v.Elem(), visit)
+		}
+	case reflect.Ptr:
+		if v.IsNil() {
+			return
+		}
+		walkCalls(v.Elem(), visit)
+		if call, ok := v.Interface().(*ast.CallExpression); ok {
+			visit(call)
+		}
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			walkCalls(v.Field(i), visit)
+		}
+	case reflect.Slice:
+		for i := 0; i < v.Len(); i++ {
+			walkCalls(v.Index(i), visit)
+		}
+	}
+}
@@ -20,11 +20,161 @@
 
 package compiler
 
+import (
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+
+	"github.com/pkg/errors"
+)
+
+// A SourceMap maps positions in the code generated by Babel back to the original source.
 type SourceMap struct {
-	Version    int
-	File       string
-	SourceRoot string
-	Sources    []string
-	Names      []string
-	Mappings   string
+	Version    int      `json:"version"`
+	File       string   `json:"file"`
+	SourceRoot string   `json:"sourceRoot"`
+	Sources    []string `json:"sources"`
+	Names      []string `json:"names"`
+	Mappings   string   `json:"mappings"`
+}
+
+const sourceMapPrefix = "//# sourceMappingURL=data:application/json;base64,"
+
+// inlineComment returns the source map as a comment that goja picks up when it's the last line of
+// a script, for a script where the mapped code comes after pre. Positions in pre aren't mapped.
+func (m SourceMap) inlineComment(pre string) (string, error) {
+	if m.Mappings == "" {
+		return "", nil
+	}
+
+	lines := strings.Count(pre, "\n")
+	cols := len(pre) - strings.LastIndex(pre, "\n") - 1
+	mappings, err := adjustMappings(m.Mappings, lines, cols)
+	if err != nil {
+		return "", err
+	}
+
+	data, err := json.Marshal(SourceMap{
+		Version:  m.Version,
+		File:     m.File,
+		Sources:  m.Sources,
+		Names:    []string{},
+		Mappings: mappings,
+	})
+	if err != nil {
+		return "", err
+	}
+	return sourceMapPrefix + base64.StdEncoding.EncodeToString(data), nil
+}
+
+// adjustMappings shifts the generated positions of the mappings by the given number of lines, and
+// by the given number of columns on what becomes the first mapped line. It also works around how
+// goja and its source map parser handle the mappings:
+//   - goja looks up 1-based columns, and reports the 0-based ones it gets back as they are, so both
+//     are made 1-based, like the columns of code without a source map
+//   - positions after the last segment aren't found, so an empty line is added at the end
+//   - names aren't supported, so they're dropped
+func adjustMappings(mappings string, lines, cols int) (string, error) {
+	adjustedSourceCol := false
+	out := strings.Split(mappings, ";")
+	for i, line := range out {
+		if line == "" {
+			continue
+		}
+		segments := strings.Split(line, ",")
+		for j, segment := range segments {
+			fields, err := decodeSegment(segment)
+			if err != nil {
+				return "", err
+			}
+			if len(fields) > 4 {
+				fields = fields[:4]
+			}
+			// Only the first segment on a line has an absolute generated column, and only the very
+			// first source column is absolute, everything else is relative to the segment before.
+			if j == 0 {
+				fields[0]++
+				if i == 0 {
+					fields[0] += cols
+				}
+			}
+			if len(fields) == 4 && !adjustedSourceCol {
+				fields[3]++
+				adjustedSourceCol = true
+			}
+			segments[j] = encodeSegment(fields)
+		}
+		out[i] = strings.Join(segments, ",")
+	}
+	return strings.Repeat(";", lines) + strings.Join(out, ";") + ";", nil
+}
+
+func decodeSegment(segment string) ([]int, error) {
+	var fields []int
+	for segment != "" {
+		value, n, err := decodeVLQ(segment)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, value)
+		segment = segment[n:]
+	}
+	return fields, nil
+}
+
+func encodeSegment(fields []int) string {
+	var b strings.Builder
+	for _, value := range fields {
+		b.WriteString(encodeVLQ(value))
+	}
+	return b.String()
+}
+
+const vlqChars = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789+/"
+
+const (
+	vlqShift    = 5
+	vlqContinue = 1 << vlqShift
+	vlqMask     = vlqContinue - 1
+)
+
+// decodeVLQ decodes the base64 VLQ value at the start of s, returning it and its length.
+func decodeVLQ(s string) (int, int, error) {
+	var value int
+	var shift uint
+	for n := 0; n < len(s); n++ {
+		digit := strings.IndexByte(vlqChars, s[n])
+		if digit < 0 {
+			return 0, 0, errors.Errorf("invalid character '%c' in the source map mappings", s[n])
+		}
+		value += (digit & vlqMask) << shift
+		if digit&vlqContinue == 0 {
+			if value&1 != 0 {
+				return -(value >> 1), n + 1, nil
+			}
+			return value >> 1, n + 1, nil
+		}
+		shift += vlqShift
+	}
+	return 0, 0, errors.New("unterminated value in the source map mappings")
+}
+
+func encodeVLQ(value int) string {
+	v := value << 1
+	if value < 0 {
+		v = (-value << 1) | 1
+	}
+
+	var b strings.Builder
+	for {
+		digit := v & vlqMask
+		v >>= vlqShift
+		if v > 0 {
+			digit |= vlqContinue
+		}
+		b.WriteByte(vlqChars[digit])
+		if v == 0 {
+			return b.String()
+		}
+	}
 }
@@ -0,0 +1,71 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package compiler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVLQ(t *testing.T) {
+	values := map[string]int{"A": 0, "C": 1, "D": -1, "2H": 123, "3H": -123, "gqjG": 100000}
+	for s, value := range values {
+		assert.Equal(t, s, encodeVLQ(value), "%d", value)
+		decoded, n, err := decodeVLQ(s + "A")
+		if assert.NoError(t, err, s) {
+			assert.Equal(t, value, decoded, s)
+			assert.Equal(t, len(s), n, s)
+		}
+	}
+
+	_, _, err := decodeVLQ("g")
+	assert.Error(t, err)
+	_, _, err = decodeVLQ("!")
+	assert.Error(t, err)
+}
+
+func TestAdjustMappings(t *testing.T) {
+	testdata := map[string]struct {
+		mappings    string
+		lines, cols int
+		expected    string
+	}{
+		"Empty":     {"", 0, 0, ";"},
+		"Columns":   {"AAAA,EAAE;AACA", 0, 0, "CAAC,EAAE;CACA;"},
+		"Offset":    {"AAAA,EAAE;AACA", 2, 10, ";;WAAC,EAAE;CACA;"},
+		"Names":     {"AAAAA,EAAEC", 0, 0, "CAAC,EAAE;"},
+		"NoSource":  {"A,EAAA", 0, 0, "C,EAAC;"},
+		"EmptyLine": {"AAAA;;AACA", 1, 0, ";CAAC;;CACA;"},
+	}
+	for name, data := range testdata {
+		data := data
+		t.Run(name, func(t *testing.T) {
+			mappings, err := adjustMappings(data.mappings, data.lines, data.cols)
+			require.NoError(t, err)
+			assert.Equal(t, data.expected, mappings)
+		})
+	}
+
+	_, err := adjustMappings("AAAg", 0, 0)
+	assert.Error(t, err)
+}
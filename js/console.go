@@ -24,19 +24,44 @@ import (
 	"context"
 	"os"
 	"strconv"
+	"strings"
 
 	"github.com/dop251/goja"
+	"github.com/loadimpact/k6/js/common"
+	"github.com/loadimpact/k6/lib"
+	"github.com/loadimpact/k6/lib/logging"
 	log "github.com/sirupsen/logrus"
 )
 
+// Each distinct console message can be logged consoleLogRate times per second on average by
+// default, in bursts of up to consoleLogBurstFactor times that, and it's dropped beyond that.
+const (
+	consoleLogRate        = 10
+	consoleLogBurstFactor = 10
+)
+
 // console represents a JS console implemented as a logrus.Logger.
 type console struct {
-	Logger *log.Logger
+	Logger  *log.Logger
+	limiter *logging.Limiter // nil if the messages aren't rate-limited
+
+	// Where the messages are logged from, see at(); source is empty if it's not known.
+	source string
+	line   int64
 }
 
 // Creates a console with the standard logrus logger.
 func newConsole() *console {
-	return &console{log.StandardLogger()}
+	return &console{Logger: log.StandardLogger(), limiter: newConsoleLimiter(consoleLogRate)}
+}
+
+// newConsoleLimiter returns a limiter for the given number of times per second that each message
+// can be logged, or nil if it's not positive.
+func newConsoleLimiter(perSecond int64) *logging.Limiter {
+	if perSecond <= 0 {
+		return nil
+	}
+	return logging.NewLimiter(float64(perSecond), int(perSecond*consoleLogBurstFactor))
 }
 
 // Creates a console logger with its output set to the file at the provided `filepath`.
@@ -52,7 +77,20 @@ func newFileConsole(filepath string) (*console, error) {
 	//TODO: refactor to not rely on global variables, albeit external ones
 	l.SetFormatter(log.StandardLogger().Formatter)
 
-	return &console{l}, nil
+	return &console{Logger: l, limiter: newConsoleLimiter(consoleLogRate)}, nil
+}
+
+// bind returns the JS object of the console, with an at() method that returns a console that
+// logs the given source and line. Compiled scripts call it for every console call, see
+// compiler.annotateConsoleCalls().
+func (c *console) bind(rt *goja.Runtime, ctxPtr *context.Context) map[string]interface{} {
+	obj := common.Bind(rt, c, ctxPtr)
+	obj["at"] = func(source string, line int64) map[string]interface{} {
+		at := *c
+		at.source, at.line = source, line
+		return common.Bind(rt, &at, ctxPtr)
+	}
+	return obj
 }
 
 func (c console) log(ctx *context.Context, level log.Level, msgobj goja.Value, args ...goja.Value) {
@@ -64,11 +102,38 @@ func (c console) log(ctx *context.Context, level log.Level, msgobj goja.Value, a
 		}
 	}
 
+	if !c.Logger.IsLevelEnabled(level) {
+		return
+	}
+	msg := msgobj.String()
 	fields := make(log.Fields)
+	key := []string{level.String(), msg}
 	for i, arg := range args {
 		fields[strconv.Itoa(i)] = arg.String()
+		key = append(key, arg.String())
+	}
+	if c.limiter != nil {
+		// Messages are only the same if they're logged with the same arguments too
+		ok, suppressed := c.limiter.Allow(strings.Join(key, "\x00"))
+		if !ok {
+			return
+		}
+		if suppressed > 0 {
+			fields["suppressed"] = suppressed
+		}
+	}
+	if c.source != "" {
+		fields["source"], fields["line"] = c.source, c.line
+	}
+	if ctx != nil && *ctx != nil {
+		if state := lib.GetState(*ctx); state != nil {
+			fields["vu"], fields["iter"] = state.Vu, state.Iteration
+			fields["scheduler"] = state.Options.SchedulerName()
+			if state.Group != nil {
+				fields["group"] = state.Group.Path
+			}
+		}
 	}
-	msg := msgobj.ToString()
 	e := c.Logger.WithFields(fields)
 	switch level {
 	case log.DebugLevel:
@@ -33,12 +33,14 @@ import (
 	"github.com/dop251/goja"
 	"github.com/loadimpact/k6/js/common"
 	"github.com/loadimpact/k6/lib"
+	"github.com/loadimpact/k6/lib/logging"
 	"github.com/loadimpact/k6/loader"
 	"github.com/loadimpact/k6/stats"
 	log "github.com/sirupsen/logrus"
 	logtest "github.com/sirupsen/logrus/hooks/test"
 	"github.com/spf13/afero"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestConsoleContext(t *testing.T) {
@@ -47,7 +49,7 @@ func TestConsoleContext(t *testing.T) {
 
 	ctxPtr := new(context.Context)
 	logger, hook := logtest.NewNullLogger()
-	rt.Set("console", common.Bind(rt, &console{logger}, ctxPtr))
+	rt.Set("console", common.Bind(rt, &console{Logger: logger, limiter: newConsoleLimiter(consoleLogRate)}, ctxPtr))
 
 	_, err := common.RunString(rt, `console.log("a")`)
 	assert.NoError(t, err)
@@ -70,6 +72,69 @@ func TestConsoleContext(t *testing.T) {
 		assert.Equal(t, "b", entry.Message)
 	}
 }
+
+func TestConsoleRateLimit(t *testing.T) {
+	rt := goja.New()
+	rt.SetFieldNameMapper(common.FieldNameMapper{})
+
+	ctxPtr := new(context.Context)
+	logger, hook := logtest.NewNullLogger()
+	rt.Set("console", common.Bind(rt, &console{Logger: logger, limiter: logging.NewLimiter(0.001, 5)}, ctxPtr))
+
+	_, err := common.RunString(rt, `
+		for (var i = 0; i < 10; i++) { console.log("same"); }
+		console.warn("same");
+		console.log("other");
+		console.log("same", 1);
+	`)
+	assert.NoError(t, err)
+	messages := make([]string, len(hook.Entries))
+	for i, entry := range hook.Entries {
+		messages[i] = entry.Level.String() + ":" + entry.Message
+	}
+	assert.Equal(t, []string{
+		"info:same", "info:same", "info:same", "info:same", "info:same", "warning:same", "info:other", "info:same",
+	}, messages)
+
+	t.Run("disabled", func(t *testing.T) {
+		logger, hook := logtest.NewNullLogger()
+		rt.Set("console", common.Bind(rt, &console{Logger: logger, limiter: newConsoleLimiter(0)}, ctxPtr))
+		_, err := common.RunString(rt, `for (var i = 0; i < 1000; i++) { console.log("same"); }`)
+		assert.NoError(t, err)
+		assert.Len(t, hook.Entries, 1000)
+	})
+}
+
+func TestConsoleSourceLine(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/lib.js", []byte("exports.f = function() {\n\tconsole.warn(\"lib\");\n};\n"), 0644))
+	r, err := getSimpleRunnerWithFileFs("/script.js", `
+		import { f } from "./lib.js";
+		export default function() {
+			console.log("script");
+			f();
+			(function(console) { console.log("shadowed"); })({ log: console.log });
+		}`, fs)
+	require.NoError(t, err)
+
+	vu, err := r.newVU(make(chan stats.SampleContainer, 100))
+	require.NoError(t, err)
+	logger, hook := logtest.NewNullLogger()
+	vu.Console.Logger = logger
+	require.NoError(t, vu.RunOnce(context.Background()))
+
+	require.Len(t, hook.Entries, 3)
+	assert.Equal(t, "script", hook.Entries[0].Message)
+	assert.Equal(t, "file:///script.js", hook.Entries[0].Data["source"])
+	assert.Equal(t, int64(4), hook.Entries[0].Data["line"])
+	assert.Equal(t, "lib", hook.Entries[1].Message)
+	assert.Equal(t, "file:///lib.js", hook.Entries[1].Data["source"])
+	assert.Equal(t, int64(2), hook.Entries[1].Data["line"])
+	// Consoles without an at() method are called as they are
+	assert.Equal(t, "shadowed", hook.Entries[2].Message)
+	assert.NotContains(t, hook.Entries[2].Data, "source")
+}
+
 func getSimpleRunner(path, data string) (*Runner, error) {
 	return getSimpleRunnerWithFileFs(path, data, afero.NewMemMapFs())
 }
@@ -93,6 +158,24 @@ func getSimpleRunnerWithFileFs(path, data string, fileFs afero.Fs) (*Runner, err
 		"https": afero.NewMemMapFs()},
 		lib.RuntimeOptions{})
 }
+
+// consoleFields returns the fields of a message logged in the first iteration of the VU from the
+// first line of its script, along with the ones of the message itself.
+func consoleFields(vu *VU, data log.Fields) log.Fields {
+	fields := log.Fields{
+		"vu":        vu.ID,
+		"iter":      int64(0),
+		"scheduler": lib.DefaultSchedulerName,
+		"group":     "",
+		"source":    vu.Runner.Bundle.Filename.String(),
+		"line":      int64(1),
+	}
+	for k, v := range data {
+		fields[k] = v
+	}
+	return fields
+}
+
 func TestConsole(t *testing.T) {
 	levels := map[string]log.Level{
 		"log":   log.InfoLevel,
@@ -137,12 +220,7 @@ func TestConsole(t *testing.T) {
 					if assert.NotNil(t, entry, "nothing logged") {
 						assert.Equal(t, level, entry.Level)
 						assert.Equal(t, result.Message, entry.Message)
-
-						data := result.Data
-						if data == nil {
-							data = make(log.Fields)
-						}
-						assert.Equal(t, data, entry.Data)
+						assert.Equal(t, consoleFields(vu, result.Data), entry.Data)
 					}
 				})
 			}
@@ -233,11 +311,7 @@ func TestFileConsole(t *testing.T) {
 								assert.Equal(t, level, entry.Level)
 								assert.Equal(t, result.Message, entry.Message)
 
-								data := result.Data
-								if data == nil {
-									data = make(log.Fields)
-								}
-								assert.Equal(t, data, entry.Data)
+								assert.Equal(t, consoleFields(vu, result.Data), entry.Data)
 
 								// Test if what we logged to the hook is the same as what we logged
 								// to the file.
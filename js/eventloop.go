@@ -0,0 +1,111 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package js
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/dop251/goja"
+	"github.com/loadimpact/k6/js/common"
+)
+
+// bindTimers defines the global setTimeout(), setInterval(), clearTimeout() and clearInterval()
+// functions, which schedule their callbacks in the VU's event loop. Like in browsers, the extra
+// arguments of setTimeout() and setInterval() are passed on to the callback.
+func bindTimers(rt *goja.Runtime, loop *common.EventLoop) {
+	setTimer := func(repeat bool) func(goja.FunctionCall) goja.Value {
+		return func(call goja.FunctionCall) goja.Value {
+			fn, ok := goja.AssertFunction(call.Argument(0))
+			if !ok {
+				panic(rt.NewTypeError("the callback needs to be a function"))
+			}
+			var args []goja.Value
+			if len(call.Arguments) > 2 {
+				args = append([]goja.Value{}, call.Arguments[2:]...) // The call's arguments are reused
+			}
+			id := loop.SetTimer(func() error {
+				_, err := fn(goja.Undefined(), args...)
+				return err
+			}, timerDelay(call.Argument(1)), repeat)
+			return rt.ToValue(id)
+		}
+	}
+	clearTimer := func(call goja.FunctionCall) goja.Value {
+		loop.ClearTimer(call.Argument(0).ToInteger())
+		return goja.Undefined()
+	}
+
+	rt.Set("setTimeout", setTimer(false))
+	rt.Set("setInterval", setTimer(true))
+	rt.Set("clearTimeout", clearTimer)
+	rt.Set("clearInterval", clearTimer)
+}
+
+// timerDelay converts a delay in milliseconds, which may be missing or not a number at all.
+func timerDelay(v goja.Value) time.Duration {
+	ms := v.ToFloat()
+	if math.IsNaN(ms) || ms < 0 {
+		return 0
+	}
+	if ms >= float64(math.MaxInt64/int64(time.Millisecond)) {
+		return math.MaxInt64
+	}
+	return time.Duration(ms * float64(time.Millisecond))
+}
+
+// awaitValue stores the value returned from an exported function in result. If it's a promise
+// (or anything else with a then() method), it's stored once it's fulfilled instead, and the
+// reason is stored in rejection if it's rejected, when the event loop gets to it. The result of a
+// promise that never settles is undefined.
+func awaitValue(rt *goja.Runtime, v goja.Value, result *goja.Value, rejection *error) error {
+	*result = v
+	obj, ok := v.(*goja.Object)
+	if !ok {
+		return nil
+	}
+	then, ok := goja.AssertFunction(obj.Get("then"))
+	if !ok {
+		return nil
+	}
+
+	*result = goja.Undefined()
+	onFulfilled := func(call goja.FunctionCall) goja.Value {
+		*result = call.Argument(0)
+		return goja.Undefined()
+	}
+	onRejected := func(call goja.FunctionCall) goja.Value {
+		*rejection = &promiseRejection{call.Argument(0)}
+		return goja.Undefined()
+	}
+	_, err := then(obj, rt.ToValue(onFulfilled), rt.ToValue(onRejected))
+	return err
+}
+
+// promiseRejection is the error for a promise returned by an exported function that was rejected.
+type promiseRejection struct {
+	reason goja.Value
+}
+
+func (r *promiseRejection) Error() string {
+	return fmt.Sprintf("Uncaught (in promise) %s", r.reason)
+}
@@ -0,0 +1,137 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package js
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/loadimpact/k6/stats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVUIntegrationTimers(t *testing.T) {
+	r, err := getSimpleRunner("/script.js", `
+		var log = [];
+		setTimeout(function() { log.push("init"); }, 10);
+		export default function() {
+			log = [];
+			var ticks = 0;
+			var interval = setInterval(function() {
+				log.push("tick");
+				if (++ticks == 3) { clearInterval(interval); }
+			}, 5);
+			setTimeout(function(a, b) { log.push("timeout " + a + b); }, 30, "a", "b");
+			clearTimeout(setTimeout(function() { log.push("cleared"); }, 0));
+			setTimeout(function() { log.push("zero"); });
+			log.push("sync");
+		}
+	`)
+	require.NoError(t, err)
+
+	vu, err := r.newVU(make(chan stats.SampleContainer, 100))
+	require.NoError(t, err)
+	assert.Equal(t, []interface{}{"init"}, vu.Runtime.Get("log").Export())
+
+	for i := 0; i < 2; i++ {
+		require.NoError(t, vu.RunOnce(context.Background()))
+		assert.Equal(t, []interface{}{"sync", "zero", "tick", "tick", "tick", "timeout ab"},
+			vu.Runtime.Get("log").Export())
+	}
+}
+
+func TestVUIntegrationPromises(t *testing.T) {
+	r, err := getSimpleRunner("/script.js", `
+		function sleep(ms) {
+			return new Promise(function(resolve) { setTimeout(resolve, ms); });
+		}
+		export let options = { setupTimeout: "10s" };
+		export let result;
+		export async function setup() {
+			await sleep(1);
+			return { answer: 42 };
+		}
+		export default async function(data) {
+			let total = 0;
+			for (let i = 0; i < 3; i++) {
+				await sleep(1);
+				total += data.answer;
+			}
+			if (__ITER == 1) {
+				throw new Error("boom");
+			}
+			try {
+				await Promise.reject(new Error("caught"));
+			} catch (e) {
+				total += 1;
+			}
+			result = total;
+		}
+	`)
+	require.NoError(t, err)
+
+	require.NoError(t, r.Setup(context.Background(), make(chan stats.SampleContainer, 100)))
+	assert.JSONEq(t, `{"answer": 42}`, string(r.GetSetupData()))
+
+	vu, err := r.newVU(make(chan stats.SampleContainer, 100))
+	require.NoError(t, err)
+	require.NoError(t, vu.RunOnce(context.Background()))
+	assert.Equal(t, int64(127), vu.Runtime.Get("exports").ToObject(vu.Runtime).Get("result").ToInteger())
+
+	err = vu.RunOnce(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "Uncaught (in promise) Error: boom")
+}
+
+func TestVUIntegrationTimersCancelled(t *testing.T) {
+	r, err := getSimpleRunner("/script.js", `
+		export default function() {
+			setInterval(function() {}, 1);
+		}
+	`)
+	require.NoError(t, err)
+
+	vu, err := r.newVU(make(chan stats.SampleContainer, 100))
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	assert.Equal(t, context.DeadlineExceeded, vu.RunOnce(ctx))
+	assert.True(t, time.Since(start) < time.Second)
+}
+
+func TestVUIntegrationTimersInvalid(t *testing.T) {
+	r, err := getSimpleRunner("/script.js", `
+		export default function() {
+			setTimeout("not a function", 1);
+		}
+	`)
+	require.NoError(t, err)
+
+	vu, err := r.newVU(make(chan stats.SampleContainer, 100))
+	require.NoError(t, err)
+	err = vu.RunOnce(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "the callback needs to be a function")
+}
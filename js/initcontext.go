@@ -30,6 +30,7 @@ import (
 	"github.com/loadimpact/k6/js/common"
 	"github.com/loadimpact/k6/js/compiler"
 	"github.com/loadimpact/k6/js/modules"
+	"github.com/loadimpact/k6/lib/fsext"
 	"github.com/loadimpact/k6/loader"
 	"github.com/pkg/errors"
 	"github.com/spf13/afero"
@@ -187,6 +188,27 @@ func (i *InitContext) Open(filename string, args ...string) (goja.Value, error)
 		return nil, errors.New("open() can't be used with an empty filename")
 	}
 
+	filename = i.resolveFilePath(filename)
+	fs := i.filesystems["file"]
+	// Workaround for https://github.com/spf13/afero/issues/201
+	if isDir, err := afero.IsDir(fs, filename); err != nil {
+		return nil, err
+	} else if isDir {
+		return nil, errors.New("open() can't be used with directories")
+	}
+	data, err := afero.ReadFile(fs, filename)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(args) > 0 && args[0] == "b" {
+		return i.runtime.ToValue(data), nil
+	}
+	return i.runtime.ToValue(string(data)), nil
+}
+
+// resolveFilePath returns the absolute path of a file that's opened from the init code.
+func (i *InitContext) resolveFilePath(filename string) string {
 	// Here IsAbs should be enough but unfortunately it doesn't handle absolute paths starting from
 	// the current drive on windows like `\users\noname\...`. Also it makes it more easy to test and
 	// will probably be need for archive execution under windows if always consider '/...' as an
@@ -195,23 +217,33 @@ func (i *InitContext) Open(filename string, args ...string) (goja.Value, error)
 		filename = filepath.Join(i.pwd.Path, filename)
 	}
 	filename = filepath.Clean(filename)
-	fs := i.filesystems["file"]
 	if filename[0:1] != afero.FilePathSeparator {
 		filename = afero.FilePathSeparator + filename
 	}
+	return filename
+}
+
+// initEnvironment is the common.InitEnvironment of an init context. It's a separate type, since
+// all the exported methods of InitContext are bound as globals of the init code.
+type initEnvironment struct {
+	init *InitContext
+}
+
+func (env initEnvironment) OpenFile(filename string) (afero.File, error) {
+	if filename == "" {
+		return nil, errors.New("a file can't be opened with an empty filename")
+	}
+	filename = env.init.resolveFilePath(filename)
+	fs := env.init.filesystems["file"]
 	// Workaround for https://github.com/spf13/afero/issues/201
 	if isDir, err := afero.IsDir(fs, filename); err != nil {
 		return nil, err
 	} else if isDir {
-		return nil, errors.New("open() can't be used with directories")
+		return nil, errors.Errorf("%s is a directory", filename)
 	}
-	data, err := afero.ReadFile(fs, filename)
-	if err != nil {
-		return nil, err
-	}
-
-	if len(args) > 0 && args[0] == "b" {
-		return i.runtime.ToValue(data), nil
+	// Files on the disk are read as they're needed, instead of being copied to the memory first
+	if cachedfs, ok := fs.(fsext.CacheOnReadFs); ok {
+		return cachedfs.Stream(filename)
 	}
-	return i.runtime.ToValue(string(data)), nil
+	return fs.Open(filename)
 }
@@ -33,6 +33,9 @@ import (
 var (
 	once   sync.Once
 	coreJs *goja.Program
+
+	regeneratorOnce    sync.Once
+	regeneratorRuntime *goja.Program
 )
 
 func GetCoreJS() *goja.Program {
@@ -46,3 +49,17 @@ func GetCoreJS() *goja.Program {
 
 	return coreJs
 }
+
+// GetRegeneratorRuntime returns the runtime that generator and async functions compiled by Babel
+// need. It uses the polyfills from core-js, so it must be run after GetCoreJS().
+func GetRegeneratorRuntime() *goja.Program {
+	regeneratorOnce.Do(func() {
+		regeneratorRuntime = goja.MustCompile(
+			"regenerator/runtime.js",
+			rice.MustFindBox("regenerator").MustString("runtime.js"),
+			true,
+		)
+	})
+
+	return regeneratorRuntime
+}
@@ -0,0 +1,479 @@
+/**
+ * A minimal implementation of the regenerator runtime, which the generator and async functions
+ * compiled by Babel call into, e.g. regeneratorRuntime.wrap() and regeneratorRuntime.awrap().
+ * It relies on the Promise and Symbol polyfills of core-js, so it has to be loaded after them.
+ */
+(function(global) {
+  "use strict";
+
+  var hasOwn = Object.prototype.hasOwnProperty;
+  var iteratorSymbol = (typeof Symbol === "function" && Symbol.iterator) || "@@iterator";
+  var asyncIteratorSymbol = (typeof Symbol === "function" && Symbol.asyncIterator) || "@@asyncIterator";
+  var toStringTagSymbol = (typeof Symbol === "function" && Symbol.toStringTag) || "@@toStringTag";
+
+  // Returned by the context methods to make the generator loop go on to context.next.
+  var ContinueSentinel = {};
+
+  var GenStateSuspendedStart = "suspendedStart";
+  var GenStateSuspendedYield = "suspendedYield";
+  var GenStateExecuting = "executing";
+  var GenStateCompleted = "completed";
+
+  function Generator() {}
+  function GeneratorFunction() {}
+  function GeneratorFunctionPrototype() {}
+
+  var IteratorPrototype = {};
+  IteratorPrototype[iteratorSymbol] = function() {
+    return this;
+  };
+
+  var Gp = Object.create(IteratorPrototype);
+  GeneratorFunctionPrototype.prototype = Generator.prototype = Gp;
+  GeneratorFunction.prototype = Gp.constructor = GeneratorFunctionPrototype;
+  GeneratorFunctionPrototype.constructor = GeneratorFunction;
+  GeneratorFunctionPrototype[toStringTagSymbol] = GeneratorFunction.displayName = "GeneratorFunction";
+  Gp[toStringTagSymbol] = "Generator";
+  Gp.toString = function() {
+    return "[object Generator]";
+  };
+
+  function defineIteratorMethods(prototype) {
+    ["next", "throw", "return"].forEach(function(method) {
+      prototype[method] = function(arg) {
+        return this._invoke(method, arg);
+      };
+    });
+  }
+  defineIteratorMethods(Gp);
+
+  function tryCatch(fn, obj, arg) {
+    try {
+      return { type: "normal", arg: fn.call(obj, arg) };
+    } catch (err) {
+      return { type: "throw", arg: err };
+    }
+  }
+
+  function doneResult() {
+    return { value: undefined, done: true };
+  }
+
+  function wrap(innerFn, outerFn, self, tryLocsList) {
+    var protoGenerator = outerFn && outerFn.prototype instanceof Generator ? outerFn : Generator;
+    var generator = Object.create(protoGenerator.prototype);
+    var context = new Context(tryLocsList || []);
+    generator._invoke = makeInvokeMethod(innerFn, self, context);
+    return generator;
+  }
+
+  function isGeneratorFunction(genFun) {
+    var ctor = typeof genFun === "function" && genFun.constructor;
+    return ctor ? ctor === GeneratorFunction || (ctor.displayName || ctor.name) === "GeneratorFunction" : false;
+  }
+
+  function mark(genFun) {
+    if (Object.setPrototypeOf) {
+      Object.setPrototypeOf(genFun, GeneratorFunctionPrototype);
+    } else {
+      genFun.constructor = GeneratorFunction;
+    }
+    genFun.prototype = Object.create(Gp);
+    return genFun;
+  }
+
+  // Wraps the operand of an await expression, so the async iterator knows to resume the function
+  // with its value, instead of returning it.
+  function awrap(arg) {
+    return { __await: arg };
+  }
+
+  function AsyncIterator(generator) {
+    function invoke(method, arg, resolve, reject) {
+      var record = tryCatch(generator[method], generator, arg);
+      if (record.type === "throw") {
+        reject(record.arg);
+        return;
+      }
+      var result = record.arg;
+      var value = result.value;
+      if (value && typeof value === "object" && hasOwn.call(value, "__await")) {
+        Promise.resolve(value.__await).then(function(value) {
+          invoke("next", value, resolve, reject);
+        }, function(err) {
+          invoke("throw", err, resolve, reject);
+        });
+        return;
+      }
+      Promise.resolve(value).then(function(unwrapped) {
+        result.value = unwrapped;
+        resolve(result);
+      }, reject);
+    }
+
+    var previousPromise;
+    this._invoke = function(method, arg) {
+      function callInvoke() {
+        return new Promise(function(resolve, reject) {
+          invoke(method, arg, resolve, reject);
+        });
+      }
+      previousPromise = previousPromise ? previousPromise.then(callInvoke, callInvoke) : callInvoke();
+      return previousPromise;
+    };
+  }
+  defineIteratorMethods(AsyncIterator.prototype);
+  AsyncIterator.prototype[asyncIteratorSymbol] = function() {
+    return this;
+  };
+
+  function async(innerFn, outerFn, self, tryLocsList) {
+    var iter = new AsyncIterator(wrap(innerFn, outerFn, self, tryLocsList));
+    if (isGeneratorFunction(outerFn)) {
+      return iter;
+    }
+    return iter.next().then(function(result) {
+      return result.done ? result.value : iter.next();
+    });
+  }
+
+  function makeInvokeMethod(innerFn, self, context) {
+    var state = GenStateSuspendedStart;
+
+    return function invoke(method, arg) {
+      if (state === GenStateExecuting) {
+        throw new Error("Generator is already running");
+      }
+      if (state === GenStateCompleted) {
+        if (method === "throw") {
+          throw arg;
+        }
+        return doneResult();
+      }
+
+      context.method = method;
+      context.arg = arg;
+
+      for (;;) {
+        var delegate = context.delegate;
+        if (delegate) {
+          var delegateResult = maybeInvokeDelegate(delegate, context);
+          if (delegateResult) {
+            if (delegateResult === ContinueSentinel) {
+              continue;
+            }
+            return delegateResult;
+          }
+        }
+
+        if (context.method === "next") {
+          context.sent = context._sent = context.arg;
+        } else if (context.method === "throw") {
+          if (state === GenStateSuspendedStart) {
+            state = GenStateCompleted;
+            throw context.arg;
+          }
+          context.dispatchException(context.arg);
+        } else if (context.method === "return") {
+          context.abrupt("return", context.arg);
+        }
+
+        state = GenStateExecuting;
+        var record = tryCatch(innerFn, self, context);
+        if (record.type === "normal") {
+          state = context.done ? GenStateCompleted : GenStateSuspendedYield;
+          if (record.arg === ContinueSentinel) {
+            continue;
+          }
+          return { value: record.arg, done: context.done };
+        }
+        state = GenStateCompleted;
+        context.method = "throw";
+        context.arg = record.arg;
+      }
+    };
+  }
+
+  // Forwards the current method call to the iterator of a yield* expression, returning the
+  // result to yield, or ContinueSentinel if the delegation is over.
+  function maybeInvokeDelegate(delegate, context) {
+    var method = delegate.iterator[context.method];
+    if (method === undefined) {
+      context.delegate = null;
+      if (context.method === "throw") {
+        if (delegate.iterator["return"]) {
+          context.method = "return";
+          context.arg = undefined;
+          maybeInvokeDelegate(delegate, context);
+          if (context.method === "throw") {
+            return ContinueSentinel;
+          }
+        }
+        context.method = "throw";
+        context.arg = new TypeError("The iterator does not provide a 'throw' method");
+      }
+      return ContinueSentinel;
+    }
+
+    var record = tryCatch(method, delegate.iterator, context.arg);
+    if (record.type === "throw") {
+      context.method = "throw";
+      context.arg = record.arg;
+      context.delegate = null;
+      return ContinueSentinel;
+    }
+
+    var info = record.arg;
+    if (!info) {
+      context.method = "throw";
+      context.arg = new TypeError("iterator result is not an object");
+      context.delegate = null;
+      return ContinueSentinel;
+    }
+    if (!info.done) {
+      return info;
+    }
+
+    context[delegate.resultName] = info.value;
+    context.next = delegate.nextLoc;
+    if (context.method !== "return") {
+      context.method = "next";
+      context.arg = undefined;
+    }
+    context.delegate = null;
+    return ContinueSentinel;
+  }
+
+  function pushTryEntry(locs) {
+    var entry = { tryLoc: locs[0] };
+    if (1 in locs) {
+      entry.catchLoc = locs[1];
+    }
+    if (2 in locs) {
+      entry.finallyLoc = locs[2];
+      entry.afterLoc = locs[3];
+    }
+    this.tryEntries.push(entry);
+  }
+
+  function resetTryEntry(entry) {
+    var record = entry.completion || {};
+    record.type = "normal";
+    delete record.arg;
+    entry.completion = record;
+  }
+
+  // Context is the state of a generator, which the compiled generator body reads and changes.
+  function Context(tryLocsList) {
+    this.tryEntries = [{ tryLoc: "root" }];
+    tryLocsList.forEach(pushTryEntry, this);
+    this.reset(true);
+  }
+
+  Context.prototype = {
+    constructor: Context,
+
+    reset: function(skipTempReset) {
+      this.prev = 0;
+      this.next = 0;
+      this.sent = this._sent = undefined;
+      this.done = false;
+      this.delegate = null;
+      this.method = "next";
+      this.arg = undefined;
+      this.tryEntries.forEach(resetTryEntry);
+      if (!skipTempReset) {
+        for (var name in this) {
+          if (name.charAt(0) === "t" && hasOwn.call(this, name) && !isNaN(+name.slice(1))) {
+            this[name] = undefined;
+          }
+        }
+      }
+    },
+
+    stop: function() {
+      this.done = true;
+      var rootRecord = this.tryEntries[0].completion;
+      if (rootRecord.type === "throw") {
+        throw rootRecord.arg;
+      }
+      return this.rval;
+    },
+
+    dispatchException: function(exception) {
+      if (this.done) {
+        throw exception;
+      }
+      var context = this;
+      var record;
+      function handle(loc, caught) {
+        record.type = "throw";
+        record.arg = exception;
+        context.next = loc;
+        if (caught) {
+          context.method = "next";
+          context.arg = undefined;
+        }
+        return !!caught;
+      }
+
+      for (var i = this.tryEntries.length - 1; i >= 0; --i) {
+        var entry = this.tryEntries[i];
+        record = entry.completion;
+        if (entry.tryLoc === "root") {
+          return handle("end");
+        }
+        if (entry.tryLoc <= this.prev) {
+          var hasCatch = hasOwn.call(entry, "catchLoc");
+          var hasFinally = hasOwn.call(entry, "finallyLoc");
+          if (hasCatch && this.prev < entry.catchLoc) {
+            return handle(entry.catchLoc, true);
+          }
+          if (hasFinally && this.prev < entry.finallyLoc) {
+            return handle(entry.finallyLoc);
+          }
+          if (!hasCatch && !hasFinally) {
+            throw new Error("try statement without catch or finally");
+          }
+        }
+      }
+    },
+
+    abrupt: function(type, arg) {
+      var finallyEntry;
+      for (var i = this.tryEntries.length - 1; i >= 0; --i) {
+        var entry = this.tryEntries[i];
+        if (entry.tryLoc <= this.prev && hasOwn.call(entry, "finallyLoc") && this.prev < entry.finallyLoc) {
+          finallyEntry = entry;
+          break;
+        }
+      }
+      if (finallyEntry && (type === "break" || type === "continue") &&
+          finallyEntry.tryLoc <= arg && arg <= finallyEntry.finallyLoc) {
+        // A break or continue that stays within the try block doesn't run the finally block.
+        finallyEntry = null;
+      }
+
+      var record = finallyEntry ? finallyEntry.completion : {};
+      record.type = type;
+      record.arg = arg;
+      if (finallyEntry) {
+        this.method = "next";
+        this.next = finallyEntry.finallyLoc;
+        return ContinueSentinel;
+      }
+      return this.complete(record);
+    },
+
+    complete: function(record, afterLoc) {
+      if (record.type === "throw") {
+        throw record.arg;
+      }
+      if (record.type === "break" || record.type === "continue") {
+        this.next = record.arg;
+      } else if (record.type === "return") {
+        this.rval = this.arg = record.arg;
+        this.method = "return";
+        this.next = "end";
+      } else if (record.type === "normal" && afterLoc) {
+        this.next = afterLoc;
+      }
+      return ContinueSentinel;
+    },
+
+    finish: function(finallyLoc) {
+      for (var i = this.tryEntries.length - 1; i >= 0; --i) {
+        var entry = this.tryEntries[i];
+        if (entry.finallyLoc === finallyLoc) {
+          this.complete(entry.completion, entry.afterLoc);
+          resetTryEntry(entry);
+          return ContinueSentinel;
+        }
+      }
+    },
+
+    "catch": function(tryLoc) {
+      for (var i = this.tryEntries.length - 1; i >= 0; --i) {
+        var entry = this.tryEntries[i];
+        if (entry.tryLoc === tryLoc) {
+          var record = entry.completion;
+          var thrown;
+          if (record.type === "throw") {
+            thrown = record.arg;
+            resetTryEntry(entry);
+          }
+          return thrown;
+        }
+      }
+      throw new Error("illegal catch attempt");
+    },
+
+    delegateYield: function(iterable, resultName, nextLoc) {
+      this.delegate = { iterator: values(iterable), resultName: resultName, nextLoc: nextLoc };
+      if (this.method === "next") {
+        this.arg = undefined;
+      }
+      return ContinueSentinel;
+    }
+  };
+
+  function keys(object) {
+    var list = [];
+    for (var key in object) {
+      list.push(key);
+    }
+    list.reverse();
+    return function next() {
+      while (list.length) {
+        var key = list.pop();
+        if (key in object) {
+          next.value = key;
+          next.done = false;
+          return next;
+        }
+      }
+      next.done = true;
+      return next;
+    };
+  }
+
+  function values(iterable) {
+    if (iterable) {
+      var iteratorMethod = iterable[iteratorSymbol];
+      if (iteratorMethod) {
+        return iteratorMethod.call(iterable);
+      }
+      if (typeof iterable.next === "function") {
+        return iterable;
+      }
+      if (!isNaN(iterable.length)) {
+        var i = -1;
+        var next = function next() {
+          while (++i < iterable.length) {
+            if (hasOwn.call(iterable, i)) {
+              next.value = iterable[i];
+              next.done = false;
+              return next;
+            }
+          }
+          next.value = undefined;
+          next.done = true;
+          return next;
+        };
+        return (next.next = next);
+      }
+    }
+    return { next: doneResult };
+  }
+
+  global.regeneratorRuntime = {
+    wrap: wrap,
+    isGeneratorFunction: isGeneratorFunction,
+    mark: mark,
+    awrap: awrap,
+    AsyncIterator: AsyncIterator,
+    async: async,
+    keys: keys,
+    values: values
+  };
+})(this);
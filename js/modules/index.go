@@ -24,21 +24,32 @@ import (
 	"github.com/loadimpact/k6/js/modules/k6"
 	"github.com/loadimpact/k6/js/modules/k6/crypto"
 	"github.com/loadimpact/k6/js/modules/k6/crypto/x509"
+	"github.com/loadimpact/k6/js/modules/k6/data"
+	"github.com/loadimpact/k6/js/modules/k6/data/csv"
 	"github.com/loadimpact/k6/js/modules/k6/encoding"
+	"github.com/loadimpact/k6/js/modules/k6/events"
+	"github.com/loadimpact/k6/js/modules/k6/experimental/fs"
 	"github.com/loadimpact/k6/js/modules/k6/html"
 	"github.com/loadimpact/k6/js/modules/k6/http"
 	"github.com/loadimpact/k6/js/modules/k6/metrics"
 	"github.com/loadimpact/k6/js/modules/k6/ws"
 )
 
+// The k6/data module is shared with k6/data/csv, so the shared arrays of both have the same names.
+var dataModule = data.New() //nolint:gochecknoglobals
+
 // Index of module implementations.
 var Index = map[string]interface{}{
-	"k6":             k6.New(),
-	"k6/crypto":      crypto.New(),
-	"k6/crypto/x509": x509.New(),
-	"k6/encoding":    encoding.New(),
-	"k6/http":        http.New(),
-	"k6/metrics":     metrics.New(),
-	"k6/html":        html.New(),
-	"k6/ws":          ws.New(),
+	"k6":                 k6.New(),
+	"k6/crypto":          crypto.New(),
+	"k6/crypto/x509":     x509.New(),
+	"k6/data":            dataModule,
+	"k6/data/csv":        csv.New(dataModule),
+	"k6/encoding":        encoding.New(),
+	"k6/events":          events.New(),
+	"k6/experimental/fs": fs.New(),
+	"k6/http":            http.New(),
+	"k6/metrics":         metrics.New(),
+	"k6/html":            html.New(),
+	"k6/ws":              ws.New(),
 }
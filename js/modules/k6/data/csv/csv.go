@@ -0,0 +1,425 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package csv
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	stdcsv "encoding/csv"
+	"encoding/json"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/dop251/goja"
+	"github.com/loadimpact/k6/js/common"
+	"github.com/loadimpact/k6/js/modules/k6/data"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// Formats of the data that can be parsed.
+const (
+	FormatCSV       = "csv"
+	FormatJSONLines = "jsonl"
+)
+
+// CSV is the k6/data/csv module, which parses CSV and JSON lines data in Go, either into arrays,
+// row by row from a file that's opened in the init context, or into shared arrays.
+type CSV struct {
+	data *data.Data
+}
+
+// New returns a new CSV module, whose shared arrays are the same as the given k6/data module's.
+func New(d *data.Data) *CSV {
+	return &CSV{data: d}
+}
+
+// options of the parser, see parseOptions().
+type options struct {
+	format        string
+	header        bool
+	delimiter     rune
+	dynamicTyping bool
+}
+
+// parseOptions parses the options object that's passed to all the functions of the module. The
+// rows of CSV data are objects keyed by the columns of the header row by default, or arrays if the
+// header option is false, and all the values are strings, unless dynamicTyping is set.
+func parseOptions(rt *goja.Runtime, v goja.Value) (options, error) {
+	opts := options{format: FormatCSV, header: true, delimiter: ','}
+	if v == nil || goja.IsUndefined(v) || goja.IsNull(v) {
+		return opts, nil
+	}
+
+	obj := v.ToObject(rt)
+	for _, key := range obj.Keys() {
+		value := obj.Get(key)
+		switch key {
+		case "format":
+			switch format := value.String(); format {
+			case FormatCSV, FormatJSONLines:
+				opts.format = format
+			default:
+				return opts, errors.Errorf("unsupported format '%s'", format)
+			}
+		case "header":
+			opts.header = value.ToBoolean()
+		case "delimiter":
+			delimiter := value.String()
+			r, _ := utf8.DecodeRuneInString(delimiter)
+			if utf8.RuneCountInString(delimiter) != 1 || r == '"' || r == '\r' || r == '\n' ||
+				r == utf8.RuneError {
+				return opts, errors.Errorf("invalid delimiter '%s'", delimiter)
+			}
+			opts.delimiter = r
+		case "dynamicTyping":
+			opts.dynamicTyping = value.ToBoolean()
+		default:
+			return opts, errors.Errorf("unknown option '%s'", key)
+		}
+	}
+	return opts, nil
+}
+
+// Parse parses all of the given CSV or JSON lines data into an array of rows.
+func (*CSV) Parse(ctx context.Context, text string, opts goja.Value) (goja.Value, error) {
+	rt := common.GetRuntime(ctx)
+	o, err := parseOptions(rt, opts)
+	if err != nil {
+		return nil, err
+	}
+	r, err := newReader(rt, strings.NewReader(text), o)
+	if err != nil {
+		return nil, err
+	}
+
+	var rows []interface{}
+	for {
+		row, err := r.next()
+		if err == io.EOF {
+			return rt.ToValue(&rows), nil
+		} else if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+}
+
+// Open opens a file in the init context, the same way as open() would, so the file is also saved
+// in archives. It returns an iterator, which reads a row from the file every time its next() is
+// called, so rows are only parsed when they're needed. Every VU has its own iterator.
+func (*CSV) Open(ctxPtr *context.Context, filename string, opts goja.Value) (map[string]interface{}, error) {
+	env := common.GetInitEnv(*ctxPtr)
+	if env == nil {
+		return nil, common.NewInitContextError("files can only be opened in the init context")
+	}
+	rt := common.GetRuntime(*ctxPtr)
+	o, err := parseOptions(rt, opts)
+	if err != nil {
+		return nil, err
+	}
+	f, err := env.OpenFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	it := &Iterator{rt: rt, opts: o, file: f}
+	if it.r, err = newReader(rt, f, o); err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return common.Bind(rt, it, ctxPtr), nil
+}
+
+// XSharedArray is the SharedArray(name, filename, options) constructor. The first call with a given
+// name reads the whole file, in the same way as open() would, and keeps its JSON encoded rows in
+// a k6/data shared array, so the data is only parsed and kept in memory once for all the VUs.
+func (c *CSV) XSharedArray(ctxPtr *context.Context, name, filename string, opts goja.Value) (interface{}, error) {
+	env := common.GetInitEnv(*ctxPtr)
+	if env == nil {
+		return nil, errors.New("shared arrays must be constructed in the init context")
+	}
+	o, err := parseOptions(common.GetRuntime(*ctxPtr), opts)
+	if err != nil {
+		return nil, err
+	}
+	return data.NewSharedArray(c.data, ctxPtr, name, func() ([]string, error) {
+		f, err := env.OpenFile(filename)
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = f.Close() }()
+		return encodeRows(f, o)
+	})
+}
+
+// errClosed is returned by the methods of an Iterator after it has been closed.
+var errClosed = errors.New("the file is closed") //nolint:gochecknoglobals
+
+// Iterator reads the rows of a file that was opened in the init context.
+type Iterator struct {
+	rt   *goja.Runtime
+	opts options
+	file afero.File
+	r    *reader
+
+	closed bool
+}
+
+// Next returns the next row as the value of an iteration result, like the iterators of JS do,
+// i.e. it's {done: false, value: row}, or {done: true} once there are no more rows.
+func (it *Iterator) Next() (goja.Value, error) {
+	if it.closed {
+		return nil, errClosed
+	}
+	result := it.rt.NewObject()
+	row, err := it.r.next()
+	if err == io.EOF {
+		_ = result.Set("done", true)
+		return result, nil
+	} else if err != nil {
+		return nil, err
+	}
+	_ = result.Set("done", false)
+	_ = result.Set("value", row)
+	return result, nil
+}
+
+// Reset goes back to the first row of the file.
+func (it *Iterator) Reset() error {
+	if it.closed {
+		return errClosed
+	}
+	if _, err := it.file.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	r, err := newReader(it.rt, it.file, it.opts)
+	if err != nil {
+		return err
+	}
+	it.r = r
+	return nil
+}
+
+// Close closes the file, after which no more rows can be read. Iterators that aren't needed
+// anymore should be closed, since their files stay open until k6 exits otherwise. Closing an
+// iterator more than once does nothing.
+func (it *Iterator) Close() error {
+	if it.closed {
+		return nil
+	}
+	it.closed = true
+	return it.file.Close()
+}
+
+// reader reads the rows of CSV or JSON lines data into JS values.
+type reader struct {
+	rt     *goja.Runtime
+	opts   options
+	csv    *stdcsv.Reader
+	lines  *bufio.Reader
+	header []string
+	parse  goja.Callable
+}
+
+// newReader returns a reader of the given data, which has already read the header row of CSV data.
+func newReader(rt *goja.Runtime, src io.Reader, opts options) (*reader, error) {
+	r := &reader{rt: rt, opts: opts}
+	if opts.format == FormatJSONLines {
+		r.lines = bufio.NewReader(src)
+		r.parse, _ = goja.AssertFunction(rt.Get("JSON").ToObject(rt).Get("parse"))
+		return r, nil
+	}
+
+	r.csv = newCSVReader(src, opts)
+	if opts.header {
+		header, err := r.csv.Read()
+		if err != nil && err != io.EOF {
+			return nil, err
+		}
+		// The record is reused by the next read, so the header needs a copy.
+		r.header = append([]string(nil), header...)
+	}
+	return r, nil
+}
+
+// next returns the next row, or io.EOF once there are no more rows.
+func (r *reader) next() (goja.Value, error) {
+	if r.lines != nil {
+		line, err := readLine(r.lines)
+		if err != nil {
+			return nil, err
+		}
+		return r.parse(goja.Undefined(), r.rt.ToValue(line))
+	}
+
+	record, err := r.csv.Read()
+	if err != nil {
+		return nil, err
+	}
+	if r.header == nil {
+		values := make([]interface{}, len(record))
+		for i, field := range record {
+			values[i] = r.value(field)
+		}
+		return r.rt.ToValue(&values), nil
+	}
+	obj := r.rt.NewObject()
+	for i, column := range r.header {
+		_ = obj.Set(column, r.value(record[i]))
+	}
+	return obj, nil
+}
+
+func (r *reader) value(field string) interface{} {
+	if !r.opts.dynamicTyping {
+		return field
+	}
+	return coerce(field)
+}
+
+// newCSVReader returns a reader of CSV data, which needs every row to have as many fields as the
+// first one.
+func newCSVReader(src io.Reader, opts options) *stdcsv.Reader {
+	r := stdcsv.NewReader(src)
+	r.Comma = opts.delimiter
+	r.ReuseRecord = true
+	return r
+}
+
+// readLine returns the next line of JSON lines data that isn't empty, or io.EOF.
+func readLine(r *bufio.Reader) (string, error) {
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil && (err != io.EOF || line == "") {
+			return "", err
+		}
+		if line = strings.TrimSpace(line); line != "" {
+			return line, nil
+		}
+	}
+}
+
+var numberRegexp = regexp.MustCompile(`^-?(0|[1-9]\d*)(\.\d+)?([eE][-+]?\d+)?$`)
+
+// coerce converts a field of CSV data to a boolean or a number, if it looks like one, and an
+// empty field to null. Only numbers that are written as in JSON are converted, so values like
+// zip codes with leading zeros are kept as strings, like anything else.
+func coerce(field string) interface{} {
+	switch field {
+	case "":
+		return nil
+	case "true", "TRUE":
+		return true
+	case "false", "FALSE":
+		return false
+	}
+	if numberRegexp.MatchString(field) {
+		if f, err := strconv.ParseFloat(field, 64); err == nil {
+			return f
+		}
+	}
+	return field
+}
+
+// encodeRows reads all of the given data into JSON encoded rows. The keys of the rows of CSV data
+// with a header row are kept in the order of its columns.
+func encodeRows(src io.Reader, opts options) ([]string, error) {
+	var rows []string
+	if opts.format == FormatJSONLines {
+		lines := bufio.NewReader(src)
+		for {
+			line, err := readLine(lines)
+			if err == io.EOF {
+				return rows, nil
+			} else if err != nil {
+				return nil, err
+			}
+			var buf bytes.Buffer
+			if err := json.Compact(&buf, []byte(line)); err != nil {
+				return nil, errors.Wrapf(err, "invalid JSON in line %d", len(rows)+1)
+			}
+			rows = append(rows, buf.String())
+		}
+	}
+
+	r := newCSVReader(src, opts)
+	var header []string
+	if opts.header {
+		record, err := r.Read()
+		if err == io.EOF {
+			return rows, nil
+		} else if err != nil {
+			return nil, err
+		}
+		header = append(header, record...)
+	}
+	for {
+		record, err := r.Read()
+		if err == io.EOF {
+			return rows, nil
+		} else if err != nil {
+			return nil, err
+		}
+		row, err := encodeRow(header, record, opts)
+		if err != nil {
+			return nil, err
+		}
+		rows = append(rows, row)
+	}
+}
+
+// encodeRow JSON encodes a row of CSV data, as an object if there's a header, or as an array.
+func encodeRow(header, record []string, opts options) (string, error) {
+	var buf bytes.Buffer
+	start, end := byte('['), byte(']')
+	if header != nil {
+		start, end = '{', '}'
+	}
+	buf.WriteByte(start)
+	for i, field := range record {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if header != nil {
+			key, err := json.Marshal(header[i])
+			if err != nil {
+				return "", err
+			}
+			buf.Write(key)
+			buf.WriteByte(':')
+		}
+		var value interface{} = field
+		if opts.dynamicTyping {
+			value = coerce(field)
+		}
+		data, err := json.Marshal(value)
+		if err != nil {
+			return "", err
+		}
+		buf.Write(data)
+	}
+	buf.WriteByte(end)
+	return buf.String(), nil
+}
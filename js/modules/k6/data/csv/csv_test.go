@@ -0,0 +1,234 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package csv
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dop251/goja"
+	"github.com/loadimpact/k6/js/common"
+	"github.com/loadimpact/k6/js/modules/k6/data"
+	"github.com/loadimpact/k6/lib"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCSV = "name,age,admin,note\r\n" +
+	"alice,31,true,\"likes \"\"quotes\"\", commas\"\n" +
+	"bob,,FALSE,\"multi\nline\"\n" +
+	"carol,-1.5e2,no,007\n" +
+	"dave,0.5,TRUE,+1\n"
+
+const testJSONLines = `{"name": "alice", "tags": ["a"]}
+
+{"name":"bob"}
+`
+
+type testInitEnv struct {
+	fs afero.Fs
+}
+
+func (env testInitEnv) OpenFile(filename string) (afero.File, error) {
+	return env.fs.Open(filename)
+}
+
+func newRuntime(t *testing.T, mod *CSV) (*goja.Runtime, *context.Context) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/data.csv", []byte(testCSV), 0644))
+	require.NoError(t, afero.WriteFile(fs, "/data.jsonl", []byte(testJSONLines), 0644))
+
+	rt := goja.New()
+	rt.SetFieldNameMapper(common.FieldNameMapper{})
+	ctxPtr := new(context.Context)
+	*ctxPtr = common.WithInitEnv(common.WithRuntime(context.Background(), rt), testInitEnv{fs})
+	rt.Set("csv", common.Bind(rt, mod, ctxPtr))
+	rt.Set("testCSV", testCSV)
+	rt.Set("testJSONLines", testJSONLines)
+	return rt, ctxPtr
+}
+
+// toVU makes the runtime run like in a VU, after the init context is done.
+func toVU(rt *goja.Runtime, ctxPtr *context.Context) {
+	*ctxPtr = lib.WithState(common.WithRuntime(context.Background(), rt), &lib.State{})
+}
+
+func TestParse(t *testing.T) {
+	rt, _ := newRuntime(t, New(data.New()))
+
+	testdata := map[string]struct{ script, result string }{
+		"Header": {
+			`csv.parse(testCSV)`,
+			`[{"name":"alice","age":"31","admin":"true","note":"likes \"quotes\", commas"},` +
+				`{"name":"bob","age":"","admin":"FALSE","note":"multi\nline"},` +
+				`{"name":"carol","age":"-1.5e2","admin":"no","note":"007"},` +
+				`{"name":"dave","age":"0.5","admin":"TRUE","note":"+1"}]`,
+		},
+		"DynamicTyping": {
+			`csv.parse(testCSV, {dynamicTyping: true})`,
+			`[{"name":"alice","age":31,"admin":true,"note":"likes \"quotes\", commas"},` +
+				`{"name":"bob","age":null,"admin":false,"note":"multi\nline"},` +
+				`{"name":"carol","age":-150,"admin":"no","note":"007"},` +
+				`{"name":"dave","age":0.5,"admin":true,"note":"+1"}]`,
+		},
+		"NoHeader": {
+			`csv.parse("a;b\n1;2", {header: false, delimiter: ";", dynamicTyping: true})`,
+			`[["a","b"],[1,2]]`,
+		},
+		"Empty": {
+			`csv.parse("")`,
+			`[]`,
+		},
+		"JSONLines": {
+			`csv.parse(testJSONLines, {format: "jsonl"})`,
+			`[{"name":"alice","tags":["a"]},{"name":"bob"}]`,
+		},
+	}
+	for name, data := range testdata {
+		data := data
+		t.Run(name, func(t *testing.T) {
+			v, err := common.RunString(rt, "JSON.stringify("+data.script+")")
+			require.NoError(t, err)
+			assert.Equal(t, data.result, v.String())
+		})
+	}
+
+	t.Run("Array", func(t *testing.T) {
+		v, err := common.RunString(rt, `
+			var rows = csv.parse(testCSV);
+			rows.push({name: "eve"});
+			Array.isArray(rows) && rows.map(function(row) { return row.name; }).join(",");
+		`)
+		require.NoError(t, err)
+		assert.Equal(t, "alice,bob,carol,dave,eve", v.String())
+	})
+
+	errors := map[string]string{
+		`csv.parse("a,b\n1,2,3")`:           "GoError: record on line 2: wrong number of fields",
+		`csv.parse("a\n\"1")`:               "GoError: parse error on line 2, column 3: extraneous or missing \" in quoted-field",
+		`csv.parse("{", {format: "jsonl"})`: "SyntaxError",
+		`csv.parse("", {format: "xml"})`:    "GoError: unsupported format 'xml'",
+		`csv.parse("", {delimiter: ",,"})`:  "GoError: invalid delimiter ',,'",
+		`csv.parse("", {delimiter: "\n"})`:  "GoError: invalid delimiter '\n'",
+		`csv.parse("", {headers: true})`:    "GoError: unknown option 'headers'",
+	}
+	for script, expErr := range errors {
+		_, err := common.RunString(rt, script)
+		if assert.Error(t, err, script) {
+			assert.Contains(t, err.Error(), expErr, script)
+		}
+	}
+}
+
+func TestOpen(t *testing.T) {
+	t.Run("CSV", func(t *testing.T) {
+		rt, ctxPtr := newRuntime(t, New(data.New()))
+		_, err := common.RunString(rt, `var rows = csv.open("/data.csv", {dynamicTyping: true});`)
+		require.NoError(t, err)
+		toVU(rt, ctxPtr)
+
+		v, err := common.RunString(rt, `
+			var names = [], res;
+			while (!(res = rows.next()).done) { names.push(res.value.name + ":" + res.value.age); }
+			rows.reset();
+			names.push(rows.next().value.name);
+			names.join(",");
+		`)
+		require.NoError(t, err)
+		assert.Equal(t, "alice:31,bob:null,carol:-150,dave:0.5,alice", v.String())
+	})
+
+	t.Run("JSONLines", func(t *testing.T) {
+		rt, ctxPtr := newRuntime(t, New(data.New()))
+		_, err := common.RunString(rt, `var rows = csv.open("/data.jsonl", {format: "jsonl"});`)
+		require.NoError(t, err)
+		toVU(rt, ctxPtr)
+
+		v, err := common.RunString(rt, `JSON.stringify([rows.next(), rows.next(), rows.next()])`)
+		require.NoError(t, err)
+		assert.Equal(t,
+			`[{"done":false,"value":{"name":"alice","tags":["a"]}},{"done":false,"value":{"name":"bob"}},{"done":true}]`,
+			v.String())
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		rt, ctxPtr := newRuntime(t, New(data.New()))
+		_, err := common.RunString(rt, `csv.open("/missing.csv")`)
+		assert.EqualError(t, err, "GoError: open /missing.csv: file does not exist")
+
+		toVU(rt, ctxPtr)
+		_, err = common.RunString(rt, `csv.open("/data.csv")`)
+		assert.EqualError(t, err, "GoError: files can only be opened in the init context")
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		rt, ctxPtr := newRuntime(t, New(data.New()))
+		_, err := common.RunString(rt, `var rows = csv.open("/data.csv");`)
+		require.NoError(t, err)
+		toVU(rt, ctxPtr)
+
+		_, err = common.RunString(rt, `rows.next(); rows.close(); rows.close();`)
+		require.NoError(t, err)
+		_, err = common.RunString(rt, `rows.next()`)
+		assert.EqualError(t, err, "GoError: the file is closed")
+		_, err = common.RunString(rt, `rows.reset()`)
+		assert.EqualError(t, err, "GoError: the file is closed")
+	})
+}
+
+func TestSharedArray(t *testing.T) {
+	dataModule := data.New()
+	rt1, ctxPtr1 := newRuntime(t, New(dataModule))
+	rt2, _ := newRuntime(t, New(dataModule))
+	rt2.Set("data", common.Bind(rt2, dataModule, new(context.Context)))
+
+	_, err := common.RunString(rt1, `
+		var users = new csv.SharedArray("users", "/data.csv", {dynamicTyping: true});
+		var lines = new csv.SharedArray("lines", "/data.jsonl", {format: "jsonl"});
+	`)
+	require.NoError(t, err)
+	toVU(rt1, ctxPtr1)
+
+	v, err := common.RunString(rt1, `
+		users.length + " " + JSON.stringify(users.get(2)) + " " + lines.length + " " + lines.get(1).name
+	`)
+	require.NoError(t, err)
+	assert.Equal(t, `4 {"name":"carol","age":-150,"admin":"no","note":"007"} 2 bob`, v.String())
+
+	t.Run("Reused", func(t *testing.T) {
+		// The file isn't read again, even if it's not the same one
+		_, err := common.RunString(rt2, `
+			var users = new csv.SharedArray("users", "/missing.csv");
+			if (users.length !== 4 || users.get(0).name !== "alice") { throw new Error("not reused"); }
+		`)
+		require.NoError(t, err)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		_, err := common.RunString(rt2, `new csv.SharedArray("missing", "/missing.csv")`)
+		assert.Contains(t, err.Error(), "GoError: couldn't create the shared array 'missing': open /missing.csv: file does not exist")
+		_, err = common.RunString(rt2, `new csv.SharedArray("", "/data.csv")`)
+		assert.Contains(t, err.Error(), "GoError: a shared array needs a name")
+		_, err = common.RunString(rt1, `new csv.SharedArray("other", "/data.csv")`)
+		assert.Contains(t, err.Error(), "GoError: shared arrays must be constructed in the init context")
+	})
+}
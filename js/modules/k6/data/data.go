@@ -0,0 +1,169 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package data
+
+import (
+	"context"
+	"strconv"
+	"sync"
+
+	"github.com/dop251/goja"
+	"github.com/loadimpact/k6/js/common"
+	"github.com/loadimpact/k6/lib"
+	"github.com/pkg/errors"
+)
+
+// Data is the k6/data module, which shares read-only data between all VUs.
+type Data struct {
+	mu     sync.Mutex
+	arrays map[string]*sharedArray
+}
+
+// New returns a new Data module.
+func New() *Data {
+	return &Data{arrays: make(map[string]*sharedArray)}
+}
+
+// sharedArray holds the JSON encoded elements of an array, which are only ever written once, so
+// they can be read by all VUs without any locking after the once is done.
+type sharedArray struct {
+	once     sync.Once
+	elements []string
+	err      error
+}
+
+// XSharedArray is the SharedArray(name, fn) constructor. The first call with a given name runs fn
+// and keeps the array it returns, every other call (i.e. in the other VUs) reuses that array
+// without running fn again. The elements are decoded in the calling VU when they're accessed.
+func (d *Data) XSharedArray(ctxPtr *context.Context, name string, fn goja.Value) (interface{}, error) {
+	if err := checkSharedArray(*ctxPtr, name); err != nil {
+		return nil, err
+	}
+	call, ok := goja.AssertFunction(fn)
+	if !ok {
+		return nil, common.NewInitContextError("a shared array needs a function that returns its data")
+	}
+
+	rt := common.GetRuntime(*ctxPtr)
+	return d.sharedArray(ctxPtr, name, func() ([]string, error) {
+		return encodeArray(rt, call)
+	})
+}
+
+// NewSharedArray returns a shared array, like the SharedArray constructor, for modules that produce
+// the data in Go. The elements are JSON encoded by encode, which is only called the first time the
+// shared array with the given name is constructed.
+func NewSharedArray(
+	d *Data, ctxPtr *context.Context, name string, encode func() ([]string, error),
+) (interface{}, error) {
+	if err := checkSharedArray(*ctxPtr, name); err != nil {
+		return nil, err
+	}
+	return d.sharedArray(ctxPtr, name, encode)
+}
+
+// checkSharedArray checks that a shared array can be constructed.
+func checkSharedArray(ctx context.Context, name string) error {
+	if lib.GetState(ctx) != nil {
+		return errors.New("shared arrays must be constructed in the init context")
+	}
+	if name == "" {
+		return common.NewInitContextError("a shared array needs a name")
+	}
+	return nil
+}
+
+func (d *Data) sharedArray(
+	ctxPtr *context.Context, name string, encode func() ([]string, error),
+) (interface{}, error) {
+	d.mu.Lock()
+	arr, ok := d.arrays[name]
+	if !ok {
+		arr = &sharedArray{}
+		d.arrays[name] = arr
+	}
+	d.mu.Unlock()
+
+	arr.once.Do(func() {
+		arr.elements, arr.err = encode()
+	})
+	if arr.err != nil {
+		return nil, errors.Wrapf(arr.err, "couldn't create the shared array '%s'", name)
+	}
+
+	obj := common.Bind(common.GetRuntime(*ctxPtr), SharedArray{arr.elements}, ctxPtr)
+	obj["length"] = len(arr.elements)
+	return obj, nil
+}
+
+// encodeArray calls fn and JSON encodes each element of the array it returns.
+func encodeArray(rt *goja.Runtime, fn goja.Callable) ([]string, error) {
+	v, err := fn(goja.Undefined())
+	if err != nil {
+		return nil, err
+	}
+	isArray, err := builtin(rt, "Array", "isArray")(goja.Undefined(), v)
+	if err != nil {
+		return nil, err
+	}
+	if !isArray.ToBoolean() {
+		return nil, errors.New("the function must return an array")
+	}
+	stringify := builtin(rt, "JSON", "stringify")
+
+	obj := v.ToObject(rt)
+	elements := make([]string, obj.Get("length").ToInteger())
+	for i := range elements {
+		e, err := stringify(goja.Undefined(), obj.Get(strconv.Itoa(i)))
+		if err != nil {
+			return nil, err
+		}
+		if goja.IsUndefined(e) {
+			// Same as JSON.stringify() does for functions and undefined inside of arrays
+			elements[i] = "null"
+			continue
+		}
+		elements[i] = e.String()
+	}
+	return elements, nil
+}
+
+// SharedArray is a VU's view of a shared array. Its elements can't be reached with an index
+// expression, since the JS runtime doesn't support proxies, so they're read with get() instead.
+type SharedArray struct {
+	elements []string
+}
+
+// Get returns a copy of the element at the given index, or undefined if it's out of range.
+// Changing the copy doesn't change the shared data, nor what the other VUs see.
+func (a SharedArray) Get(ctx context.Context, index int) (goja.Value, error) {
+	if index < 0 || index >= len(a.elements) {
+		return goja.Undefined(), nil
+	}
+	rt := common.GetRuntime(ctx)
+	return builtin(rt, "JSON", "parse")(goja.Undefined(), rt.ToValue(a.elements[index]))
+}
+
+// builtin returns a function of one of the runtime's global objects, e.g. JSON.parse().
+func builtin(rt *goja.Runtime, object, name string) goja.Callable {
+	fn, _ := goja.AssertFunction(rt.Get(object).ToObject(rt).Get(name))
+	return fn
+}
@@ -0,0 +1,117 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package data
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dop251/goja"
+	"github.com/loadimpact/k6/js/common"
+	"github.com/loadimpact/k6/lib"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRuntime(t *testing.T, mod *Data) (*goja.Runtime, *context.Context) {
+	rt := goja.New()
+	rt.SetFieldNameMapper(common.FieldNameMapper{})
+
+	ctxPtr := new(context.Context)
+	*ctxPtr = common.WithRuntime(context.Background(), rt)
+	rt.Set("data", common.Bind(rt, mod, ctxPtr))
+	return rt, ctxPtr
+}
+
+func TestSharedArray(t *testing.T) {
+	mod := New()
+	rt1, _ := newRuntime(t, mod)
+	rt2, ctxPtr2 := newRuntime(t, mod)
+
+	_, err := common.RunString(rt1, `
+		var calls = 0;
+		var arr = new data.SharedArray("users", function() {
+			calls++;
+			return [{name: "alice", roles: ["admin"]}, {name: "bob"}, 42, undefined];
+		});
+	`)
+	require.NoError(t, err)
+
+	t.Run("OnlyCalledOnce", func(t *testing.T) {
+		_, err := common.RunString(rt2, `
+			var calls = 0;
+			var arr = new data.SharedArray("users", function() { calls++; return []; });
+		`)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), rt1.Get("calls").ToInteger())
+		assert.Equal(t, int64(0), rt2.Get("calls").ToInteger())
+	})
+
+	t.Run("Get", func(t *testing.T) {
+		v, err := common.RunString(rt2, `
+			if (arr.length !== 4) { throw new Error("wrong length " + arr.length); }
+			if (arr.get(0).roles[0] !== "admin") { throw new Error("wrong element"); }
+			if (arr.get(2) !== 42) { throw new Error("wrong number"); }
+			if (arr.get(3) !== null) { throw new Error("undefined isn't null"); }
+			if (arr.get(4) !== undefined || arr.get(-1) !== undefined) { throw new Error("out of range"); }
+			arr.get(1).name;
+		`)
+		require.NoError(t, err)
+		assert.Equal(t, "bob", v.String())
+	})
+
+	t.Run("Copies", func(t *testing.T) {
+		_, err := common.RunString(rt1, `arr.get(1).name = "mallory"`)
+		require.NoError(t, err)
+		v, err := common.RunString(rt2, `arr.get(1).name`)
+		require.NoError(t, err)
+		assert.Equal(t, "bob", v.String())
+	})
+
+	t.Run("NotInitContext", func(t *testing.T) {
+		*ctxPtr2 = lib.WithState(*ctxPtr2, &lib.State{})
+		_, err := common.RunString(rt2, `new data.SharedArray("other", function() { return []; })`)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "must be constructed in the init context")
+
+		v, err := common.RunString(rt2, `arr.get(1).name`)
+		require.NoError(t, err)
+		assert.Equal(t, "bob", v.String())
+	})
+}
+
+func TestSharedArrayErrors(t *testing.T) {
+	rt, _ := newRuntime(t, New())
+	testdata := map[string]struct{ script, err string }{
+		"NoName":      {`new data.SharedArray("", function() { return []; })`, "needs a name"},
+		"NoFunction":  {`new data.SharedArray("a", [1, 2])`, "needs a function"},
+		"NotAnArray":  {`new data.SharedArray("b", function() { return {a: 1}; })`, "must return an array"},
+		"ReturnsNull": {`new data.SharedArray("c", function() { return null; })`, "must return an array"},
+		"Throws":      {`new data.SharedArray("d", function() { throw new Error("oops"); })`, "oops"},
+	}
+	for name, data := range testdata {
+		t.Run(name, func(t *testing.T) {
+			_, err := common.RunString(rt, data.script)
+			require.Error(t, err)
+			assert.Contains(t, err.Error(), data.err)
+		})
+	}
+}
@@ -0,0 +1,71 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package events
+
+import (
+	"context"
+	"time"
+
+	"github.com/loadimpact/k6/js/common"
+	"github.com/loadimpact/k6/lib"
+	"github.com/loadimpact/k6/stats"
+	"github.com/pkg/errors"
+)
+
+// ErrRecordInInitContext is returned when an event is recorded in the init context
+var ErrRecordInInitContext = common.NewInitContextError("Recording events in the init context is not supported")
+
+// Events is the k6/events module, which records annotations of the test run.
+type Events struct{}
+
+// New returns a new Events module.
+func New() *Events {
+	return &Events{}
+}
+
+// Record records an event with the given message at the current time. It's tagged like a custom
+// metric sample would be, along with any extra tags.
+func (*Events) Record(ctx context.Context, message string, addTags ...map[string]string) (bool, error) {
+	state := lib.GetState(ctx)
+	if state == nil {
+		return false, ErrRecordInInitContext
+	}
+	if message == "" {
+		return false, errors.New("an event needs a message")
+	}
+
+	tags := state.Options.RunTags.CloneTags()
+	if state.Options.SystemTags["group"] {
+		tags["group"] = state.Group.Path
+	}
+	for _, ts := range addTags {
+		for k, v := range ts {
+			tags[k] = v
+		}
+	}
+
+	stats.PushIfNotCancelled(ctx, state.Samples, stats.Event{
+		Time:    time.Now(),
+		Message: message,
+		Tags:    stats.IntoSampleTags(&tags),
+	})
+	return true, nil
+}
@@ -0,0 +1,94 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package events
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dop251/goja"
+	"github.com/loadimpact/k6/js/common"
+	"github.com/loadimpact/k6/lib"
+	"github.com/loadimpact/k6/stats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecord(t *testing.T) {
+	rt := goja.New()
+	rt.SetFieldNameMapper(common.FieldNameMapper{})
+
+	ctxPtr := new(context.Context)
+	*ctxPtr = common.WithRuntime(context.Background(), rt)
+	rt.Set("events", common.Bind(rt, New(), ctxPtr))
+
+	t.Run("InitContext", func(t *testing.T) {
+		_, err := common.RunString(rt, `events.record("deployed")`)
+		assert.Contains(t, err.Error(), ErrRecordInInitContext.Error())
+	})
+
+	root, err := lib.NewGroup("", nil)
+	require.NoError(t, err)
+	child, err := root.Group("child")
+	require.NoError(t, err)
+	samples := make(chan stats.SampleContainer, 1000)
+	runTags := map[string]string{"testid": "123"}
+	*ctxPtr = lib.WithState(*ctxPtr, &lib.State{
+		Options: lib.Options{
+			SystemTags: lib.GetTagSet("group"),
+			RunTags:    stats.IntoSampleTags(&runTags),
+		},
+		Group:   child,
+		Samples: samples,
+	})
+
+	t.Run("Message", func(t *testing.T) {
+		_, err := common.RunString(rt, `events.record("deployed v2")`)
+		require.NoError(t, err)
+
+		containers := stats.GetBufferedSamples(samples)
+		require.Len(t, containers, 1)
+		event, ok := containers[0].(stats.Event)
+		require.True(t, ok)
+		assert.NotZero(t, event.Time)
+		assert.Equal(t, "deployed v2", event.Message)
+		assert.Equal(t, map[string]string{"testid": "123", "group": child.Path}, event.Tags.CloneTags())
+	})
+
+	t.Run("Tags", func(t *testing.T) {
+		_, err := common.RunString(rt, `events.record("fault injected", {fault: "latency", testid: "456"})`)
+		require.NoError(t, err)
+
+		containers := stats.GetBufferedSamples(samples)
+		require.Len(t, containers, 1)
+		event, ok := containers[0].(stats.Event)
+		require.True(t, ok)
+		assert.Equal(t, "fault injected", event.Message)
+		assert.Equal(t, map[string]string{"testid": "456", "group": child.Path, "fault": "latency"},
+			event.Tags.CloneTags())
+	})
+
+	t.Run("NoMessage", func(t *testing.T) {
+		_, err := common.RunString(rt, `events.record("")`)
+		assert.Error(t, err)
+		assert.Empty(t, stats.GetBufferedSamples(samples))
+	})
+}
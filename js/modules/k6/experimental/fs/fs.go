@@ -0,0 +1,175 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package fs
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"strings"
+
+	"github.com/loadimpact/k6/js/common"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// defaultBufferSize is the size of the buffer used to read lines; longer lines still work.
+const defaultBufferSize = 64 * 1024
+
+// FS is the k6/experimental/fs module, which streams files instead of loading them whole like
+// open() does, so even files that are too big to fit in the memory of every VU can be used.
+type FS struct {
+	SeekStart   int `js:"SeekStart"`
+	SeekCurrent int `js:"SeekCurrent"`
+	SeekEnd     int `js:"SeekEnd"`
+}
+
+// New returns a new FS module.
+func New() *FS {
+	return &FS{
+		SeekStart:   io.SeekStart,
+		SeekCurrent: io.SeekCurrent,
+		SeekEnd:     io.SeekEnd,
+	}
+}
+
+// Open opens a file in the init context, the same way as open() would, so the file is also saved
+// in archives. Every VU gets its own handle, with its own position, which is then read from during
+// the iterations.
+func (*FS) Open(ctxPtr *context.Context, filename string) (map[string]interface{}, error) {
+	env := common.GetInitEnv(*ctxPtr)
+	if env == nil {
+		return nil, common.NewInitContextError("files can only be opened in the init context")
+	}
+	f, err := env.OpenFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+
+	obj := common.Bind(common.GetRuntime(*ctxPtr), &File{f: f, r: bufio.NewReaderSize(f, defaultBufferSize)}, ctxPtr)
+	obj["name"] = info.Name()
+	obj["size"] = info.Size()
+	return obj, nil
+}
+
+// errClosed is returned by the methods of a File after it has been closed.
+var errClosed = errors.New("the file is closed") //nolint:gochecknoglobals
+
+// File is a handle of a file that was opened in the init context. Reads are buffered, so lines and
+// small chunks can be read without going to the filesystem every time.
+type File struct {
+	f afero.File
+	r *bufio.Reader
+
+	closed bool
+}
+
+// ReadLine returns the next line, without the line ending (either "\n" or "\r\n"), or null once
+// the end of the file is reached. The last line doesn't need to end with a line ending.
+func (f *File) ReadLine() (interface{}, error) {
+	if f.closed {
+		return nil, errClosed
+	}
+	line, err := f.r.ReadString('\n')
+	if err == io.EOF {
+		if line == "" {
+			return nil, nil
+		}
+	} else if err != nil {
+		return nil, err
+	}
+	line = strings.TrimSuffix(line, "\n")
+	return strings.TrimSuffix(line, "\r"), nil
+}
+
+// Read returns the next chunk of the file as an array of bytes, which is only shorter than the
+// given size at the end of the file, or null once the end of the file is reached.
+func (f *File) Read(size int) (interface{}, error) {
+	if size <= 0 {
+		return nil, errors.Errorf("invalid chunk size %d", size)
+	}
+	if f.closed {
+		return nil, errClosed
+	}
+	buf := make([]byte, size)
+	n, err := io.ReadFull(f.r, buf)
+	switch {
+	case err == io.EOF:
+		return nil, nil
+	case err != nil && err != io.ErrUnexpectedEOF:
+		return nil, err
+	}
+	return buf[:n], nil
+}
+
+// Seek sets the position of the next read, relative to the start of the file (SeekStart, the
+// default), the current position (SeekCurrent) or the end of the file (SeekEnd). It returns the
+// new position, so seek(0, SeekCurrent) tells the current one.
+func (f *File) Seek(offset int64, whence int) (int64, error) {
+	if f.closed {
+		return 0, errClosed
+	}
+	var base int64
+	switch whence {
+	case io.SeekStart:
+	case io.SeekCurrent:
+		pos, err := f.f.Seek(0, io.SeekCurrent)
+		if err != nil {
+			return 0, err
+		}
+		// The file itself is ahead by what's buffered but wasn't read yet.
+		base = pos - int64(f.r.Buffered())
+	case io.SeekEnd:
+		info, err := f.f.Stat()
+		if err != nil {
+			return 0, err
+		}
+		base = info.Size()
+	default:
+		return 0, errors.Errorf("invalid whence %d", whence)
+	}
+	if base+offset < 0 {
+		return 0, errors.New("can't seek before the start of the file")
+	}
+
+	pos, err := f.f.Seek(base+offset, io.SeekStart)
+	if err != nil {
+		return 0, err
+	}
+	f.r.Reset(f.f)
+	return pos, nil
+}
+
+// Close closes the file, after which it can't be read anymore. Files that aren't needed anymore,
+// e.g. once all of their lines have been read, should be closed, since they stay open until k6
+// exits otherwise. Closing a file more than once does nothing.
+func (f *File) Close() error {
+	if f.closed {
+		return nil
+	}
+	f.closed = true
+	return f.f.Close()
+}
@@ -0,0 +1,148 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package fs
+
+import (
+	"context"
+	"testing"
+
+	"github.com/dop251/goja"
+	"github.com/loadimpact/k6/js/common"
+	"github.com/loadimpact/k6/lib"
+	"github.com/spf13/afero"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testInitEnv struct {
+	fs afero.Fs
+}
+
+func (env testInitEnv) OpenFile(filename string) (afero.File, error) {
+	return env.fs.Open(filename)
+}
+
+func newRuntime(t *testing.T) (*goja.Runtime, *context.Context) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(fs, "/data.log", []byte("first\r\nsecond\n\nlast"), 0644))
+
+	rt := goja.New()
+	rt.SetFieldNameMapper(common.FieldNameMapper{})
+	ctxPtr := new(context.Context)
+	*ctxPtr = common.WithInitEnv(common.WithRuntime(context.Background(), rt), testInitEnv{fs})
+	rt.Set("fs", common.Bind(rt, New(), ctxPtr))
+
+	_, err := common.RunString(rt, `var file = fs.open("/data.log");`)
+	require.NoError(t, err)
+
+	// Like in a VU, after the init context is done
+	*ctxPtr = lib.WithState(common.WithRuntime(context.Background(), rt), &lib.State{})
+	return rt, ctxPtr
+}
+
+func TestOpen(t *testing.T) {
+	t.Run("Info", func(t *testing.T) {
+		rt, _ := newRuntime(t)
+		v, err := common.RunString(rt, `file.name + " " + file.size`)
+		require.NoError(t, err)
+		assert.Equal(t, "data.log 19", v.String())
+	})
+
+	t.Run("Nonexistent", func(t *testing.T) {
+		rt, ctxPtr := newRuntime(t)
+		*ctxPtr = common.WithInitEnv(common.WithRuntime(context.Background(), rt), testInitEnv{afero.NewMemMapFs()})
+		_, err := common.RunString(rt, `fs.open("/data.log")`)
+		assert.EqualError(t, err, "GoError: open /data.log: file does not exist")
+	})
+
+	t.Run("OutsideInitContext", func(t *testing.T) {
+		rt, _ := newRuntime(t)
+		_, err := common.RunString(rt, `fs.open("/data.log")`)
+		assert.EqualError(t, err, "GoError: files can only be opened in the init context")
+	})
+}
+
+func TestFile(t *testing.T) {
+	t.Run("ReadLine", func(t *testing.T) {
+		rt, _ := newRuntime(t)
+		v, err := common.RunString(rt, `
+			var lines = [], line;
+			while ((line = file.readLine()) !== null) { lines.push(line); }
+			JSON.stringify(lines);
+		`)
+		require.NoError(t, err)
+		assert.Equal(t, `["first","second","","last"]`, v.String())
+	})
+
+	t.Run("Read", func(t *testing.T) {
+		rt, _ := newRuntime(t)
+		v, err := common.RunString(rt, `
+			var chunks = [], chunk;
+			while ((chunk = file.read(8)) !== null) { chunks.push(chunk.length); }
+			chunks.join(",");
+		`)
+		require.NoError(t, err)
+		assert.Equal(t, "8,8,3", v.String())
+
+		_, err = common.RunString(rt, `file.read(0)`)
+		assert.EqualError(t, err, "GoError: invalid chunk size 0")
+	})
+
+	t.Run("Seek", func(t *testing.T) {
+		rt, _ := newRuntime(t)
+		v, err := common.RunString(rt, `
+			var res = [file.readLine(), file.seek(0, fs.SeekCurrent), file.readLine()];
+			res.push(file.seek(-4, fs.SeekEnd), file.readLine());
+			res.push(file.seek(2), String.fromCharCode.apply(null, file.read(3)));
+			res.push(file.seek(-2, fs.SeekCurrent), file.readLine());
+			JSON.stringify(res);
+		`)
+		require.NoError(t, err)
+		assert.Equal(t, `["first",7,"second",15,"last",2,"rst",3,"st"]`, v.String())
+
+		_, err = common.RunString(rt, `file.seek(-1)`)
+		assert.EqualError(t, err, "GoError: can't seek before the start of the file")
+		_, err = common.RunString(rt, `file.seek(0, 3)`)
+		assert.EqualError(t, err, "GoError: invalid whence 3")
+	})
+
+	t.Run("PerVU", func(t *testing.T) {
+		rt1, _ := newRuntime(t)
+		rt2, _ := newRuntime(t)
+		_, err := common.RunString(rt1, `file.readLine()`)
+		require.NoError(t, err)
+		v, err := common.RunString(rt2, `file.readLine()`)
+		require.NoError(t, err)
+		assert.Equal(t, "first", v.String())
+	})
+
+	t.Run("Close", func(t *testing.T) {
+		rt, _ := newRuntime(t)
+		_, err := common.RunString(rt, `file.close(); file.close();`)
+		require.NoError(t, err)
+		_, err = common.RunString(rt, `file.readLine()`)
+		assert.EqualError(t, err, "GoError: the file is closed")
+		_, err = common.RunString(rt, `file.read(1)`)
+		assert.EqualError(t, err, "GoError: the file is closed")
+		_, err = common.RunString(rt, `file.seek(0)`)
+		assert.EqualError(t, err, "GoError: the file is closed")
+	})
+}
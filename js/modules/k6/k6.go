@@ -89,6 +89,9 @@ func (*K6) Group(ctx context.Context, name string, fn goja.Callable) (goja.Value
 	startTime := time.Now()
 	ret, err := fn(goja.Undefined())
 	t := time.Now()
+	if err != nil && err != state.GroupError {
+		state.GroupError, state.GroupErrorGroup = err, g
+	}
 
 	tags := state.Options.RunTags.CloneTags()
 	if state.Options.SystemTags["group"] {
@@ -190,7 +190,7 @@ func (r *Runner) newVU(samplesOut chan<- stats.SampleContainer) (*VU, error) {
 		Samples:        samplesOut,
 		m:              &sync.Mutex{},
 	}
-	vu.Runtime.Set("console", common.Bind(vu.Runtime, vu.Console, vu.Context))
+	vu.Runtime.Set("console", vu.Console.bind(vu.Runtime, vu.Context))
 	common.BindToGlobal(vu.Runtime, map[string]interface{}{
 		"open": func() {
 			common.Throw(vu.Runtime, errors.New("\"open\" function is only available to the init code (aka global scope), see https://docs.k6.io/docs/test-life-cycle for more information"))
@@ -283,6 +283,9 @@ func (r *Runner) SetOptions(opts lib.Options) error {
 
 		r.console = c
 	}
+	if opts.ConsoleRateLimit.Valid {
+		r.console.limiter = newConsoleLimiter(opts.ConsoleRateLimit.Int64)
+	}
 
 	return nil
 }
@@ -406,10 +409,47 @@ func (u *VU) RunOnce(ctx context.Context) error {
 	}
 
 	// Call the default function.
-	_, _, err := u.runFn(ctx, u.Runner.defaultGroup, u.Default, u.setupData)
+	_, state, err := u.runFn(ctx, u.Runner.defaultGroup, u.Default, u.setupData)
+	// Errors of interrupted iterations aren't classified, since they're not counted anyway
+	if err != nil && ctx.Err() == nil {
+		return iterationError(err, state)
+	}
 	return err
 }
 
+// iterationError classifies an error thrown out of the default function by the name and message
+// of the thrown value, and attributes it to the group it was thrown in.
+func iterationError(err error, state *lib.State) *lib.IterationError {
+	ierr := lib.NewIterationError(err)
+	if state != nil {
+		ierr.VU, ierr.Iteration = state.Vu, state.Iteration
+		ierr.Group = state.Group
+		if state.GroupError == err {
+			ierr.Group = state.GroupErrorGroup
+		}
+	}
+
+	var value goja.Value
+	switch e := err.(type) {
+	case *goja.Exception:
+		value = e.Value()
+	case *promiseRejection:
+		value = e.reason
+	}
+	if value == nil {
+		return ierr
+	}
+
+	ierr.Type, ierr.Message = "Error", lib.NormalizeErrorMessage(value.String())
+	if obj, ok := value.(*goja.Object); ok {
+		name, message := obj.Get("name"), obj.Get("message")
+		if name != nil && !goja.IsUndefined(name) && message != nil && !goja.IsUndefined(message) {
+			ierr.Type, ierr.Message = name.String(), lib.NormalizeErrorMessage(message.String())
+		}
+	}
+	return ierr
+}
+
 func (u *VU) runFn(
 	ctx context.Context, group *lib.Group, fn goja.Callable, args ...goja.Value,
 ) (goja.Value, *lib.State, error) {
@@ -438,6 +478,7 @@ func (u *VU) runFn(
 	}
 
 	newctx := common.WithRuntime(ctx, u.Runtime)
+	newctx = common.WithEventLoop(newctx, u.EventLoop)
 	newctx = lib.WithState(newctx, state)
 	*u.Context = newctx
 
@@ -445,8 +486,20 @@ func (u *VU) runFn(
 	iter := u.Iteration
 	u.Iteration++
 
+	// Actually run the JS script, along with any timers and promises it leaves behind
+	var v goja.Value
+	var rejection error
 	startTime := time.Now()
-	v, err := fn(goja.Undefined(), args...) // Actually run the JS script
+	err = u.EventLoop.Run(ctx, func() error {
+		res, err := fn(goja.Undefined(), args...)
+		if err != nil {
+			return err
+		}
+		return awaitValue(u.Runtime, res, &v, &rejection)
+	})
+	if err == nil {
+		err = rejection
+	}
 	endTime := time.Now()
 
 	var isFullIteration bool
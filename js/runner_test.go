@@ -433,6 +433,92 @@ func TestRunnerIntegrationImports(t *testing.T) {
 	})
 }
 
+func TestVURunSourceMap(t *testing.T) {
+	fs := afero.NewMemMapFs()
+	require.NoError(t, fs.MkdirAll("/path/to", 0755))
+	require.NoError(t, afero.WriteFile(fs, "/path/to/es6.js", []byte(`let a = 1;
+export function fail() {
+	let msg = `+"`boom`"+`; throw new Error(msg);
+}`), 0644))
+
+	testdata := map[string]struct{ script, position string }{
+		"Script": {`
+			import { fail } from "./to/es6.js";
+			export default function() {
+				let fn = () => { throw new Error("boom"); };
+				fn();
+			}`, "file:///path/script.js:4:"},
+		"Module": {`
+			import { fail } from "./to/es6.js";
+			export default function() { fail(); }`, "file:///path/to/es6.js:3:"},
+	}
+	for name, data := range testdata {
+		data := data
+		t.Run(name, func(t *testing.T) {
+			r1, err := getSimpleRunnerWithFileFs("/path/script.js", data.script, fs)
+			require.NoError(t, err)
+
+			r2, err := NewFromArchive(r1.MakeArchive(), lib.RuntimeOptions{})
+			require.NoError(t, err)
+
+			testdata := map[string]*Runner{"Source": r1, "Archive": r2}
+			for name, r := range testdata {
+				r := r
+				t.Run(name, func(t *testing.T) {
+					vu, err := r.NewVU(make(chan stats.SampleContainer, 100))
+					require.NoError(t, err)
+					err = vu.RunOnce(context.Background())
+					require.Error(t, err)
+					assert.Contains(t, err.Error(), "Error: boom at ")
+					assert.Contains(t, err.Error(), data.position)
+				})
+			}
+		})
+	}
+}
+
+func TestVURunIterationError(t *testing.T) {
+	testdata := map[string]struct {
+		script, errorType, message, group string
+	}{
+		"TypeError": {`
+			import { group } from "k6";
+			export default function() {
+				group("outer", function() { group("inner", function() { let o; o.x; }); });
+			}`, "TypeError", "Cannot read property <string> of undefined", "::outer::inner"},
+		"Caught": {`
+			import { group } from "k6";
+			export default function() {
+				group("outer", function() {
+					try { group("inner", function() { throw new Error("caught"); }); } catch (e) {}
+					throw new RangeError("uncaught");
+				});
+			}`, "RangeError", "uncaught", "::outer"},
+		"Value": {`
+			export default function() { throw "  not\n an error"; }`, "Error", "not", ""},
+		"Rejection": {`
+			export default async function() { throw new SyntaxError("async"); }`, "SyntaxError", "async", ""},
+	}
+	for name, data := range testdata {
+		data := data
+		t.Run(name, func(t *testing.T) {
+			r, err := getSimpleRunner("/script.js", data.script)
+			require.NoError(t, err)
+
+			vu, err := r.NewVU(make(chan stats.SampleContainer, 100))
+			require.NoError(t, err)
+			err = vu.RunOnce(context.Background())
+			require.IsType(t, &lib.IterationError{}, err)
+			ierr := err.(*lib.IterationError)
+			assert.Equal(t, data.errorType, ierr.Type)
+			assert.Equal(t, data.message, ierr.Message)
+			if assert.NotNil(t, ierr.Group) {
+				assert.Equal(t, data.group, ierr.Group.Path)
+			}
+		})
+	}
+}
+
 func TestVURunContext(t *testing.T) {
 	r1, err := getSimpleRunner("/script.js", `
 		export let options = { vus: 10 };
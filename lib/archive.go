@@ -90,6 +90,10 @@ type Archive struct {
 
 	K6Version string `json:"k6version"`
 	Goos      string `json:"goos"`
+
+	// Transpiled code of the files that needed it, keyed by a hash of their contents. It's opaque
+	// to the archive and optional, since the runner can always compile the sources again.
+	Transpiled map[string]json.RawMessage `json:"-"`
 }
 
 func (arc *Archive) getFs(name string) afero.Fs {
@@ -152,6 +156,11 @@ func ReadArchive(in io.Reader) (*Archive, error) {
 		case "data":
 			arc.Data = data
 			continue
+		case "transpiled.json":
+			if err = json.Unmarshal(data, &arc.Transpiled); err != nil {
+				return nil, err
+			}
+			continue
 		}
 
 		// Path separator normalization for older archives (<=0.20.0)
@@ -271,13 +280,35 @@ func (arc *Archive) Write(out io.Writer) error {
 	if _, err = w.Write(arc.Data); err != nil {
 		return err
 	}
+
+	// Older versions skip top-level files they don't know, so they can still read the archive.
+	if len(arc.Transpiled) > 0 {
+		transpiled, err := json.Marshal(arc.Transpiled)
+		if err != nil {
+			return err
+		}
+		_ = w.WriteHeader(&tar.Header{
+			Name:     "transpiled.json",
+			Mode:     0644,
+			Size:     int64(len(transpiled)),
+			ModTime:  now,
+			Typeflag: tar.TypeReg,
+		})
+		if _, err = w.Write(transpiled); err != nil {
+			return err
+		}
+	}
+
 	for _, name := range [...]string{"file", "https"} {
 		filesystem, ok := arc.Filesystems[name]
 		if !ok {
 			continue
 		}
+		var streamed []string
+		var basefs afero.Fs
 		if cachedfs, ok := filesystem.(fsext.CacheOnReadFs); ok {
 			filesystem = cachedfs.GetCachingFs()
+			streamed, basefs = cachedfs.GetStreamedFiles(), cachedfs.GetBaseFs()
 		}
 
 		// A couple of things going on here:
@@ -312,6 +343,23 @@ func (arc *Archive) Write(out io.Writer) error {
 		if err = fsext.Walk(filesystem, afero.FilePathSeparator, walkFunc); err != nil {
 			return err
 		}
+		// Streamed files were never copied to the cache, so they're only read from the disk now
+		for _, filePath := range streamed {
+			normalizedPath := NormalizeAndAnonymizePath(filePath)
+			if _, ok := files[normalizedPath]; ok {
+				continue
+			}
+			if infos[normalizedPath], err = basefs.Stat(filePath); err != nil {
+				return err
+			}
+			if files[normalizedPath], err = afero.ReadFile(basefs, filePath); err != nil {
+				return err
+			}
+			paths = append(paths, normalizedPath)
+			for dir := path.Dir(normalizedPath); !foundDirs[dir]; dir = path.Dir(dir) {
+				foundDirs[dir] = true
+			}
+		}
 		if len(files) == 0 {
 			continue // we don't need to write anything for this fs, if this is not done the root will be written
 		}
@@ -22,6 +22,7 @@ package lib
 
 import (
 	"bytes"
+	"encoding/json"
 	"fmt"
 	"net/url"
 	"os"
@@ -157,6 +158,9 @@ func TestArchiveReadWrite(t *testing.T) {
 					"/github.com/loadimpact/k6/README.md": []byte(`README`),
 				}),
 			},
+			Transpiled: map[string]json.RawMessage{
+				"0123abcd": json.RawMessage(`{"code":"\"use strict\";var a = 1;"}`),
+			},
 		}
 
 		buf := bytes.NewBuffer(nil)
@@ -297,6 +301,40 @@ func TestUsingCacheFromCacheOnReadFs(t *testing.T) {
 	require.Nil(t, data)
 }
 
+func TestStreamedFilesFromCacheOnReadFs(t *testing.T) {
+	var base = afero.NewMemMapFs()
+	var cached = afero.NewMemMapFs()
+	require.NoError(t, afero.WriteFile(base, "/data/big.csv", []byte(`a,b`), 0644))
+	require.NoError(t, afero.WriteFile(cached, "/script.js", []byte(`test`), 0644))
+	fs := fsext.NewCacheOnReadFs(base, cached, 0)
+
+	f, err := fs.(fsext.CacheOnReadFs).Stream("/data/big.csv")
+	require.NoError(t, err)
+	require.NoError(t, f.Close())
+	exists, err := afero.Exists(cached, "/data/big.csv")
+	require.NoError(t, err)
+	require.False(t, exists)
+
+	arc := &Archive{
+		Type:        "js",
+		FilenameURL: &url.URL{Scheme: "file", Path: "/script.js"},
+		K6Version:   consts.Version,
+		Data:        []byte(`test`),
+		PwdURL:      &url.URL{Scheme: "file", Path: "/"},
+		Filesystems: map[string]afero.Fs{"file": fs},
+	}
+
+	buf := bytes.NewBuffer(nil)
+	require.NoError(t, arc.Write(buf))
+
+	newArc, err := ReadArchive(buf)
+	require.NoError(t, err)
+
+	data, err := afero.ReadFile(newArc.Filesystems["file"], "/data/big.csv")
+	require.NoError(t, err)
+	require.Equal(t, "a,b", string(data))
+}
+
 func TestArchiveWithDataNotInFS(t *testing.T) {
 	t.Parallel()
 
@@ -22,6 +22,7 @@ package lib
 
 import (
 	"context"
+	"fmt"
 
 	"github.com/loadimpact/k6/stats"
 )
@@ -44,8 +45,43 @@ const (
 	RunStatusAbortedSystem      RunStatus = 6
 	RunStatusAbortedScriptError RunStatus = 7
 	RunStatusAbortedThreshold   RunStatus = 8
+
+	// Only used for local runs, never sent to or received from the cloud.
+	RunStatusSetup    RunStatus = 9
+	RunStatusTeardown RunStatus = 10
 )
 
+//nolint:gochecknoglobals
+var runStatusNames = map[RunStatus]string{
+	RunStatusCreated:            "created",
+	RunStatusValidated:          "validated",
+	RunStatusQueued:             "queued",
+	RunStatusInitializing:       "initializing",
+	RunStatusRunning:            "running",
+	RunStatusFinished:           "finished",
+	RunStatusTimedOut:           "timed_out",
+	RunStatusAbortedUser:        "aborted_by_user",
+	RunStatusAbortedSystem:      "aborted_by_system",
+	RunStatusAbortedScriptError: "aborted_by_script_error",
+	RunStatusAbortedThreshold:   "aborted_by_threshold",
+	RunStatusSetup:              "setup",
+	RunStatusTeardown:           "teardown",
+}
+
+// String returns the name of the status, e.g. "aborted_by_user". It's not used for the JSON
+// encoding, since the cloud API uses the numeric values.
+func (rs RunStatus) String() string {
+	if name, ok := runStatusNames[rs]; ok {
+		return name
+	}
+	return fmt.Sprintf("RunStatus(%d)", int(rs))
+}
+
+// IsFinal returns whether the status is one of the ways a test run can end.
+func (rs RunStatus) IsFinal() bool {
+	return rs >= RunStatusFinished && rs <= RunStatusAbortedThreshold
+}
+
 // A Collector abstracts the process of funneling samples to an external storage backend,
 // such as an InfluxDB instance.
 type Collector interface {
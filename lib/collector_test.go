@@ -0,0 +1,46 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package lib
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRunStatusString(t *testing.T) {
+	testdata := map[RunStatus]string{
+		RunStatusQueued:             "queued",
+		RunStatusSetup:              "setup",
+		RunStatusRunning:            "running",
+		RunStatusTeardown:           "teardown",
+		RunStatusAbortedScriptError: "aborted_by_script_error",
+		RunStatus(42):               "RunStatus(42)",
+	}
+	for status, name := range testdata {
+		assert.Equal(t, name, status.String())
+	}
+
+	assert.True(t, RunStatusFinished.IsFinal())
+	assert.True(t, RunStatusAbortedThreshold.IsFinal())
+	assert.False(t, RunStatusRunning.IsFinal())
+	assert.False(t, RunStatusTeardown.IsFinal())
+}
@@ -85,3 +85,38 @@ type Executor interface {
 	SetRunSetup(r bool)
 	SetRunTeardown(r bool)
 }
+
+// RunStatusGetter is an optional interface for executors that can report the phase they're in:
+// initializing until Run() is called, then setup, running and teardown, and finished once it has
+// returned.
+type RunStatusGetter interface {
+	GetRunStatus() RunStatus
+}
+
+// GetPlannedDuration returns how long the executor's test is planned to take, going by the
+// earlier of its end time and the end of its stages, if either is set.
+func GetPlannedDuration(ex Executor) types.NullDuration {
+	stagesEndT := SumStages(ex.GetStages())
+	endT := ex.GetEndTime()
+	if !endT.Valid || (stagesEndT.Valid && endT.Duration > stagesEndT.Duration) {
+		endT = stagesEndT
+	}
+	return endT
+}
+
+// GetProgress returns how far along the executor's test is, from 0 to 1, going by its end
+// iterations if they're set, or its planned duration. It's 0 if the test doesn't have an end.
+func GetProgress(ex Executor) float64 {
+	var progress float64
+	if endIt := ex.GetEndIterations(); endIt.Valid {
+		if endIt.Int64 > 0 {
+			progress = float64(ex.GetIterations()) / float64(endIt.Int64)
+		}
+	} else if endT := GetPlannedDuration(ex); endT.Valid && endT.Duration > 0 {
+		progress = float64(ex.GetTime()) / float64(endT.Duration)
+	}
+	if progress > 1 {
+		progress = 1
+	}
+	return progress
+}
@@ -1,6 +1,8 @@
 package fsext
 
 import (
+	"sort"
+	"sync"
 	"time"
 
 	"github.com/spf13/afero"
@@ -10,14 +12,25 @@ import (
 // that is used as cache
 type CacheOnReadFs struct {
 	afero.Fs
+	base  afero.Fs
 	cache afero.Fs
+
+	streamed *streamedFiles
+}
+
+// streamedFiles are the names of the files that were opened with CacheOnReadFs.Stream().
+type streamedFiles struct {
+	mu    sync.Mutex
+	names map[string]struct{}
 }
 
 // NewCacheOnReadFs returns a new CacheOnReadFs
 func NewCacheOnReadFs(base, layer afero.Fs, cacheTime time.Duration) afero.Fs {
 	return CacheOnReadFs{
-		Fs:    afero.NewCacheOnReadFs(base, layer, cacheTime),
-		cache: layer,
+		Fs:       afero.NewCacheOnReadFs(base, layer, cacheTime),
+		base:     base,
+		cache:    layer,
+		streamed: &streamedFiles{names: make(map[string]struct{})},
 	}
 }
 
@@ -25,3 +38,33 @@ func NewCacheOnReadFs(base, layer afero.Fs, cacheTime time.Duration) afero.Fs {
 func (c CacheOnReadFs) GetCachingFs() afero.Fs {
 	return c.cache
 }
+
+// GetBaseFs returns the afero.Fs that the files are read from
+func (c CacheOnReadFs) GetBaseFs() afero.Fs {
+	return c.base
+}
+
+// Stream opens a file of the base filesystem for reading, without copying it to the cache, so
+// big files don't have to fit in memory. Only its name is recorded, see GetStreamedFiles().
+func (c CacheOnReadFs) Stream(name string) (afero.File, error) {
+	f, err := c.base.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	c.streamed.mu.Lock()
+	c.streamed.names[name] = struct{}{}
+	c.streamed.mu.Unlock()
+	return f, nil
+}
+
+// GetStreamedFiles returns the sorted names of the files that were opened with Stream()
+func (c CacheOnReadFs) GetStreamedFiles() []string {
+	c.streamed.mu.Lock()
+	defer c.streamed.mu.Unlock()
+	names := make([]string, 0, len(c.streamed.names))
+	for name := range c.streamed.names {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
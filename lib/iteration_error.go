@@ -0,0 +1,121 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package lib
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxErrorMessageLength is the length that error messages are cut to by NormalizeErrorMessage.
+const MaxErrorMessageLength = 200
+
+// The variable parts of error messages that NormalizeErrorMessage replaces with placeholders. A
+// quote only starts a string if it isn't preceded by a letter or a digit, so that apostrophes in
+// words like "can't" are left alone.
+//nolint:gochecknoglobals
+var (
+	errorMessageURL    = regexp.MustCompile(`[a-zA-Z][a-zA-Z0-9+.-]*://[^\s"'<>]*[^\s"'<>:,.;)]`)
+	errorMessageString = regexp.MustCompile("(^|\\W)(?:'[^']*'|\"(?:[^\"\\\\]|\\\\.)*\"|`[^`]*`)")
+	errorMessageNumber = regexp.MustCompile(`\b(?:0x[0-9a-fA-F]+|\d+(?:\.\d+)?)`)
+)
+
+// IterationError is the error that aborted an iteration, classified by its type and message, so
+// that all occurrences of the same error can be counted together instead of logged one by one.
+type IterationError struct {
+	Err error
+
+	// The type of the error, e.g. "TypeError" for an exception thrown by a script, or "GoError"
+	// for an error that didn't come from a script.
+	Type string
+
+	// The message of the error, as returned by NormalizeErrorMessage.
+	Message string
+
+	// The group that the error was thrown in, if it's known.
+	Group *Group
+
+	// The VU and the iteration that the error aborted, if they're known, i.e. if VU isn't 0.
+	VU, Iteration int64
+}
+
+// NewIterationError classifies an error that doesn't come with more details than its message.
+// Errors that already are IterationErrors are returned as they are.
+func NewIterationError(err error) *IterationError {
+	if ierr, ok := err.(*IterationError); ok {
+		return ierr
+	}
+	return &IterationError{Err: err, Type: "GoError", Message: NormalizeErrorMessage(err.Error())}
+}
+
+func (e *IterationError) Error() string {
+	return e.Err.Error()
+}
+
+// String returns the underlying error with its stack trace, if it has one.
+func (e *IterationError) String() string {
+	if s, ok := e.Err.(fmt.Stringer); ok {
+		return s.String()
+	}
+	return e.Err.Error()
+}
+
+// Cause returns the underlying error, for errors.Cause().
+func (e *IterationError) Cause() error {
+	return e.Err
+}
+
+// NormalizeErrorMessage makes messages of the same error comparable: only the first line is kept,
+// which leaves out stack traces, URLs, quoted strings and numbers are replaced with placeholders,
+// whitespace is collapsed and the result is cut to a sane length.
+func NormalizeErrorMessage(msg string) string {
+	if i := strings.IndexByte(msg, '\n'); i >= 0 {
+		msg = msg[:i]
+	}
+	msg = errorMessageURL.ReplaceAllString(msg, "<url>")
+	msg = errorMessageString.ReplaceAllString(msg, "$1<string>")
+	msg = errorMessageNumber.ReplaceAllString(msg, "<number>")
+	msg = strings.Join(strings.Fields(msg), " ")
+	if utf8.RuneCountInString(msg) > MaxErrorMessageLength {
+		msg = string([]rune(msg)[:MaxErrorMessageLength-3]) + "..."
+	}
+	return msg
+}
+
+// Key returns what identifies all the occurrences of the same error.
+func (e *IterationError) Key() IterationErrorKey {
+	return IterationErrorKey{Type: e.Type, Message: e.Message}
+}
+
+// IterationErrorKey identifies the occurrences of the same error, by its type and its normalized
+// message.
+type IterationErrorKey struct {
+	Type, Message string
+}
+
+// IterationErrorCount is the number of iterations that were aborted by the same error.
+type IterationErrorCount struct {
+	Type    string `json:"type"`
+	Message string `json:"message"`
+	Count   int64  `json:"count"`
+}
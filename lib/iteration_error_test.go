@@ -0,0 +1,57 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package lib
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeErrorMessage(t *testing.T) {
+	testdata := map[string]string{
+		"":                            "",
+		"oops":                        "oops",
+		"  too   much\tspace ":        "too much space",
+		"oops\n\tat file:///x.js:1:1": "oops",
+		strings.Repeat("é", 300):      strings.Repeat("é", MaxErrorMessageLength-3) + "...",
+
+		"Get http://example.com/users/42?x=1: EOF":       "Get <url>: EOF",
+		`user "bob" not found, can't log in`:             "user <string> not found, can't log in",
+		"can't read property 'name' of undefined":        "can't read property <string> of undefined",
+		"got 3 items after 1.5s, expected 4 (code 0x1f)": "got <number> items after <number>s, expected <number> (code <number>)",
+		"failed at step 12 of test_2":                    "failed at step <number> of test_2",
+	}
+	for msg, expected := range testdata {
+		assert.Equal(t, expected, NormalizeErrorMessage(msg), msg)
+	}
+}
+
+func TestNewIterationError(t *testing.T) {
+	err := errors.New("oops\nthere")
+	ierr := NewIterationError(err)
+	assert.Equal(t, &IterationError{Err: err, Type: "GoError", Message: "oops"}, ierr)
+	assert.Equal(t, "oops\nthere", ierr.Error())
+	assert.Equal(t, err, errors.Cause(ierr))
+	assert.Equal(t, ierr, NewIterationError(ierr))
+}
@@ -0,0 +1,82 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+// Package logging contains log outputs and helpers for the logrus loggers used throughout k6.
+package logging
+
+import (
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// maxLimiterKeys bounds the number of distinct messages a Limiter keeps track of, so scripts that
+// log unique messages can't make it grow forever. It starts over once that's reached.
+const maxLimiterKeys = 10000
+
+// Limiter limits how often each distinct message can be logged, so that thousands of VUs logging
+// the same message in every iteration can't flood the log outputs and stall the test.
+type Limiter struct {
+	limit rate.Limit
+	burst int
+
+	mu      sync.Mutex
+	entries map[string]*limiterEntry
+}
+
+type limiterEntry struct {
+	limiter    *rate.Limiter
+	suppressed int64
+}
+
+// NewLimiter returns a Limiter that lets each message through at the given rate per second, with
+// bursts of up to the given size.
+func NewLimiter(perSecond float64, burst int) *Limiter {
+	return &Limiter{
+		limit:   rate.Limit(perSecond),
+		burst:   burst,
+		entries: make(map[string]*limiterEntry),
+	}
+}
+
+// Allow reports whether the message with the given key can be logged now. If it can, it also
+// returns how many times it was suppressed since the last time it was allowed.
+func (l *Limiter) Allow(key string) (bool, int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry, ok := l.entries[key]
+	if !ok {
+		if len(l.entries) >= maxLimiterKeys {
+			l.entries = make(map[string]*limiterEntry)
+		}
+		entry = &limiterEntry{limiter: rate.NewLimiter(l.limit, l.burst)}
+		l.entries[key] = entry
+	}
+
+	if !entry.limiter.AllowN(time.Now(), 1) {
+		entry.suppressed++
+		return false, 0
+	}
+	suppressed := entry.suppressed
+	entry.suppressed = 0
+	return true, suppressed
+}
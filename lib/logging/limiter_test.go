@@ -0,0 +1,63 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package logging
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+)
+
+func TestLimiter(t *testing.T) {
+	l := NewLimiter(0.001, 2)
+
+	for i := 0; i < 2; i++ {
+		ok, suppressed := l.Allow("a")
+		assert.True(t, ok)
+		assert.Equal(t, int64(0), suppressed)
+	}
+	for i := 0; i < 3; i++ {
+		ok, _ := l.Allow("a")
+		assert.False(t, ok)
+	}
+
+	ok, _ := l.Allow("b")
+	assert.True(t, ok, "messages are limited separately")
+
+	// Pretend that enough time has passed for another one
+	l.entries["a"].limiter = rate.NewLimiter(rate.Inf, 1)
+	ok, suppressed := l.Allow("a")
+	assert.True(t, ok)
+	assert.Equal(t, int64(3), suppressed)
+
+	t.Run("MaxKeys", func(t *testing.T) {
+		l := NewLimiter(1, 1)
+		for i := 0; i < maxLimiterKeys; i++ {
+			l.Allow(strconv.Itoa(i))
+		}
+		assert.Len(t, l.entries, maxLimiterKeys)
+		ok, _ := l.Allow("new")
+		assert.True(t, ok)
+		assert.Len(t, l.entries, 1)
+	})
+}
@@ -0,0 +1,230 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	// LokiPushPath is the path of the Loki push API, used when the URL doesn't have one.
+	LokiPushPath = "/loki/api/v1/push"
+
+	lokiBufferSize  = 10000
+	lokiBatchSize   = 1000
+	lokiFlushPeriod = 1 * time.Second
+)
+
+// LokiHook is a logrus hook that pushes the log entries to a Grafana Loki server, in batches and
+// from a separate goroutine. If the server can't keep up, entries are dropped instead of blocking
+// whatever logged them.
+type LokiHook struct {
+	url       string
+	labels    map[string]string
+	formatter log.Formatter
+	client    *http.Client
+
+	entries chan lokiEntry
+	stop    chan struct{}
+	done    chan struct{}
+	once    sync.Once
+
+	// The number of entries dropped because the queue was full, and of the ones that couldn't be
+	// pushed, along with the last error that happened while pushing them.
+	mu      sync.Mutex
+	dropped int64
+	failed  int64
+	lastErr error
+}
+
+type lokiEntry struct {
+	time  time.Time
+	level log.Level
+	line  string
+}
+
+// NewLokiHook returns a hook that pushes the entries, formatted with the given formatter, to the
+// Loki server at the given URL. All of them are labelled with app="k6" and their level.
+func NewLokiHook(addr string, formatter log.Formatter) (*LokiHook, error) {
+	u, err := url.Parse(addr)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, errors.Errorf("unsupported protocol scheme for Loki: '%s'", u.Scheme)
+	}
+	if u.Path == "" || u.Path == "/" {
+		u.Path = LokiPushPath
+	}
+
+	h := &LokiHook{
+		url:       u.String(),
+		labels:    map[string]string{"app": "k6"},
+		formatter: formatter,
+		client:    &http.Client{Timeout: 10 * time.Second},
+		entries:   make(chan lokiEntry, lokiBufferSize),
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	go h.loop()
+	return h, nil
+}
+
+// Levels returns all levels, filtering is left to the logger.
+func (h *LokiHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+// Fire queues the entry to be pushed, or drops it if the queue is full.
+func (h *LokiHook) Fire(entry *log.Entry) error {
+	line, err := h.formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+	select {
+	case h.entries <- lokiEntry{entry.Time, entry.Level, strings.TrimSuffix(string(line), "\n")}:
+	default:
+		h.mu.Lock()
+		h.dropped++
+		h.mu.Unlock()
+	}
+	return nil
+}
+
+// Close pushes the queued entries and stops the hook. It returns an error if any entries were
+// dropped or couldn't be pushed.
+func (h *LokiHook) Close() error {
+	h.once.Do(func() { close(h.stop) })
+	<-h.done
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	switch {
+	case h.failed > 0:
+		return errors.Wrapf(h.lastErr, "Loki: %d log entries couldn't be pushed", h.failed)
+	case h.dropped > 0:
+		return errors.Errorf("Loki: %d log entries were dropped, the server couldn't keep up", h.dropped)
+	}
+	return nil
+}
+
+func (h *LokiHook) loop() {
+	defer close(h.done)
+
+	ticker := time.NewTicker(lokiFlushPeriod)
+	defer ticker.Stop()
+
+	batch := make([]lokiEntry, 0, lokiBatchSize)
+	push := func() {
+		if len(batch) == 0 {
+			return
+		}
+		if err := h.push(batch); err != nil {
+			h.mu.Lock()
+			h.failed += int64(len(batch))
+			h.lastErr = err
+			h.mu.Unlock()
+		}
+		batch = batch[:0]
+	}
+
+	for {
+		select {
+		case entry := <-h.entries:
+			batch = append(batch, entry)
+			if len(batch) >= lokiBatchSize {
+				push()
+			}
+		case <-ticker.C:
+			push()
+		case <-h.stop:
+			for {
+				select {
+				case entry := <-h.entries:
+					batch = append(batch, entry)
+					if len(batch) >= lokiBatchSize {
+						push()
+					}
+				default:
+					push()
+					return
+				}
+			}
+		}
+	}
+}
+
+type lokiStream struct {
+	Stream map[string]string `json:"stream"`
+	Values [][2]string       `json:"values"`
+}
+
+func (h *LokiHook) push(batch []lokiEntry) error {
+	streams := make(map[log.Level]*lokiStream)
+	var order []log.Level
+	for _, entry := range batch {
+		stream, ok := streams[entry.level]
+		if !ok {
+			labels := make(map[string]string, len(h.labels)+1)
+			for k, v := range h.labels {
+				labels[k] = v
+			}
+			labels["level"] = entry.level.String()
+			stream = &lokiStream{Stream: labels}
+			streams[entry.level] = stream
+			order = append(order, entry.level)
+		}
+		stream.Values = append(stream.Values, [2]string{strconv.FormatInt(entry.time.UnixNano(), 10), entry.line})
+	}
+
+	var body struct {
+		Streams []*lokiStream `json:"streams"`
+	}
+	for _, level := range order {
+		body.Streams = append(body.Streams, streams[level])
+	}
+	data, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+
+	res, err := h.client.Post(h.url, "application/json", bytes.NewReader(data))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = res.Body.Close() }()
+	if res.StatusCode/100 != 2 {
+		msg, _ := ioutil.ReadAll(res.Body)
+		return errors.Errorf("%s: %s", res.Status, strings.TrimSpace(string(msg)))
+	}
+	return nil
+}
@@ -0,0 +1,99 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package logging
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLokiHook(t *testing.T) {
+	type push struct {
+		Streams []lokiStream `json:"streams"`
+	}
+	var mu sync.Mutex
+	var pushes []push
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, LokiPushPath, r.URL.Path)
+		var p push
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&p))
+		mu.Lock()
+		pushes = append(pushes, p)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	hook, err := NewLokiHook(srv.URL, &RawFormatter{})
+	require.NoError(t, err)
+
+	logger := log.New()
+	logger.Out = nopWriter{}
+	logger.Hooks.Add(hook)
+	logger.WithField("vu", 1).Info("hi")
+	logger.Warn("careful")
+	logger.Info("there")
+	require.NoError(t, hook.Close())
+
+	mu.Lock()
+	defer mu.Unlock()
+	require.Len(t, pushes, 1)
+	require.Len(t, pushes[0].Streams, 2)
+
+	info, warn := pushes[0].Streams[0], pushes[0].Streams[1]
+	assert.Equal(t, map[string]string{"app": "k6", "level": "info"}, info.Stream)
+	assert.Equal(t, map[string]string{"app": "k6", "level": "warning"}, warn.Stream)
+	require.Len(t, info.Values, 2)
+	assert.Equal(t, "hi", info.Values[0][1])
+	assert.Equal(t, "there", info.Values[1][1])
+	require.Len(t, warn.Values, 1)
+	assert.Equal(t, "careful", warn.Values[0][1])
+
+	t.Run("Error", func(t *testing.T) {
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			http.Error(w, "nope", http.StatusBadRequest)
+		}))
+		defer srv.Close()
+
+		hook, err := NewLokiHook(srv.URL+"/custom/push", &RawFormatter{})
+		require.NoError(t, err)
+		assert.Equal(t, srv.URL+"/custom/push", hook.url)
+		assert.NoError(t, hook.Fire(&log.Entry{Time: time.Now(), Level: log.InfoLevel, Message: "hi"}))
+		assert.EqualError(t, hook.Close(), "Loki: 1 log entries couldn't be pushed: 400 Bad Request: nope")
+	})
+
+	t.Run("InvalidURL", func(t *testing.T) {
+		_, err := NewLokiHook("localhost:3100", &RawFormatter{})
+		assert.Error(t, err)
+	})
+}
+
+type nopWriter struct{}
+
+func (nopWriter) Write(p []byte) (int, error) { return len(p), nil }
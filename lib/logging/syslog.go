@@ -0,0 +1,78 @@
+// +build !windows,!nacl,!plan9
+
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package logging
+
+import (
+	"log/syslog"
+	"strings"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// SyslogHook is a logrus hook that writes the log entries to the local syslog daemon, with the
+// syslog priority that corresponds to their level.
+type SyslogHook struct {
+	writer    *syslog.Writer
+	formatter log.Formatter
+}
+
+// NewSyslogHook connects to the local syslog daemon, to which the entries are written formatted
+// with the given formatter.
+func NewSyslogHook(formatter log.Formatter) (*SyslogHook, error) {
+	w, err := syslog.New(syslog.LOG_INFO|syslog.LOG_USER, "k6")
+	if err != nil {
+		return nil, err
+	}
+	return &SyslogHook{writer: w, formatter: formatter}, nil
+}
+
+// Levels returns all levels, filtering is left to the logger.
+func (h *SyslogHook) Levels() []log.Level {
+	return log.AllLevels
+}
+
+// Fire writes the entry to syslog.
+func (h *SyslogHook) Fire(entry *log.Entry) error {
+	data, err := h.formatter.Format(entry)
+	if err != nil {
+		return err
+	}
+	line := strings.TrimSuffix(string(data), "\n")
+	switch entry.Level {
+	case log.PanicLevel, log.FatalLevel:
+		return h.writer.Crit(line)
+	case log.ErrorLevel:
+		return h.writer.Err(line)
+	case log.WarnLevel:
+		return h.writer.Warning(line)
+	case log.InfoLevel:
+		return h.writer.Info(line)
+	default:
+		return h.writer.Debug(line)
+	}
+}
+
+// Close closes the connection to the syslog daemon.
+func (h *SyslogHook) Close() error {
+	return h.writer.Close()
+}
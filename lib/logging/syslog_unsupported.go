@@ -0,0 +1,51 @@
+// +build windows nacl plan9
+
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package logging
+
+import (
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// SyslogHook isn't supported on this platform, since Go's log/syslog isn't.
+type SyslogHook struct{}
+
+// NewSyslogHook always returns an error on this platform.
+func NewSyslogHook(formatter log.Formatter) (*SyslogHook, error) {
+	return nil, errors.New("syslog isn't supported on this platform")
+}
+
+// Levels returns no levels.
+func (h *SyslogHook) Levels() []log.Level {
+	return nil
+}
+
+// Fire does nothing.
+func (h *SyslogHook) Fire(entry *log.Entry) error {
+	return nil
+}
+
+// Close does nothing.
+func (h *SyslogHook) Close() error {
+	return nil
+}
@@ -33,6 +33,8 @@ var (
 	Iterations        = stats.New("iterations", stats.Counter)
 	IterationDuration = stats.New("iteration_duration", stats.Trend, stats.Time)
 	Errors            = stats.New("errors", stats.Counter)
+	VUInitDuration    = stats.New("vu_init_duration", stats.Trend, stats.Time)
+	VUInitMemory      = stats.New("vu_init_memory", stats.Gauge, stats.Data)
 
 	// Runner-emitted.
 	Checks        = stats.New("checks", stats.Rate)
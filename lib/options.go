@@ -337,6 +337,10 @@ type Options struct {
 
 	// Redirect console logging to a file
 	ConsoleOutput null.String `json:"-" envconfig:"console_output"`
+
+	// How many times per second each distinct console message can be logged, in bursts of up to
+	// ten times that; 0 disables the limit
+	ConsoleRateLimit null.Int `json:"consoleRateLimit" envconfig:"console_rate_limit"`
 }
 
 // Returns the result of overwriting any fields with any that are set on the argument.
@@ -480,6 +484,9 @@ func (o Options) Apply(opts Options) Options {
 	if opts.ConsoleOutput.Valid {
 		o.ConsoleOutput = opts.ConsoleOutput
 	}
+	if opts.ConsoleRateLimit.Valid {
+		o.ConsoleRateLimit = opts.ConsoleRateLimit
+	}
 
 	return o
 }
@@ -491,6 +498,17 @@ func (o Options) Validate() []error {
 	return o.Execution.Validate()
 }
 
+// SchedulerName returns the name of the scheduler that runs the iterations. Only a single one is
+// supported for now, so it's the configured one if there's exactly one, or the default otherwise.
+func (o Options) SchedulerName() string {
+	if len(o.Execution) == 1 {
+		for name := range o.Execution {
+			return name
+		}
+	}
+	return DefaultSchedulerName
+}
+
 // ForEachSpecified enumerates all struct fields and calls the supplied function with each
 // element that is valid. It panics for any unfamiliar or unexpected fields, so make sure
 // new fields in Options are accounted for.
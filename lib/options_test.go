@@ -401,6 +401,10 @@ func TestOptions(t *testing.T) {
 		assert.True(t, opts.DiscardResponseBodies.Valid)
 		assert.True(t, opts.DiscardResponseBodies.Bool)
 	})
+	t.Run("ConsoleRateLimit", func(t *testing.T) {
+		opts := Options{}.Apply(Options{ConsoleRateLimit: null.IntFrom(0)})
+		assert.Equal(t, null.IntFrom(0), opts.ConsoleRateLimit)
+	})
 
 }
 
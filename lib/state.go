@@ -49,6 +49,11 @@ type State struct {
 	// Current group; all emitted metrics are tagged with this.
 	Group *Group
 
+	// The last error that was thrown out of a group, and the innermost group it was thrown in, so
+	// an error that aborts the iteration can be attributed to the group it came from.
+	GroupError      error
+	GroupErrorGroup *Group
+
 	// Networking equipment.
 	Transport http.RoundTripper
 	Dialer    DialContexter
@@ -36,7 +36,9 @@ import (
 	"gopkg.in/guregu/null.v3"
 
 	"github.com/loadimpact/k6/lib"
+	"github.com/loadimpact/k6/lib/consts"
 	"github.com/loadimpact/k6/stats"
+	"github.com/loadimpact/k6/stats/output"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -97,6 +99,14 @@ func MergeFromExternal(external map[string]json.RawMessage, conf *Config) error
 	return nil
 }
 
+func init() {
+	output.Register("cloud", func(jsonRawConf json.RawMessage, arg string) (interface{}, error) {
+		return GetConsolidatedConfig(jsonRawConf, arg)
+	}, func(conf interface{}, params output.Params) (lib.Collector, error) {
+		return New(conf.(Config), params.Source, params.Options, consts.Version)
+	})
+}
+
 // New creates a new cloud collector
 func New(conf Config, src *loader.SourceData, opts lib.Options, version string) (*Collector, error) {
 	if err := MergeFromExternal(opts.External, &conf); err != nil {
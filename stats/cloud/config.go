@@ -21,8 +21,10 @@
 package cloud
 
 import (
+	"encoding/json"
 	"time"
 
+	"github.com/kelseyhightower/envconfig"
 	"github.com/loadimpact/k6/lib/types"
 	"gopkg.in/guregu/null.v3"
 )
@@ -223,3 +225,23 @@ func (c Config) Apply(cfg Config) Config {
 	}
 	return c
 }
+
+// GetConsolidatedConfig combines the default config values with the JSON config and the
+// environment variables. The --out argument, if any, is the name of the test run.
+func GetConsolidatedConfig(jsonRawConf json.RawMessage, arg string) (Config, error) {
+	result := NewConfig()
+	if jsonRawConf != nil {
+		jsonConf := Config{}
+		if err := json.Unmarshal(jsonRawConf, &jsonConf); err != nil {
+			return result, err
+		}
+		result = result.Apply(jsonConf)
+	}
+	if err := envconfig.Process("k6", &result); err != nil {
+		return result, err
+	}
+	if arg != "" {
+		result.Name = null.StringFrom(arg)
+	}
+	return result, nil
+}
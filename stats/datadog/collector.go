@@ -21,47 +21,54 @@
 package datadog
 
 import (
+	"encoding/json"
+
+	"github.com/kelseyhightower/envconfig"
 	"github.com/loadimpact/k6/lib"
+	"github.com/loadimpact/k6/stats/output"
 	"github.com/loadimpact/k6/stats/statsd/common"
 )
 
-type tagHandler lib.TagSet
-
-func (t tagHandler) processTags(tags map[string]string) []string {
-	var res []string
-
-	for key, value := range tags {
-		if value != "" && !t[key] {
-			res = append(res, key+":"+value)
-		}
-	}
-	return res
+func init() {
+	output.Register("datadog", func(jsonRawConf json.RawMessage, _ string) (interface{}, error) {
+		return GetConsolidatedConfig(jsonRawConf)
+	}, func(conf interface{}, _ output.Params) (lib.Collector, error) {
+		return New(conf.(Config))
+	})
 }
 
 // Config defines the datadog configuration
 type Config struct {
 	common.Config
-
-	TagBlacklist lib.TagSet `json:"tagBlacklist,omitempty" envconfig:"TAG_BLACKLIST"`
 }
 
 // Apply saves config non-zero config values from the passed config in the receiver.
 func (c Config) Apply(cfg Config) Config {
 	c.Config = c.Config.Apply(cfg.Config)
 
-	if cfg.TagBlacklist != nil {
-		c.TagBlacklist = cfg.TagBlacklist
-	}
-
 	return c
 }
 
 // NewConfig creates a new Config instance with default values for some fields.
 func NewConfig() Config {
 	return Config{
-		Config:       common.NewConfig(),
-		TagBlacklist: lib.GetTagSet(),
+		Config: common.NewConfig(),
+	}
+}
+
+// GetConsolidatedConfig combines the default config values with the JSON config and the
+// K6_DATADOG_* environment variables. The datadog output doesn't take a --out argument.
+func GetConsolidatedConfig(jsonRawConf json.RawMessage) (Config, error) {
+	result := NewConfig()
+	if jsonRawConf != nil {
+		jsonConf := Config{}
+		if err := json.Unmarshal(jsonRawConf, &jsonConf); err != nil {
+			return result, err
+		}
+		result = result.Apply(jsonConf)
 	}
+	err := envconfig.Process("k6_datadog", &result)
+	return result, err
 }
 
 // New creates a new statsd connector client
@@ -69,6 +76,7 @@ func New(conf Config) (*common.Collector, error) {
 	return &common.Collector{
 		Config:      conf.Config,
 		Type:        "datadog",
-		ProcessTags: tagHandler(conf.TagBlacklist).processTags,
+		ProcessTags: conf.ProcessTags,
+		DogStatsD:   true,
 	}, nil
 }
@@ -13,11 +13,11 @@ import (
 
 func TestCollector(t *testing.T) {
 	var tagSet = lib.GetTagSet("tag1", "tag2")
-	var handler = tagHandler(tagSet)
+	var handler = common.Config{TagBlacklist: tagSet}
 	testutil.BaseTest(t, func(config common.Config) (*common.Collector, error) {
+		config.TagBlacklist = tagSet
 		return New(NewConfig().Apply(Config{
-			TagBlacklist: tagSet,
-			Config:       config,
+			Config: config,
 		}))
 	}, func(t *testing.T, containers []stats.SampleContainer, expectedOutput, output string) {
 		var outputLines = strings.Split(output, "\n")
@@ -25,7 +25,7 @@ func TestCollector(t *testing.T) {
 		for i, container := range containers {
 			for j, sample := range container.GetSamples() {
 				var (
-					expectedTagList    = handler.processTags(sample.GetTags().CloneTags())
+					expectedTagList    = handler.ProcessTags(sample.GetTags().CloneTags())
 					expectedOutputLine = expectedOutputLines[i*j+i]
 					outputLine         = outputLines[i*j+i]
 					outputWithoutTags  = outputLine
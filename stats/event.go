@@ -0,0 +1,50 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package stats
+
+import (
+	"time"
+)
+
+// Event is a timestamped annotation of a test run, e.g. marking a deployment or a fault that
+// was injected in the system under test. It's a ConnectedSampleContainer without any samples,
+// so it goes through the same pipeline as the samples do, and collectors that don't support
+// events simply ignore it.
+type Event struct {
+	Time    time.Time   `json:"time"`
+	Message string      `json:"message"`
+	Tags    *SampleTags `json:"tags"`
+}
+
+// GetSamples implements the SampleContainer interface, an event doesn't have any samples.
+func (e Event) GetSamples() []Sample {
+	return nil
+}
+
+// GetTags implements the ConnectedSampleContainer interface and returns the event's tags.
+func (e Event) GetTags() *SampleTags {
+	return e.Tags
+}
+
+// GetTime implements the ConnectedSampleContainer interface and returns the event's time.
+func (e Event) GetTime() time.Time {
+	return e.Time
+}
@@ -0,0 +1,328 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+// Package http implements a generic output that pushes batches of samples to an HTTP endpoint.
+package http
+
+import (
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"text/template"
+	"time"
+
+	"github.com/loadimpact/k6/lib"
+	"github.com/loadimpact/k6/stats"
+	"github.com/loadimpact/k6/stats/output"
+	"github.com/pkg/errors"
+	log "github.com/sirupsen/logrus"
+)
+
+// Sample is the JSON representation of a single sample or, in the aggregate mode, of all samples of
+// a metric with the same tags over one push interval. Values holds the aggregated values, in the
+// same format the thresholds use, while Value is the most relevant of them for the metric type.
+type Sample struct {
+	Metric string             `json:"metric"`
+	Type   stats.MetricType   `json:"type"`
+	Time   time.Time          `json:"time"`
+	Value  float64            `json:"value"`
+	Values map[string]float64 `json:"values,omitempty"`
+	Tags   map[string]string  `json:"tags,omitempty"`
+}
+
+// mainValues are the aggregated values used as the Value of an aggregated Sample.
+var mainValues = map[stats.MetricType]string{
+	stats.Counter: "count",
+	stats.Gauge:   "value",
+	stats.Trend:   "avg",
+	stats.Rate:    "rate",
+}
+
+// Collector pushes the collected samples to an HTTP endpoint on every push interval.
+type Collector struct {
+	Config Config
+	client *http.Client
+	tmpl   *template.Template
+
+	buffer     []stats.Sample
+	bufferLock sync.Mutex
+}
+
+var _ lib.Collector = &Collector{}
+
+func init() {
+	output.Register("http", func(jsonRawConf json.RawMessage, arg string) (interface{}, error) {
+		return GetConsolidatedConfig(jsonRawConf, arg)
+	}, func(conf interface{}, _ output.Params) (lib.Collector, error) {
+		return New(conf.(Config))
+	})
+}
+
+// New creates a new HTTP push collector.
+func New(conf Config) (*Collector, error) {
+	if conf.URL.String == "" {
+		return nil, errors.New("the http output needs a URL to send the samples to")
+	}
+	if conf.PushInterval.Duration <= 0 {
+		return nil, errors.Errorf("the push interval of the http output must be positive, not %s", conf.PushInterval.Duration)
+	}
+	if conf.Retries.Int64 < 0 {
+		return nil, errors.Errorf("the number of retries of the http output can't be negative, not %d", conf.Retries.Int64)
+	}
+	if conf.RetryInterval.Duration < 0 {
+		return nil, errors.Errorf("the retry interval of the http output can't be negative, not %s", conf.RetryInterval.Duration)
+	}
+	return &Collector{
+		Config: conf,
+		client: &http.Client{Timeout: 60 * time.Second},
+	}, nil
+}
+
+// Init validates the format and parses the payload template, if any.
+func (c *Collector) Init() (err error) {
+	switch c.Config.Format.String {
+	case FormatJSON, FormatNDJSON:
+	case FormatTemplate:
+		c.tmpl, err = c.Config.template()
+	default:
+		err = errors.Errorf("invalid format for the http output: %s", c.Config.Format.String)
+	}
+	return err
+}
+
+// Run pushes the buffered samples on every push interval, and once more when the context is done.
+func (c *Collector) Run(ctx context.Context) {
+	ticker := time.NewTicker(time.Duration(c.Config.PushInterval.Duration))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+			c.push(ctx)
+		case <-ctx.Done():
+			c.push(ctx)
+			return
+		}
+	}
+}
+
+// Collect buffers the samples until the next push.
+func (c *Collector) Collect(scs []stats.SampleContainer) {
+	c.bufferLock.Lock()
+	defer c.bufferLock.Unlock()
+	for _, sc := range scs {
+		c.buffer = append(c.buffer, sc.GetSamples()...)
+	}
+}
+
+// Link returns the URL the samples are pushed to.
+func (c *Collector) Link() string {
+	return c.Config.URL.String
+}
+
+// GetRequiredSystemTags returns which sample tags are needed by this collector
+func (c *Collector) GetRequiredSystemTags() lib.TagSet {
+	return lib.TagSet{} // There are no required tags for this collector
+}
+
+// SetRunStatus does nothing in the http collector
+func (c *Collector) SetRunStatus(status lib.RunStatus) {}
+
+// push sends the buffered samples. Once the context is done, they're not retried anymore.
+func (c *Collector) push(ctx context.Context) {
+	c.bufferLock.Lock()
+	buffer := c.buffer
+	c.buffer = nil
+	c.bufferLock.Unlock()
+
+	if len(buffer) == 0 {
+		return
+	}
+
+	var samples []Sample
+	if c.Config.Aggregate.Bool {
+		samples = aggregate(buffer, time.Now(), time.Duration(c.Config.PushInterval.Duration))
+	} else {
+		samples = make([]Sample, len(buffer))
+		for i, s := range buffer {
+			samples[i] = Sample{
+				Metric: s.Metric.Name,
+				Type:   s.Metric.Type,
+				Time:   s.Time,
+				Value:  s.Value,
+				Tags:   s.Tags.CloneTags(),
+			}
+		}
+	}
+
+	startTime := time.Now()
+	body, err := c.encode(samples)
+	if err != nil {
+		log.WithError(err).Error("HTTP output: Couldn't encode the samples")
+		return
+	}
+	if err := c.send(ctx, body); err != nil {
+		log.WithError(err).Error("HTTP output: Couldn't send the samples")
+		return
+	}
+	log.WithFields(log.Fields{"samples": len(samples), "t": time.Since(startTime)}).Debug("HTTP output: Pushed!")
+}
+
+// aggregate folds the samples into one Sample per metric and tag set, sorted by metric name.
+func aggregate(buffer []stats.Sample, t time.Time, interval time.Duration) []Sample {
+	type entry struct {
+		sample Sample
+		sink   stats.Sink
+	}
+	entries := make(map[string]*entry)
+	var keys []string
+	for _, s := range buffer {
+		tags := s.Tags.CloneTags()
+		tagsJSON, _ := json.Marshal(tags) // map keys are sorted, so this is a stable key
+		key := s.Metric.Name + string(tagsJSON)
+
+		e, ok := entries[key]
+		if !ok {
+			e = &entry{
+				sample: Sample{Metric: s.Metric.Name, Type: s.Metric.Type, Time: t, Tags: tags},
+				sink:   stats.New(s.Metric.Name, s.Metric.Type).Sink,
+			}
+			entries[key] = e
+			keys = append(keys, key)
+		}
+		e.sink.Add(s)
+	}
+
+	sort.Strings(keys)
+	samples := make([]Sample, len(keys))
+	for i, key := range keys {
+		e := entries[key]
+		e.sample.Values = e.sink.Format(interval)
+		e.sample.Value = e.sample.Values[mainValues[e.sample.Type]]
+		samples[i] = e.sample
+	}
+	return samples
+}
+
+func (c *Collector) encode(samples []Sample) ([]byte, error) {
+	var buf bytes.Buffer
+	switch c.Config.Format.String {
+	case FormatNDJSON:
+		enc := json.NewEncoder(&buf)
+		for _, s := range samples {
+			if err := enc.Encode(s); err != nil {
+				return nil, err
+			}
+		}
+	case FormatTemplate:
+		if err := c.tmpl.Execute(&buf, samples); err != nil {
+			return nil, err
+		}
+	default:
+		if err := json.NewEncoder(&buf).Encode(samples); err != nil {
+			return nil, err
+		}
+	}
+	return buf.Bytes(), nil
+}
+
+func (c *Collector) contentType() string {
+	switch c.Config.Format.String {
+	case FormatNDJSON:
+		return "application/x-ndjson"
+	case FormatTemplate:
+		return "text/plain; charset=utf-8"
+	default:
+		return "application/json"
+	}
+}
+
+// send POSTs the body, retrying on network errors and 5xx or 429 responses until the context is
+// done. The interval between the attempts doubles after every one of them.
+func (c *Collector) send(ctx context.Context, body []byte) error {
+	contentEncoding := ""
+	if c.Config.Gzip.Bool {
+		var buf bytes.Buffer
+		g := gzip.NewWriter(&buf)
+		if _, err := g.Write(body); err != nil {
+			return err
+		}
+		if err := g.Close(); err != nil {
+			return err
+		}
+		body = buf.Bytes()
+		contentEncoding = "gzip"
+	}
+
+	interval := time.Duration(c.Config.RetryInterval.Duration)
+	var err error
+	for i := int64(0); i <= c.Config.Retries.Int64; i++ {
+		if i > 0 {
+			log.WithError(err).WithField("attempt", i).Debug("HTTP output: Retrying...")
+			select {
+			case <-ctx.Done():
+				return err
+			case <-time.After(interval):
+			}
+			interval *= 2
+		}
+
+		var retry bool
+		if retry, err = c.post(body, contentEncoding); !retry {
+			return err
+		}
+	}
+	return err
+}
+
+func (c *Collector) post(body []byte, contentEncoding string) (retry bool, err error) {
+	req, err := http.NewRequest("POST", c.Config.URL.String, bytes.NewReader(body))
+	if err != nil {
+		return false, err
+	}
+	req.Header.Set("User-Agent", "k6")
+	req.Header.Set("Content-Type", c.contentType())
+	if contentEncoding != "" {
+		req.Header.Set("Content-Encoding", contentEncoding)
+	}
+	for k, v := range c.Config.Headers {
+		req.Header.Set(k, v)
+	}
+
+	res, err := c.client.Do(req)
+	if err != nil {
+		return true, err
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	if res.StatusCode >= 200 && res.StatusCode < 300 {
+		_, _ = io.Copy(ioutil.Discard, res.Body)
+		return false, nil
+	}
+	data, _ := ioutil.ReadAll(io.LimitReader(res.Body, 1024))
+	err = errors.Errorf("%s: %s", res.Status, strings.TrimSpace(string(data)))
+	return res.StatusCode >= 500 || res.StatusCode == http.StatusTooManyRequests, err
+}
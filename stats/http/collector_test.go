@@ -0,0 +1,210 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package http
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/loadimpact/k6/lib/types"
+	"github.com/loadimpact/k6/stats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	null "gopkg.in/guregu/null.v3"
+)
+
+func newTestCollector(t *testing.T, url string, conf Config) *Collector {
+	conf = NewConfig().Apply(Config{
+		URL:           null.StringFrom(url),
+		RetryInterval: types.NullDurationFrom(time.Millisecond),
+	}).Apply(conf)
+	c, err := New(conf)
+	require.NoError(t, err)
+	require.NoError(t, c.Init())
+	return c
+}
+
+func testSamples() []stats.SampleContainer {
+	now := time.Date(2019, 1, 2, 3, 4, 5, 0, time.UTC)
+	trend := stats.New("http_req_duration", stats.Trend, stats.Time)
+	counter := stats.New("http_reqs", stats.Counter)
+	tags := stats.IntoSampleTags(&map[string]string{"status": "200"})
+	return []stats.SampleContainer{
+		stats.Sample{Metric: trend, Time: now, Value: 10, Tags: tags},
+		stats.Sample{Metric: trend, Time: now, Value: 30, Tags: tags},
+		stats.Sample{Metric: counter, Time: now, Value: 1, Tags: tags},
+	}
+}
+
+func TestCollectorRaw(t *testing.T) {
+	var received []Sample
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.Equal(t, "Bearer abc", r.Header.Get("Authorization"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+	}))
+	defer srv.Close()
+
+	c := newTestCollector(t, srv.URL, Config{Headers: map[string]string{"Authorization": "Bearer abc"}})
+	c.Collect(testSamples())
+	c.push(context.Background())
+
+	require.Len(t, received, 3)
+	assert.Equal(t, "http_req_duration", received[0].Metric)
+	assert.Equal(t, stats.Trend, received[0].Type)
+	assert.Equal(t, 10.0, received[0].Value)
+	assert.Equal(t, map[string]string{"status": "200"}, received[0].Tags)
+	assert.Nil(t, received[0].Values)
+	assert.Empty(t, c.buffer)
+}
+
+func TestCollectorAggregateNDJSONGzip(t *testing.T) {
+	var received []Sample
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/x-ndjson", r.Header.Get("Content-Type"))
+		assert.Equal(t, "gzip", r.Header.Get("Content-Encoding"))
+		body, err := gzip.NewReader(r.Body)
+		require.NoError(t, err)
+		dec := json.NewDecoder(body)
+		for dec.More() {
+			var s Sample
+			assert.NoError(t, dec.Decode(&s))
+			received = append(received, s)
+		}
+	}))
+	defer srv.Close()
+
+	c := newTestCollector(t, srv.URL, Config{
+		Format:    null.StringFrom(FormatNDJSON),
+		Gzip:      null.BoolFrom(true),
+		Aggregate: null.BoolFrom(true),
+	})
+	c.Collect(testSamples())
+	c.push(context.Background())
+
+	require.Len(t, received, 2)
+	assert.Equal(t, "http_req_duration", received[0].Metric)
+	assert.Equal(t, 20.0, received[0].Value)
+	assert.Equal(t, 30.0, received[0].Values["max"])
+	assert.Equal(t, "http_reqs", received[1].Metric)
+	assert.Equal(t, 1.0, received[1].Value)
+	assert.Equal(t, 1.0, received[1].Values["rate"])
+}
+
+func TestCollectorTemplate(t *testing.T) {
+	var received string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		received = string(body)
+	}))
+	defer srv.Close()
+
+	c := newTestCollector(t, srv.URL, Config{
+		Format:   null.StringFrom(FormatTemplate),
+		Template: null.StringFrom("{{range .}}{{.Metric}} {{.Value}} {{.Time.Unix}}\n{{end}}"),
+	})
+	c.Collect(testSamples())
+	c.push(context.Background())
+
+	assert.Equal(t, "http_req_duration 10 1546398245\nhttp_req_duration 30 1546398245\nhttp_reqs 1 1546398245\n", received)
+
+	c.Config.Template = null.String{}
+	assert.EqualError(t, c.Init(), "the template format requires a template or a template file")
+	c.Config.Format = null.StringFrom("xml")
+	assert.EqualError(t, c.Init(), "invalid format for the http output: xml")
+}
+
+func TestCollectorRetries(t *testing.T) {
+	testdata := map[string]struct {
+		status   int
+		attempts int32
+	}{
+		"ok":          {http.StatusOK, 1},
+		"bad request": {http.StatusBadRequest, 1},
+		"rate limit":  {http.StatusTooManyRequests, 3},
+		"error":       {http.StatusBadGateway, 3},
+	}
+	for name, data := range testdata {
+		data := data
+		t.Run(name, func(t *testing.T) {
+			var attempts int32
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				atomic.AddInt32(&attempts, 1)
+				w.WriteHeader(data.status)
+			}))
+			defer srv.Close()
+
+			c := newTestCollector(t, srv.URL, Config{Retries: null.IntFrom(2)})
+			err := c.send(context.Background(), []byte("[]"))
+			assert.Equal(t, data.attempts, atomic.LoadInt32(&attempts))
+			if data.status == http.StatusOK {
+				assert.NoError(t, err)
+			} else {
+				assert.Error(t, err)
+			}
+		})
+	}
+}
+
+func TestNewWithoutURL(t *testing.T) {
+	_, err := New(NewConfig())
+	assert.EqualError(t, err, "the http output needs a URL to send the samples to")
+}
+
+func TestNewInvalidConfig(t *testing.T) {
+	testdata := map[string]Config{
+		"zero push interval":      {PushInterval: types.NullDurationFrom(0)},
+		"negative push interval":  {PushInterval: types.NullDurationFrom(-time.Second)},
+		"negative retries":        {Retries: null.IntFrom(-1)},
+		"negative retry interval": {RetryInterval: types.NullDurationFrom(-time.Second)},
+	}
+	for name, conf := range testdata {
+		conf := NewConfig().Apply(Config{URL: null.StringFrom("http://example.com")}).Apply(conf)
+		_, err := New(conf)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestSendStopsRetryingWhenDone(t *testing.T) {
+	var attempts int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	c := newTestCollector(t, srv.URL, Config{
+		Retries:       null.IntFrom(5),
+		RetryInterval: types.NullDurationFrom(time.Hour),
+	})
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.Error(t, c.send(ctx, []byte("[]")))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&attempts))
+}
@@ -0,0 +1,197 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package http
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/url"
+	"strconv"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/loadimpact/k6/lib/types"
+	"github.com/pkg/errors"
+	null "gopkg.in/guregu/null.v3"
+)
+
+// The supported payload formats.
+const (
+	FormatJSON     = "json"
+	FormatNDJSON   = "ndjson"
+	FormatTemplate = "template"
+)
+
+// Config is the config for the HTTP push collector
+type Config struct {
+	// Connection.
+	URL     null.String       `json:"url" envconfig:"HTTP_URL"`
+	Headers map[string]string `json:"headers,omitempty" envconfig:"HTTP_HEADERS"`
+	Gzip    null.Bool         `json:"gzip,omitempty" envconfig:"HTTP_GZIP"`
+
+	// Retries; the interval doubles after each failed attempt.
+	Retries       null.Int           `json:"retries,omitempty" envconfig:"HTTP_RETRIES"`
+	RetryInterval types.NullDuration `json:"retryInterval,omitempty" envconfig:"HTTP_RETRY_INTERVAL"`
+
+	// Payload.
+	Format       null.String        `json:"format,omitempty" envconfig:"HTTP_FORMAT"`
+	Template     null.String        `json:"template,omitempty" envconfig:"HTTP_TEMPLATE"`
+	TemplateFile null.String        `json:"templateFile,omitempty" envconfig:"HTTP_TEMPLATE_FILE"`
+	Aggregate    null.Bool          `json:"aggregate,omitempty" envconfig:"HTTP_AGGREGATE"`
+	PushInterval types.NullDuration `json:"pushInterval,omitempty" envconfig:"HTTP_PUSH_INTERVAL"`
+}
+
+// NewConfig creates a new Config instance with default values for some fields.
+func NewConfig() Config {
+	return Config{
+		Format:        null.NewString(FormatJSON, false),
+		Retries:       null.NewInt(3, false),
+		RetryInterval: types.NewNullDuration(1*time.Second, false),
+		PushInterval:  types.NewNullDuration(1*time.Second, false),
+	}
+}
+
+// Apply saves config non-zero config values from the passed config in the receiver.
+func (c Config) Apply(cfg Config) Config {
+	if cfg.URL.Valid {
+		c.URL = cfg.URL
+	}
+	if cfg.Headers != nil {
+		c.Headers = cfg.Headers
+	}
+	if cfg.Gzip.Valid {
+		c.Gzip = cfg.Gzip
+	}
+	if cfg.Retries.Valid {
+		c.Retries = cfg.Retries
+	}
+	if cfg.RetryInterval.Valid {
+		c.RetryInterval = cfg.RetryInterval
+	}
+	if cfg.Format.Valid {
+		c.Format = cfg.Format
+	}
+	if cfg.Template.Valid {
+		c.Template = cfg.Template
+	}
+	if cfg.TemplateFile.Valid {
+		c.TemplateFile = cfg.TemplateFile
+	}
+	if cfg.Aggregate.Valid {
+		c.Aggregate = cfg.Aggregate
+	}
+	if cfg.PushInterval.Valid {
+		c.PushInterval = cfg.PushInterval
+	}
+	return c
+}
+
+// ParseURL parses the --out argument, which is the URL that the samples are sent to. A few of the
+// options can also be specified as query parameters prefixed with "k6_", which are removed from
+// the URL, e.g. http://example.com/metrics?k6_format=ndjson&k6_gzip=true
+func ParseURL(text string) (Config, error) {
+	c := Config{}
+	u, err := url.Parse(text)
+	if err != nil {
+		return c, err
+	}
+
+	query := u.Query()
+	for k, vs := range query {
+		if !strings.HasPrefix(k, "k6_") {
+			continue
+		}
+		delete(query, k)
+
+		switch v := vs[0]; k {
+		case "k6_format":
+			c.Format = null.StringFrom(v)
+		case "k6_template_file":
+			c.TemplateFile = null.StringFrom(v)
+		case "k6_gzip", "k6_aggregate":
+			b, err := strconv.ParseBool(v)
+			if err != nil {
+				return c, errors.Errorf("%s must be true or false, not %s", k, v)
+			}
+			if k == "k6_gzip" {
+				c.Gzip = null.BoolFrom(b)
+			} else {
+				c.Aggregate = null.BoolFrom(b)
+			}
+		case "k6_retries":
+			n, err := strconv.ParseInt(v, 10, 64)
+			if err != nil {
+				return c, err
+			}
+			c.Retries = null.IntFrom(n)
+		case "k6_push_interval":
+			if err := c.PushInterval.UnmarshalText([]byte(v)); err != nil {
+				return c, err
+			}
+		default:
+			return c, errors.Errorf("unknown query parameter: %s", k)
+		}
+	}
+	u.RawQuery = query.Encode()
+	if text != "" {
+		c.URL = null.StringFrom(u.String())
+	}
+	return c, nil
+}
+
+// GetConsolidatedConfig combines the default config values with the JSON config, the environment
+// variables and the --out argument, in that order of precedence.
+func GetConsolidatedConfig(jsonRawConf json.RawMessage, arg string) (Config, error) {
+	result := NewConfig()
+	if jsonRawConf != nil {
+		jsonConf := Config{}
+		if err := json.Unmarshal(jsonRawConf, &jsonConf); err != nil {
+			return result, err
+		}
+		result = result.Apply(jsonConf)
+	}
+	if err := envconfig.Process("k6", &result); err != nil {
+		return result, err
+	}
+	urlConf, err := ParseURL(arg)
+	if err != nil {
+		return result, err
+	}
+	return result.Apply(urlConf), nil
+}
+
+// template returns the parsed payload template, if the template format is used.
+func (c Config) template() (*template.Template, error) {
+	text := c.Template.String
+	if c.TemplateFile.String != "" {
+		data, err := ioutil.ReadFile(c.TemplateFile.String)
+		if err != nil {
+			return nil, err
+		}
+		text = string(data)
+	}
+	if text == "" {
+		return nil, errors.New("the template format requires a template or a template file")
+	}
+	return template.New("payload").Parse(text)
+}
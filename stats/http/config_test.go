@@ -0,0 +1,76 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package http
+
+import (
+	"testing"
+	"time"
+
+	"github.com/loadimpact/k6/lib/types"
+	"github.com/stretchr/testify/assert"
+	null "gopkg.in/guregu/null.v3"
+)
+
+func TestParseURL(t *testing.T) {
+	testdata := map[string]Config{
+		"": {},
+		"http://example.com/metrics": {
+			URL: null.StringFrom("http://example.com/metrics"),
+		},
+		"https://example.com/metrics?token=abc&k6_format=ndjson&k6_gzip=true": {
+			URL:    null.StringFrom("https://example.com/metrics?token=abc"),
+			Format: null.StringFrom(FormatNDJSON),
+			Gzip:   null.BoolFrom(true),
+		},
+		"http://example.com?k6_aggregate=1&k6_retries=5&k6_push_interval=10s&k6_template_file=t.tmpl": {
+			URL:          null.StringFrom("http://example.com"),
+			Aggregate:    null.BoolFrom(true),
+			Retries:      null.IntFrom(5),
+			PushInterval: types.NullDurationFrom(10 * time.Second),
+			TemplateFile: null.StringFrom("t.tmpl"),
+		},
+	}
+	for str, expected := range testdata {
+		t.Run(str, func(t *testing.T) {
+			config, err := ParseURL(str)
+			assert.NoError(t, err)
+			assert.Equal(t, expected, config)
+		})
+	}
+
+	_, err := ParseURL("http://example.com?k6_gzip=maybe")
+	assert.EqualError(t, err, "k6_gzip must be true or false, not maybe")
+	_, err = ParseURL("http://example.com?k6_unknown=1")
+	assert.EqualError(t, err, "unknown query parameter: k6_unknown")
+}
+
+func TestConfigApply(t *testing.T) {
+	conf := NewConfig().Apply(Config{
+		URL:     null.StringFrom("http://example.com"),
+		Headers: map[string]string{"Authorization": "Bearer abc"},
+		Format:  null.StringFrom(FormatTemplate),
+	})
+	assert.Equal(t, null.StringFrom("http://example.com"), conf.URL)
+	assert.Equal(t, map[string]string{"Authorization": "Bearer abc"}, conf.Headers)
+	assert.Equal(t, null.StringFrom(FormatTemplate), conf.Format)
+	assert.Equal(t, null.NewInt(3, false), conf.Retries)
+	assert.Equal(t, types.NewNullDuration(1*time.Second, false), conf.PushInterval)
+}
@@ -22,17 +22,23 @@ package influxdb
 
 import (
 	"context"
+	"encoding/json"
 	"sync"
 	"time"
 
 	"github.com/influxdata/influxdb/client/v2"
 	"github.com/loadimpact/k6/lib"
 	"github.com/loadimpact/k6/stats"
+	"github.com/loadimpact/k6/stats/output"
 	log "github.com/sirupsen/logrus"
 )
 
 const (
 	pushInterval = 1 * time.Second
+
+	// EventsMeasurement is the measurement events are written to, with their message in the
+	// "text" field, so they can be used as Grafana annotations.
+	EventsMeasurement = "events"
 )
 
 // Verify that Collector implements lib.Collector
@@ -44,9 +50,18 @@ type Collector struct {
 	BatchConf client.BatchPointsConfig
 
 	buffer     []stats.Sample
+	events     []stats.Event
 	bufferLock sync.Mutex
 }
 
+func init() {
+	output.Register("influxdb", func(jsonRawConf json.RawMessage, arg string) (interface{}, error) {
+		return GetConsolidatedConfig(jsonRawConf, arg)
+	}, func(conf interface{}, _ output.Params) (lib.Collector, error) {
+		return New(conf.(Config))
+	})
+}
+
 func New(conf Config) (*Collector, error) {
 	cl, err := MakeClient(conf)
 	if err != nil {
@@ -61,6 +76,11 @@ func New(conf Config) (*Collector, error) {
 }
 
 func (c *Collector) Init() error {
+	// Buckets can't be created through the v2 write API, they have to exist beforehand.
+	if c.Config.IsV2() {
+		return nil
+	}
+
 	// Try to create the database if it doesn't exist. Failure to do so is USUALLY harmless; it
 	// usually means we're either a non-admin user to an existing DB or connecting over UDP.
 	_, err := c.Client.Query(client.NewQuery("CREATE DATABASE "+c.BatchConf.Database, "", ""))
@@ -89,6 +109,10 @@ func (c *Collector) Collect(scs []stats.SampleContainer) {
 	c.bufferLock.Lock()
 	defer c.bufferLock.Unlock()
 	for _, sc := range scs {
+		if event, ok := sc.(stats.Event); ok {
+			c.events = append(c.events, event)
+			continue
+		}
 		c.buffer = append(c.buffer, sc.GetSamples()...)
 	}
 }
@@ -99,8 +123,8 @@ func (c *Collector) Link() string {
 
 func (c *Collector) commit() {
 	c.bufferLock.Lock()
-	samples := c.buffer
-	c.buffer = nil
+	samples, events := c.buffer, c.events
+	c.buffer, c.events = nil, nil
 	c.bufferLock.Unlock()
 
 	log.Debug("InfluxDB: Committing...")
@@ -109,6 +133,19 @@ func (c *Collector) commit() {
 	if err != nil {
 		return
 	}
+	for _, event := range events {
+		p, err := client.NewPoint(
+			EventsMeasurement,
+			event.Tags.CloneTags(),
+			map[string]interface{}{"text": event.Message},
+			event.Time,
+		)
+		if err != nil {
+			log.WithError(err).Error("InfluxDB: Couldn't make point from event!")
+			continue
+		}
+		batch.AddPoint(p)
+	}
 
 	log.WithField("points", len(batch.Points())).Debug("InfluxDB: Writing...")
 	startTime := time.Now()
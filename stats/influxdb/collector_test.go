@@ -0,0 +1,61 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package influxdb
+
+import (
+	"testing"
+	"time"
+
+	client "github.com/influxdata/influxdb/client/v2"
+	"github.com/loadimpact/k6/stats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testClient records the batches written to it.
+type testClient struct {
+	client.Client
+	batches []client.BatchPoints
+}
+
+func (c *testClient) Write(bp client.BatchPoints) error {
+	c.batches = append(c.batches, bp)
+	return nil
+}
+
+func TestCollectorEvents(t *testing.T) {
+	cl := &testClient{}
+	c := &Collector{Client: cl, Config: *NewConfig()}
+
+	now := time.Unix(1550000000, 0)
+	metric := stats.New("my_metric", stats.Counter)
+	c.Collect([]stats.SampleContainer{
+		stats.Sample{Metric: metric, Time: now, Value: 1},
+		stats.Event{Time: now, Message: "deployed v2", Tags: stats.IntoSampleTags(&map[string]string{"service": "api"})},
+	})
+	c.commit()
+
+	require.Len(t, cl.batches, 1)
+	points := cl.batches[0].Points()
+	require.Len(t, points, 2)
+	assert.Equal(t, "my_metric value=1 1550000000000000000", points[0].String())
+	assert.Equal(t, `events,service=api text="deployed v2" 1550000000000000000`, points[1].String())
+}
@@ -21,10 +21,12 @@
 package influxdb
 
 import (
+	"encoding/json"
 	"net/url"
 	"strconv"
 	"strings"
 
+	"github.com/kelseyhightower/envconfig"
 	"github.com/kubernetes/helm/pkg/strvals"
 	"github.com/loadimpact/k6/lib/types"
 	"github.com/mitchellh/mapstructure"
@@ -40,6 +42,11 @@ type Config struct {
 	Insecure    null.Bool   `json:"insecure,omitempty" envconfig:"INFLUXDB_INSECURE"`
 	PayloadSize null.Int    `json:"payloadSize,omitempty" envconfig:"INFLUXDB_PAYLOAD_SIZE"`
 
+	// InfluxDB 2.x; setting an organization switches to the /api/v2/write endpoint.
+	Organization null.String `json:"organization,omitempty" envconfig:"INFLUXDB_ORGANIZATION"`
+	Bucket       null.String `json:"bucket,omitempty" envconfig:"INFLUXDB_BUCKET"`
+	Token        null.String `json:"token,omitempty" envconfig:"INFLUXDB_TOKEN"`
+
 	// Samples.
 	DB           null.String `json:"db" envconfig:"INFLUXDB_DB"`
 	Precision    null.String `json:"precision,omitempty" envconfig:"INFLUXDB_PRECISION"`
@@ -73,6 +80,15 @@ func (c Config) Apply(cfg Config) Config {
 	if cfg.PayloadSize.Valid && cfg.PayloadSize.Int64 > 0 {
 		c.PayloadSize = cfg.PayloadSize
 	}
+	if cfg.Organization.Valid {
+		c.Organization = cfg.Organization
+	}
+	if cfg.Bucket.Valid {
+		c.Bucket = cfg.Bucket
+	}
+	if cfg.Token.Valid {
+		c.Token = cfg.Token
+	}
 	if cfg.DB.Valid {
 		c.DB = cfg.DB
 	}
@@ -122,6 +138,11 @@ func ParseMap(m map[string]interface{}) (Config, error) {
 	return c, err
 }
 
+// IsV2 returns whether the InfluxDB 2.x write API should be used.
+func (c Config) IsV2() bool {
+	return c.Organization.String != ""
+}
+
 func ParseURL(text string) (Config, error) {
 	c := Config{}
 	u, err := url.Parse(text)
@@ -161,6 +182,12 @@ func ParseURL(text string) (Config, error) {
 			c.Retention = null.StringFrom(vs[0])
 		case "consistency":
 			c.Consistency = null.StringFrom(vs[0])
+		case "organization":
+			c.Organization = null.StringFrom(vs[0])
+		case "bucket":
+			c.Bucket = null.StringFrom(vs[0])
+		case "token":
+			c.Token = null.StringFrom(vs[0])
 		case "tagsAsFields":
 			c.TagsAsFields = vs
 		default:
@@ -169,3 +196,24 @@ func ParseURL(text string) (Config, error) {
 	}
 	return c, err
 }
+
+// GetConsolidatedConfig combines the default config values with the JSON config, the environment
+// variables and the --out argument, in that order of precedence.
+func GetConsolidatedConfig(jsonRawConf json.RawMessage, arg string) (Config, error) {
+	result := *NewConfig()
+	if jsonRawConf != nil {
+		jsonConf := Config{}
+		if err := json.Unmarshal(jsonRawConf, &jsonConf); err != nil {
+			return result, err
+		}
+		result = result.Apply(jsonConf)
+	}
+	if err := envconfig.Process("k6", &result); err != nil {
+		return result, err
+	}
+	urlConf, err := ParseURL(arg)
+	if err != nil {
+		return result, err
+	}
+	return result.Apply(urlConf), nil
+}
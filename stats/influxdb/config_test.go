@@ -21,9 +21,12 @@
 package influxdb
 
 import (
+	"encoding/json"
+	"os"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	null "gopkg.in/guregu/null.v3"
 )
 
@@ -35,6 +38,7 @@ func TestParseArg(t *testing.T) {
 		"addr=http://localhost:8086,db=dbname": {Addr: null.StringFrom("http://localhost:8086"), DB: null.StringFrom("dbname")},
 		"addr=http://localhost:8086,db=dbname,insecure=false,payloadSize=69,":                    {Addr: null.StringFrom("http://localhost:8086"), DB: null.StringFrom("dbname"), Insecure: null.BoolFrom(false), PayloadSize: null.IntFrom(69)},
 		"addr=http://localhost:8086,db=dbname,insecure=false,payloadSize=69,tagsAsFields={fake}": {Addr: null.StringFrom("http://localhost:8086"), DB: null.StringFrom("dbname"), Insecure: null.BoolFrom(false), PayloadSize: null.IntFrom(69), TagsAsFields: []string{"fake"}},
+		"organization=org,bucket=bkt,token=tkn":                                                  {Organization: null.StringFrom("org"), Bucket: null.StringFrom("bkt"), Token: null.StringFrom("tkn")},
 	}
 
 	for str, expConfig := range testdata {
@@ -65,6 +69,9 @@ func TestParseURL(t *testing.T) {
 		"?insecure=ture":   {Config{}, "insecure must be true or false, not ture"},
 		"?payload_size=69": {Config{PayloadSize: null.IntFrom(69)}, ""},
 		"?payload_size=a":  {Config{}, "strconv.Atoi: parsing \"a\": invalid syntax"},
+		"?organization=org&bucket=bkt&token=tkn": {Config{
+			Organization: null.StringFrom("org"), Bucket: null.StringFrom("bkt"), Token: null.StringFrom("tkn"),
+		}, ""},
 	}
 	for str, data := range testdata {
 		t.Run(str, func(t *testing.T) {
@@ -88,3 +95,30 @@ func TestParseURL(t *testing.T) {
 		})
 	}
 }
+
+func TestGetConsolidatedConfig(t *testing.T) {
+	os.Clearenv()
+	defer os.Clearenv()
+
+	conf, err := GetConsolidatedConfig(nil, "")
+	require.NoError(t, err)
+	assert.Equal(t, "http://localhost:8086", conf.Addr.String)
+	assert.Equal(t, "k6", conf.DB.String)
+
+	require.NoError(t, os.Setenv("K6_INFLUXDB_USERNAME", "envuser"))
+	require.NoError(t, os.Setenv("K6_INFLUXDB_PASSWORD", "envpass"))
+	conf, err = GetConsolidatedConfig(
+		json.RawMessage(`{"addr":"http://jsonhost:8086","db":"jsondb","username":"jsonuser"}`),
+		"http://urlhost:8086/urldb",
+	)
+	require.NoError(t, err)
+	assert.Equal(t, null.StringFrom("http://urlhost:8086"), conf.Addr)
+	assert.Equal(t, null.StringFrom("urldb"), conf.DB)
+	assert.Equal(t, null.StringFrom("envuser"), conf.Username)
+	assert.Equal(t, null.StringFrom("envpass"), conf.Password)
+
+	_, err = GetConsolidatedConfig(nil, "http://urlhost:8086?dummy=1")
+	assert.EqualError(t, err, "unknown query parameter: dummy")
+	_, err = GetConsolidatedConfig(json.RawMessage(`{"addr":`), "")
+	assert.Error(t, err)
+}
@@ -37,6 +37,9 @@ func MakeClient(conf Config) (client.Client, error) {
 	if conf.Addr.String == "" {
 		conf.Addr = null.StringFrom("http://localhost:8086")
 	}
+	if conf.IsV2() {
+		return newV2Client(conf)
+	}
 	return client.NewHTTPClient(client.HTTPConfig{
 		Addr:               conf.Addr.String,
 		Username:           conf.Username.String,
@@ -0,0 +1,192 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package influxdb
+
+import (
+	"bytes"
+	"crypto/tls"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"path"
+	"strings"
+	"time"
+
+	client "github.com/influxdata/influxdb/client/v2"
+	"github.com/pkg/errors"
+)
+
+// v2Client implements client.Client on top of the InfluxDB 2.x HTTP API, so the rest of the
+// collector doesn't have to care which server version it's talking to. Points are still built
+// with the 1.x client, since the line protocol is the same.
+type v2Client struct {
+	url          *url.URL
+	org          string
+	bucket       string
+	token        string
+	precision    string
+	apiPrecision string
+	httpClient   *http.Client
+}
+
+var _ client.Client = &v2Client{}
+
+// v2Precisions maps the precisions accepted by the 1.x API to the 2.x ones.
+var v2Precisions = map[string]string{
+	"":   "ns",
+	"n":  "ns",
+	"ns": "ns",
+	"u":  "us",
+	"us": "us",
+	"ms": "ms",
+	"s":  "s",
+}
+
+func newV2Client(conf Config) (client.Client, error) {
+	u, err := url.Parse(conf.Addr.String)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, errors.Errorf("unsupported protocol scheme for the InfluxDB v2 API: %s", u.Scheme)
+	}
+
+	apiPrecision, ok := v2Precisions[conf.Precision.String]
+	if !ok {
+		return nil, errors.Errorf("unsupported precision for the InfluxDB v2 API: %s", conf.Precision.String)
+	}
+
+	// The points are formatted by the 1.x client, which only knows the single-letter units.
+	precision := apiPrecision
+	switch apiPrecision {
+	case "ns":
+		precision = "n"
+	case "us":
+		precision = "u"
+	}
+
+	bucket := conf.Bucket.String
+	if bucket == "" {
+		bucket = conf.DB.String
+	}
+	if bucket == "" {
+		bucket = "k6"
+	}
+
+	return &v2Client{
+		url:          u,
+		org:          conf.Organization.String,
+		bucket:       bucket,
+		token:        conf.Token.String,
+		precision:    precision,
+		apiPrecision: apiPrecision,
+		httpClient: &http.Client{
+			Transport: &http.Transport{
+				Proxy:           http.ProxyFromEnvironment,
+				TLSClientConfig: &tls.Config{InsecureSkipVerify: conf.Insecure.Bool}, //nolint:gosec
+			},
+		},
+	}, nil
+}
+
+func (c *v2Client) newRequest(method, endpoint string, body []byte) (*http.Request, error) {
+	u := *c.url
+	u.Path = path.Join(u.Path, endpoint)
+	req, err := http.NewRequest(method, u.String(), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("User-Agent", "k6")
+	if c.token != "" {
+		req.Header.Set("Authorization", "Token "+c.token)
+	}
+	return req, nil
+}
+
+func (c *v2Client) do(req *http.Request) error {
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	body, err := ioutil.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode != http.StatusNoContent && resp.StatusCode != http.StatusOK {
+		return errors.Errorf("%s: %s", resp.Status, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
+
+// Ping checks whether the server is up through its /ping endpoint.
+func (c *v2Client) Ping(timeout time.Duration) (time.Duration, string, error) {
+	startTime := time.Now()
+	req, err := c.newRequest("GET", "ping", nil)
+	if err != nil {
+		return 0, "", err
+	}
+	httpClient := *c.httpClient
+	httpClient.Timeout = timeout
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return 0, "", err
+	}
+	_ = resp.Body.Close()
+	return time.Since(startTime), resp.Header.Get("X-Influxdb-Version"), nil
+}
+
+// Write sends the batch to the /api/v2/write endpoint. The database, retention policy and
+// consistency of the batch are ignored, the configured organization and bucket are used instead.
+func (c *v2Client) Write(bp client.BatchPoints) error {
+	var b bytes.Buffer
+	for _, p := range bp.Points() {
+		b.WriteString(p.PrecisionString(c.precision))
+		b.WriteByte('\n')
+	}
+
+	req, err := c.newRequest("POST", "api/v2/write", b.Bytes())
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "text/plain; charset=utf-8")
+	params := req.URL.Query()
+	params.Set("org", c.org)
+	params.Set("bucket", c.bucket)
+	params.Set("precision", c.apiPrecision)
+	req.URL.RawQuery = params.Encode()
+
+	return c.do(req)
+}
+
+// Query isn't supported, the 2.x API uses Flux instead of InfluxQL.
+func (c *v2Client) Query(q client.Query) (*client.Response, error) {
+	return nil, errors.New("queries aren't supported with the InfluxDB v2 API")
+}
+
+// Close releases the idle connections of the underlying HTTP client.
+func (c *v2Client) Close() error {
+	if t, ok := c.httpClient.Transport.(*http.Transport); ok {
+		t.CloseIdleConnections()
+	}
+	return nil
+}
@@ -0,0 +1,87 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package influxdb
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/loadimpact/k6/stats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	null "gopkg.in/guregu/null.v3"
+)
+
+func TestV2Write(t *testing.T) {
+	var (
+		reqs int
+		body string
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reqs++
+		assert.Equal(t, "POST", r.Method)
+		assert.Equal(t, "/api/v2/write", r.URL.Path)
+		assert.Equal(t, "myorg", r.URL.Query().Get("org"))
+		assert.Equal(t, "mybucket", r.URL.Query().Get("bucket"))
+		assert.Equal(t, "ms", r.URL.Query().Get("precision"))
+		assert.Equal(t, "Token mytoken", r.Header.Get("Authorization"))
+		b, err := ioutil.ReadAll(r.Body)
+		assert.NoError(t, err)
+		body = string(b)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer srv.Close()
+
+	c, err := New(Config{
+		Addr:         null.StringFrom(srv.URL),
+		Organization: null.StringFrom("myorg"),
+		Bucket:       null.StringFrom("mybucket"),
+		Token:        null.StringFrom("mytoken"),
+		Precision:    null.StringFrom("ms"),
+		TagsAsFields: []string{"vu"},
+	})
+	require.NoError(t, err)
+	require.NoError(t, c.Init())
+
+	c.Collect([]stats.SampleContainer{stats.Sample{
+		Metric: stats.New("my_metric", stats.Gauge),
+		Time:   time.Unix(1550000000, 0),
+		Value:  1,
+		Tags:   stats.IntoSampleTags(&map[string]string{"a": "1", "vu": "2"}),
+	}})
+	c.commit()
+
+	assert.Equal(t, 1, reqs)
+	assert.Equal(t, "my_metric,a=1 value=1,vu=\"2\" 1550000000000\n", body)
+}
+
+func TestV2Config(t *testing.T) {
+	_, err := MakeClient(Config{Organization: null.StringFrom("org"), Precision: null.StringFrom("h")})
+	assert.EqualError(t, err, "unsupported precision for the InfluxDB v2 API: h")
+
+	cl, err := MakeClient(Config{Organization: null.StringFrom("org"), DB: null.StringFrom("dbname")})
+	require.NoError(t, err)
+	assert.Equal(t, "dbname", cl.(*v2Client).bucket)
+	assert.Equal(t, "http://localhost:8086", cl.(*v2Client).url.String())
+}
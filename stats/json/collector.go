@@ -28,6 +28,7 @@ import (
 
 	"github.com/loadimpact/k6/lib"
 	"github.com/loadimpact/k6/stats"
+	"github.com/loadimpact/k6/stats/output"
 	log "github.com/sirupsen/logrus"
 	"github.com/spf13/afero"
 )
@@ -57,6 +58,19 @@ func (c *Collector) HasSeenMetric(str string) bool {
 	return false
 }
 
+func init() {
+	// The --out argument is the name of the file, there's nothing to configure otherwise.
+	output.Register("json", func(_ json.RawMessage, arg string) (interface{}, error) {
+		return arg, nil
+	}, func(fname interface{}, params output.Params) (lib.Collector, error) {
+		fs := params.FS
+		if fs == nil {
+			fs = afero.NewOsFs()
+		}
+		return New(fs, fname.(string))
+	})
+}
+
 func New(fs afero.Fs, fname string) (*Collector, error) {
 	if fname == "" || fname == "-" {
 		return &Collector{
@@ -111,6 +125,10 @@ func (c *Collector) HandleMetric(m *stats.Metric) {
 
 func (c *Collector) Collect(scs []stats.SampleContainer) {
 	for _, sc := range scs {
+		if event, ok := sc.(stats.Event); ok {
+			c.HandleEvent(event)
+			continue
+		}
 		for _, sample := range sc.GetSamples() {
 			c.HandleMetric(sample.Metric)
 
@@ -133,6 +151,20 @@ func (c *Collector) Collect(scs []stats.SampleContainer) {
 	}
 }
 
+// HandleEvent writes an "Event" envelope for the event.
+func (c *Collector) HandleEvent(event stats.Event) {
+	row, err := json.Marshal(WrapEvent(event))
+	if err != nil {
+		log.WithField("filename", c.fname).WithError(err).Warning("JSON: Event couldn't be marshalled to JSON")
+		return
+	}
+
+	row = append(row, '\n')
+	if _, err := c.outfile.Write(row); err != nil {
+		log.WithField("filename", c.fname).Error("JSON: Error writing to file")
+	}
+}
+
 func (c *Collector) Link() string {
 	return ""
 }
@@ -0,0 +1,129 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package json
+
+import (
+	"bufio"
+	"encoding/json"
+	"io"
+
+	"github.com/loadimpact/k6/stats"
+	"github.com/pkg/errors"
+)
+
+// maxLineSize is the biggest envelope the Reader will accept; samples with a lot of
+// tags (e.g. long URLs) can be quite a bit longer than bufio's default token size.
+const maxLineSize = 1024 * 1024
+
+// metricData is the subset of a serialized stats.Metric needed to recreate it.
+type metricData struct {
+	Name     string           `json:"name"`
+	Type     stats.MetricType `json:"type"`
+	Contains stats.ValueType  `json:"contains"`
+}
+
+// Reader decodes the envelope stream written by the Collector back into samples.
+type Reader struct {
+	scanner *bufio.Scanner
+	line    int
+	metrics map[string]*stats.Metric
+	events  []stats.Event
+}
+
+// NewReader returns a Reader that reads envelopes from r, one per line.
+func NewReader(r io.Reader) *Reader {
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineSize)
+	return &Reader{
+		scanner: scanner,
+		metrics: make(map[string]*stats.Metric),
+	}
+}
+
+// Metrics returns all metrics that have been seen in the stream so far, by name.
+func (r *Reader) Metrics() map[string]*stats.Metric {
+	return r.metrics
+}
+
+// Events returns all events that have been seen in the stream so far.
+func (r *Reader) Events() []stats.Event {
+	return r.events
+}
+
+// Next returns the next sample in the stream, or io.EOF if there are no more.
+// Metric and Event envelopes are consumed transparently; a Point that refers to a metric that
+// wasn't defined earlier in the stream is an error.
+func (r *Reader) Next() (stats.Sample, error) {
+	for r.scanner.Scan() {
+		r.line++
+		data := r.scanner.Bytes()
+		if len(data) == 0 {
+			continue
+		}
+
+		var env struct {
+			Type   string          `json:"type"`
+			Data   json.RawMessage `json:"data"`
+			Metric string          `json:"metric"`
+		}
+		if err := json.Unmarshal(data, &env); err != nil {
+			return stats.Sample{}, errors.Wrapf(err, "line %d", r.line)
+		}
+
+		switch env.Type {
+		case "Metric":
+			var md metricData
+			if err := json.Unmarshal(env.Data, &md); err != nil {
+				return stats.Sample{}, errors.Wrapf(err, "line %d", r.line)
+			}
+			if md.Name == "" {
+				md.Name = env.Metric
+			}
+			m := stats.New(md.Name, md.Type, md.Contains)
+			if m == nil {
+				return stats.Sample{}, errors.Errorf("line %d: invalid type for metric '%s'", r.line, md.Name)
+			}
+			r.metrics[m.Name] = m
+		case "Point":
+			m, ok := r.metrics[env.Metric]
+			if !ok {
+				return stats.Sample{}, errors.Errorf("line %d: sample for undefined metric '%s'", r.line, env.Metric)
+			}
+			var js JSONSample
+			if err := json.Unmarshal(env.Data, &js); err != nil {
+				return stats.Sample{}, errors.Wrapf(err, "line %d", r.line)
+			}
+			return stats.Sample{Metric: m, Time: js.Time, Value: js.Value, Tags: js.Tags}, nil
+		case "Event":
+			var event stats.Event
+			if err := json.Unmarshal(env.Data, &event); err != nil {
+				return stats.Sample{}, errors.Wrapf(err, "line %d", r.line)
+			}
+			r.events = append(r.events, event)
+		default:
+			return stats.Sample{}, errors.Errorf("line %d: unknown envelope type '%s'", r.line, env.Type)
+		}
+	}
+	if err := r.scanner.Err(); err != nil {
+		return stats.Sample{}, err
+	}
+	return stats.Sample{}, io.EOF
+}
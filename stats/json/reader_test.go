@@ -0,0 +1,90 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package json
+
+import (
+	"bytes"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/loadimpact/k6/stats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReader(t *testing.T) {
+	t.Run("roundtrip", func(t *testing.T) {
+		var buf bytes.Buffer
+		c := &Collector{outfile: nopCloser{&buf}, fname: "-"}
+
+		metric := stats.New("my_metric", stats.Trend, stats.Time)
+		now := time.Unix(1550000000, 0).UTC()
+		c.Collect([]stats.SampleContainer{
+			stats.Sample{Metric: metric, Time: now, Value: 1.5, Tags: stats.IntoSampleTags(&map[string]string{"a": "1"})},
+			stats.Event{Time: now, Message: "deployed", Tags: stats.IntoSampleTags(&map[string]string{"b": "2"})},
+			stats.Sample{Metric: metric, Time: now.Add(time.Second), Value: 2},
+		})
+
+		r := NewReader(&buf)
+		s, err := r.Next()
+		require.NoError(t, err)
+		assert.Equal(t, "my_metric", s.Metric.Name)
+		assert.Equal(t, stats.Trend, s.Metric.Type)
+		assert.Equal(t, stats.Time, s.Metric.Contains)
+		assert.True(t, now.Equal(s.Time))
+		assert.Equal(t, 1.5, s.Value)
+		v, ok := s.Tags.Get("a")
+		assert.True(t, ok)
+		assert.Equal(t, "1", v)
+
+		s2, err := r.Next()
+		require.NoError(t, err)
+		assert.Equal(t, s.Metric, s2.Metric)
+		assert.True(t, s2.Tags.IsEmpty())
+
+		_, err = r.Next()
+		assert.Equal(t, io.EOF, err)
+		assert.Len(t, r.Metrics(), 1)
+
+		events := r.Events()
+		require.Len(t, events, 1)
+		assert.True(t, now.Equal(events[0].Time))
+		assert.Equal(t, "deployed", events[0].Message)
+		assert.Equal(t, map[string]string{"b": "2"}, events[0].Tags.CloneTags())
+	})
+
+	errors := map[string]string{
+		"undefined metric": `{"type":"Point","metric":"nope","data":{"value":1}}`,
+		"unknown type":     `{"type":"Wat","metric":"nope","data":{}}`,
+		"bad metric type":  `{"type":"Metric","metric":"m","data":{"name":"m","type":"wat"}}`,
+		"invalid json":     `{"type":`,
+	}
+	for name, data := range errors {
+		data := data
+		t.Run(name, func(t *testing.T) {
+			_, err := NewReader(strings.NewReader(data)).Next()
+			assert.Error(t, err)
+			assert.Contains(t, err.Error(), "line 1")
+		})
+	}
+}
@@ -68,3 +68,11 @@ func WrapMetric(metric *stats.Metric) *Envelope {
 		Data:   metric,
 	}
 }
+
+// WrapEvent wraps an event, which doesn't belong to any metric.
+func WrapEvent(event stats.Event) *Envelope {
+	return &Envelope{
+		Type: "Event",
+		Data: event,
+	}
+}
@@ -0,0 +1,141 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package kafka
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/loadimpact/k6/stats"
+	"github.com/pkg/errors"
+)
+
+// AvroSchema is the Avro schema used for the samples with the "avro" format.
+const AvroSchema = `{
+  "type": "record",
+  "name": "Sample",
+  "namespace": "io.k6",
+  "fields": [
+    {"name": "metric", "type": "string"},
+    {"name": "time", "type": {"type": "long", "logicalType": "timestamp-micros"}},
+    {"name": "value", "type": "double"},
+    {"name": "tags", "type": {"type": "map", "values": "string"}}
+  ]
+}`
+
+// avroMagicByte starts every message in the schema registry wire format, it's followed by the
+// 4-byte big-endian schema ID and the Avro binary encoded data.
+const avroMagicByte = 0
+
+// schemaRegistryTimeout limits the schema registration, so an unresponsive registry doesn't hang
+// the start of the test.
+const schemaRegistryTimeout = 10 * time.Second
+
+// avroEncoder encodes samples as Avro records, prefixed with the schema ID if a schema registry
+// is used. Without a registry, consumers need to know the AvroSchema in advance.
+type avroEncoder struct {
+	schemaID int32
+	registry bool
+}
+
+// newAvroEncoder registers the AvroSchema for the topic's value subject in the registry, if any.
+func newAvroEncoder(registryURL, topic string) (*avroEncoder, error) {
+	if registryURL == "" {
+		return &avroEncoder{}, nil
+	}
+
+	body, err := json.Marshal(map[string]string{"schema": AvroSchema})
+	if err != nil {
+		return nil, err
+	}
+	url := strings.TrimSuffix(registryURL, "/") + "/subjects/" + topic + "-value/versions"
+	client := &http.Client{Timeout: schemaRegistryTimeout}
+	res, err := client.Post(url, "application/vnd.schemaregistry.v1+json", bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = res.Body.Close() }()
+
+	var data struct {
+		ID      int32  `json:"id"`
+		Message string `json:"message"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&data); err != nil {
+		return nil, errors.Wrap(err, "couldn't decode the schema registry response")
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, errors.Errorf("couldn't register the Avro schema: %s: %s", res.Status, data.Message)
+	}
+	return &avroEncoder{schemaID: data.ID, registry: true}, nil
+}
+
+func (e *avroEncoder) encode(sample stats.Sample) []byte {
+	var buf bytes.Buffer
+	if e.registry {
+		buf.WriteByte(avroMagicByte)
+		_ = binary.Write(&buf, binary.BigEndian, e.schemaID)
+	}
+
+	avroString(&buf, sample.Metric.Name)
+	avroLong(&buf, sample.Time.UnixNano()/1000)
+	avroDouble(&buf, sample.Value)
+
+	tags := sample.Tags.CloneTags()
+	if len(tags) > 0 {
+		keys := make([]string, 0, len(tags))
+		for k := range tags {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		avroLong(&buf, int64(len(keys)))
+		for _, k := range keys {
+			avroString(&buf, k)
+			avroString(&buf, tags[k])
+		}
+	}
+	avroLong(&buf, 0) // end of the map blocks
+
+	return buf.Bytes()
+}
+
+func avroLong(buf *bytes.Buffer, v int64) {
+	var b [binary.MaxVarintLen64]byte
+	n := binary.PutVarint(b[:], v) // zig-zag encoded, as Avro expects
+	buf.Write(b[:n])
+}
+
+func avroDouble(buf *bytes.Buffer, v float64) {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], math.Float64bits(v))
+	buf.Write(b[:])
+}
+
+func avroString(buf *bytes.Buffer, s string) {
+	avroLong(buf, int64(len(s)))
+	buf.WriteString(s)
+}
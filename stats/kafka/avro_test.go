@@ -0,0 +1,83 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package kafka
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/loadimpact/k6/stats"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAvroEncoding(t *testing.T) {
+	sample := stats.Sample{
+		Metric: stats.New("m", stats.Gauge),
+		Time:   time.Unix(0, 1000),
+		Value:  1,
+		Tags:   stats.IntoSampleTags(&map[string]string{"b": "2", "a": "1"}),
+	}
+	exp := []byte{
+		0x02, 'm', // metric
+		0x02,                                           // time, in microseconds
+		0x00, 0x00, 0x00, 0x00, 0x00, 0x00, 0xf0, 0x3f, // value
+		0x04, 0x02, 'a', 0x02, '1', 0x02, 'b', 0x02, '2', 0x00, // tags
+	}
+
+	assert.Equal(t, exp, (&avroEncoder{}).encode(sample))
+	assert.Equal(t,
+		append([]byte{0x00, 0x00, 0x00, 0x00, 0x2a}, exp...),
+		(&avroEncoder{schemaID: 42, registry: true}).encode(sample),
+	)
+
+	sample.Tags = nil
+	assert.Equal(t, append(exp[:11:11], 0x00), (&avroEncoder{}).encode(sample))
+}
+
+func TestAvroSchemaRegistry(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/subjects/my_topic-value/versions" {
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error_code":40401,"message":"Subject not found."}`))
+			return
+		}
+		var data map[string]string
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&data))
+		assert.Equal(t, AvroSchema, data["schema"])
+		_, _ = w.Write([]byte(`{"id":42}`))
+	}))
+	defer srv.Close()
+
+	e, err := newAvroEncoder(srv.URL+"/", "my_topic")
+	require.NoError(t, err)
+	assert.Equal(t, &avroEncoder{schemaID: 42, registry: true}, e)
+
+	_, err = newAvroEncoder(srv.URL+"/prefix", "my_topic")
+	assert.EqualError(t, err, "couldn't register the Avro schema: 404 Not Found: Subject not found.")
+
+	e, err = newAvroEncoder("", "my_topic")
+	require.NoError(t, err)
+	assert.Equal(t, &avroEncoder{}, e)
+}
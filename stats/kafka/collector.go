@@ -31,9 +31,15 @@ import (
 	"github.com/loadimpact/k6/stats"
 	"github.com/loadimpact/k6/stats/influxdb"
 	jsonc "github.com/loadimpact/k6/stats/json"
+	"github.com/loadimpact/k6/stats/output"
 	log "github.com/sirupsen/logrus"
 )
 
+const (
+	formatInfluxDB = "influxdb"
+	formatAvro     = "avro"
+)
+
 // Collector implements the lib.Collector interface and should be used only for testing
 type Collector struct {
 	Producer sarama.SyncProducer
@@ -41,11 +47,26 @@ type Collector struct {
 
 	Samples []stats.Sample
 	lock    sync.Mutex
+
+	avro *avroEncoder
+}
+
+func init() {
+	output.Register("kafka", func(jsonRawConf json.RawMessage, arg string) (interface{}, error) {
+		return GetConsolidatedConfig(jsonRawConf, arg)
+	}, func(conf interface{}, _ output.Params) (lib.Collector, error) {
+		return New(conf.(Config))
+	})
 }
 
 // New creates an instance of the collector
 func New(conf Config) (*Collector, error) {
-	producer, err := sarama.NewSyncProducer(conf.Brokers, nil)
+	saramaConfig, err := conf.saramaConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	producer, err := sarama.NewSyncProducer(conf.Brokers, saramaConfig)
 	if err != nil {
 		return nil, err
 	}
@@ -56,8 +77,13 @@ func New(conf Config) (*Collector, error) {
 	}, nil
 }
 
-// Init does nothing, it's only included to satisfy the lib.Collector interface
-func (c *Collector) Init() error { return nil }
+// Init registers the Avro schema in the schema registry, if the "avro" format is used
+func (c *Collector) Init() (err error) {
+	if c.Config.Format.String == formatAvro {
+		c.avro, err = newAvroEncoder(c.Config.SchemaRegistry.String, c.Config.Topic.String)
+	}
+	return err
+}
 
 // Run just blocks until the context is done
 func (c *Collector) Run(ctx context.Context) {
@@ -110,7 +136,14 @@ func (c *Collector) formatSamples(samples stats.Samples) ([]string, error) {
 	var metrics []string
 
 	switch c.Config.Format.String {
-	case "influxdb":
+	case formatAvro:
+		if c.avro == nil {
+			c.avro = &avroEncoder{}
+		}
+		for _, sample := range samples {
+			metrics = append(metrics, string(c.avro.encode(sample)))
+		}
+	case formatInfluxDB:
 		i, err := influxdb.New(c.Config.InfluxDBConfig)
 		if err != nil {
 			return nil, err
@@ -153,8 +186,13 @@ func (c *Collector) pushMetrics() {
 	// Send the samples
 	log.Debug("Kafka: Delivering...")
 
-	for _, sample := range formattedSamples {
+	for i, sample := range formattedSamples {
 		msg := &sarama.ProducerMessage{Topic: c.Config.Topic.String, Value: sarama.StringEncoder(sample)}
+		if key := c.Config.PartitionKey.String; key != "" {
+			if value, ok := samples[i].Tags.Get(key); ok {
+				msg.Key = sarama.StringEncoder(value)
+			}
+		}
 		partition, offset, err := c.Producer.SendMessage(msg)
 		if err != nil {
 			log.WithError(err).Error("Kafka: failed to send message.")
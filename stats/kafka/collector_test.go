@@ -28,6 +28,7 @@ import (
 	"github.com/Shopify/sarama"
 	"github.com/loadimpact/k6/stats"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"gopkg.in/guregu/null.v3"
 )
 
@@ -82,3 +83,58 @@ func TestFormatSamples(t *testing.T) {
 	assert.Nil(t, err)
 	assert.Equal(t, []string{expJSON1, expJSON2}, fmtdSamples)
 }
+
+// recordingProducer keeps a copy of all messages sent through the wrapped producer.
+type recordingProducer struct {
+	sarama.SyncProducer
+	messages []*sarama.ProducerMessage
+}
+
+func (p *recordingProducer) SendMessage(msg *sarama.ProducerMessage) (int32, int64, error) {
+	p.messages = append(p.messages, msg)
+	return p.SyncProducer.SendMessage(msg)
+}
+
+func TestPushMetrics(t *testing.T) {
+	broker := sarama.NewMockBroker(t, 1)
+	defer broker.Close()
+	broker.SetHandlerByMap(map[string]sarama.MockResponse{
+		"MetadataRequest": sarama.NewMockMetadataResponse(t).
+			SetBroker(broker.Addr(), broker.BrokerID()).
+			SetLeader("my_topic", 0, broker.BrokerID()),
+		"ProduceRequest": sarama.NewMockProduceResponse(t),
+	})
+
+	c, err := New(NewConfig().Apply(Config{
+		Brokers:      []string{broker.Addr()},
+		Topic:        null.StringFrom("my_topic"),
+		Format:       null.StringFrom("avro"),
+		PartitionKey: null.StringFrom("vu"),
+	}))
+	require.NoError(t, err)
+	require.NoError(t, c.Init())
+	producer := &recordingProducer{SyncProducer: c.Producer}
+	c.Producer = producer
+
+	metric := stats.New("my_metric", stats.Gauge)
+	c.Collect([]stats.SampleContainer{stats.Samples{
+		{Metric: metric, Value: 1, Tags: stats.IntoSampleTags(&map[string]string{"vu": "1"})},
+		{Metric: metric, Value: 2, Tags: stats.IntoSampleTags(&map[string]string{"vu": "2"})},
+		{Metric: metric, Value: 3},
+	}})
+	c.pushMetrics()
+	require.NoError(t, c.Producer.Close())
+
+	require.Len(t, producer.messages, 3)
+	assert.Equal(t, sarama.StringEncoder("1"), producer.messages[0].Key)
+	assert.Equal(t, sarama.StringEncoder("2"), producer.messages[1].Key)
+	assert.Nil(t, producer.messages[2].Key)
+
+	produced := 0
+	for _, rr := range broker.History() {
+		if _, ok := rr.Request.(*sarama.ProduceRequest); ok {
+			produced++
+		}
+	}
+	assert.Equal(t, 3, produced)
+}
@@ -21,15 +21,31 @@
 package kafka
 
 import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/json"
+	"io/ioutil"
+	"strings"
 	"time"
 
+	"github.com/Shopify/sarama"
+	"github.com/kelseyhightower/envconfig"
 	"github.com/kubernetes/helm/pkg/strvals"
 	"github.com/loadimpact/k6/lib/types"
 	"github.com/loadimpact/k6/stats/influxdb"
 	"github.com/mitchellh/mapstructure"
+	"github.com/pkg/errors"
 	"gopkg.in/guregu/null.v3"
 )
 
+// SASL mechanisms. SASLPlain is the only one that the vendored Kafka client supports, the SCRAM
+// ones are recognized only to reject them with a clear error until the client is updated.
+const (
+	SASLPlain       = "plain"
+	SASLScramSHA256 = "scram-sha-256"
+	SASLScramSHA512 = "scram-sha-512"
+)
+
 // Config is the config for the kafka collector
 type Config struct {
 	// Connection.
@@ -40,6 +56,23 @@ type Config struct {
 	Format       null.String        `json:"format" envconfig:"KAFKA_FORMAT"`
 	PushInterval types.NullDuration `json:"push_interval" envconfig:"KAFKA_PUSH_INTERVAL"`
 
+	// PartitionKey is the name of the sample tag used as the message key, e.g. "vu".
+	PartitionKey null.String `json:"partition_key" envconfig:"KAFKA_PARTITION_KEY"`
+	// SchemaRegistry is the URL of the Confluent-compatible schema registry used with "avro".
+	SchemaRegistry null.String `json:"schema_registry" envconfig:"KAFKA_SCHEMA_REGISTRY"`
+
+	// TLS.
+	TLS                null.Bool   `json:"tls" envconfig:"KAFKA_TLS"`
+	CACert             null.String `json:"ca_cert" envconfig:"KAFKA_CA_CERT"`
+	ClientCert         null.String `json:"client_cert" envconfig:"KAFKA_CLIENT_CERT"`
+	ClientKey          null.String `json:"client_key" envconfig:"KAFKA_CLIENT_KEY"`
+	InsecureSkipVerify null.Bool   `json:"insecure_skip_verify" envconfig:"KAFKA_INSECURE_SKIP_VERIFY"`
+
+	// SASL.
+	SASLMechanism null.String `json:"sasl_mechanism" envconfig:"KAFKA_SASL_MECHANISM"`
+	SASLUser      null.String `json:"sasl_user" envconfig:"KAFKA_SASL_USER"`
+	SASLPassword  null.String `json:"sasl_password" envconfig:"KAFKA_SASL_PASSWORD"`
+
 	InfluxDBConfig influxdb.Config `json:"influxdb"`
 }
 
@@ -51,6 +84,19 @@ type config struct {
 	Format       string   `json:"format" mapstructure:"format" envconfig:"KAFKA_FORMAT"`
 	PushInterval string   `json:"push_interval" mapstructure:"push_interval" envconfig:"KAFKA_PUSH_INTERVAL"`
 
+	PartitionKey   string `json:"partition_key" mapstructure:"partition_key" envconfig:"KAFKA_PARTITION_KEY"`
+	SchemaRegistry string `json:"schema_registry" mapstructure:"schema_registry" envconfig:"KAFKA_SCHEMA_REGISTRY"`
+
+	TLS                bool   `json:"tls" mapstructure:"tls" envconfig:"KAFKA_TLS"`
+	CACert             string `json:"ca_cert" mapstructure:"ca_cert" envconfig:"KAFKA_CA_CERT"`
+	ClientCert         string `json:"client_cert" mapstructure:"client_cert" envconfig:"KAFKA_CLIENT_CERT"`
+	ClientKey          string `json:"client_key" mapstructure:"client_key" envconfig:"KAFKA_CLIENT_KEY"`
+	InsecureSkipVerify bool   `json:"insecure_skip_verify" mapstructure:"insecure_skip_verify" envconfig:"KAFKA_INSECURE_SKIP_VERIFY"`
+
+	SASLMechanism string `json:"sasl_mechanism" mapstructure:"sasl_mechanism" envconfig:"KAFKA_SASL_MECHANISM"`
+	SASLUser      string `json:"sasl_user" mapstructure:"sasl_user" envconfig:"KAFKA_SASL_USER"`
+	SASLPassword  string `json:"sasl_password" mapstructure:"sasl_password" envconfig:"KAFKA_SASL_PASSWORD"`
+
 	InfluxDBConfig influxdb.Config `json:"influxdb" mapstructure:"influxdb"`
 }
 
@@ -75,9 +121,83 @@ func (c Config) Apply(cfg Config) Config {
 	if cfg.PushInterval.Valid {
 		c.PushInterval = cfg.PushInterval
 	}
+	if cfg.PartitionKey.Valid {
+		c.PartitionKey = cfg.PartitionKey
+	}
+	if cfg.SchemaRegistry.Valid {
+		c.SchemaRegistry = cfg.SchemaRegistry
+	}
+	if cfg.TLS.Valid {
+		c.TLS = cfg.TLS
+	}
+	if cfg.CACert.Valid {
+		c.CACert = cfg.CACert
+	}
+	if cfg.ClientCert.Valid {
+		c.ClientCert = cfg.ClientCert
+	}
+	if cfg.ClientKey.Valid {
+		c.ClientKey = cfg.ClientKey
+	}
+	if cfg.InsecureSkipVerify.Valid {
+		c.InsecureSkipVerify = cfg.InsecureSkipVerify
+	}
+	if cfg.SASLMechanism.Valid {
+		c.SASLMechanism = cfg.SASLMechanism
+	}
+	if cfg.SASLUser.Valid {
+		c.SASLUser = cfg.SASLUser
+	}
+	if cfg.SASLPassword.Valid {
+		c.SASLPassword = cfg.SASLPassword
+	}
 	return c
 }
 
+// saramaConfig returns the producer configuration, with TLS and SASL set up if needed.
+func (c Config) saramaConfig() (*sarama.Config, error) {
+	sc := sarama.NewConfig()
+	sc.Producer.Return.Successes = true
+
+	if c.TLS.Bool {
+		tlsConfig := &tls.Config{InsecureSkipVerify: c.InsecureSkipVerify.Bool} //nolint:gosec
+		if c.CACert.String != "" {
+			pem, err := ioutil.ReadFile(c.CACert.String)
+			if err != nil {
+				return nil, err
+			}
+			tlsConfig.RootCAs = x509.NewCertPool()
+			if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+				return nil, errors.Errorf("no certificates found in %s", c.CACert.String)
+			}
+		}
+		if c.ClientCert.String != "" || c.ClientKey.String != "" {
+			cert, err := tls.LoadX509KeyPair(c.ClientCert.String, c.ClientKey.String)
+			if err != nil {
+				return nil, err
+			}
+			tlsConfig.Certificates = []tls.Certificate{cert}
+		}
+		sc.Net.TLS.Enable = true
+		sc.Net.TLS.Config = tlsConfig
+	}
+
+	switch strings.ToLower(c.SASLMechanism.String) {
+	case "":
+	case SASLPlain:
+		sc.Net.SASL.Enable = true
+		sc.Net.SASL.User = c.SASLUser.String
+		sc.Net.SASL.Password = c.SASLPassword.String
+	case SASLScramSHA256, SASLScramSHA512:
+		//TODO: support SCRAM, it needs a newer version of github.com/Shopify/sarama
+		return nil, errors.Errorf("the SASL mechanism '%s' isn't supported yet, only '%s' is", c.SASLMechanism.String, SASLPlain)
+	default:
+		return nil, errors.Errorf("unknown SASL mechanism '%s'", c.SASLMechanism.String)
+	}
+
+	return sc, sc.Validate()
+}
+
 // ParseArg takes an arg string and converts it to a config
 func ParseArg(arg string) (Config, error) {
 	c := Config{}
@@ -117,5 +237,54 @@ func ParseArg(arg string) (Config, error) {
 	c.Topic = null.StringFrom(cfg.Topic)
 	c.Format = null.StringFrom(cfg.Format)
 
+	strs := map[string]struct {
+		dst *null.String
+		val string
+	}{
+		"partition_key":   {&c.PartitionKey, cfg.PartitionKey},
+		"schema_registry": {&c.SchemaRegistry, cfg.SchemaRegistry},
+		"ca_cert":         {&c.CACert, cfg.CACert},
+		"client_cert":     {&c.ClientCert, cfg.ClientCert},
+		"client_key":      {&c.ClientKey, cfg.ClientKey},
+		"sasl_mechanism":  {&c.SASLMechanism, cfg.SASLMechanism},
+		"sasl_user":       {&c.SASLUser, cfg.SASLUser},
+		"sasl_password":   {&c.SASLPassword, cfg.SASLPassword},
+	}
+	for key, v := range strs {
+		if _, ok := params[key]; ok {
+			*v.dst = null.StringFrom(v.val)
+		}
+	}
+	if _, ok := params["tls"]; ok {
+		c.TLS = null.BoolFrom(cfg.TLS)
+	}
+	if _, ok := params["insecure_skip_verify"]; ok {
+		c.InsecureSkipVerify = null.BoolFrom(cfg.InsecureSkipVerify)
+	}
+
 	return c, nil
 }
+
+// GetConsolidatedConfig combines the default config values with the JSON config, the environment
+// variables and the --out argument, in that order of precedence.
+func GetConsolidatedConfig(jsonRawConf json.RawMessage, arg string) (Config, error) {
+	result := NewConfig()
+	if jsonRawConf != nil {
+		jsonConf := Config{}
+		if err := json.Unmarshal(jsonRawConf, &jsonConf); err != nil {
+			return result, err
+		}
+		result = result.Apply(jsonConf)
+	}
+	if err := envconfig.Process("k6", &result); err != nil {
+		return result, err
+	}
+	if arg != "" {
+		argConf, err := ParseArg(arg)
+		if err != nil {
+			return result, err
+		}
+		result = result.Apply(argConf)
+	}
+	return result, nil
+}
@@ -63,3 +63,51 @@ func TestConfigParseArg(t *testing.T) {
 	assert.Equal(t, null.StringFrom("influxdb"), c.Format)
 	assert.Equal(t, expInfluxConfig, c.InfluxDBConfig)
 }
+
+func TestConfigParseArgSecurity(t *testing.T) {
+	c, err := ParseArg("brokers=broker1,topic=someTopic,format=avro,schema_registry=http://registry:8081," +
+		"partition_key=vu,tls=true,ca_cert=ca.pem,insecure_skip_verify=false," +
+		"sasl_mechanism=plain,sasl_user=user,sasl_password=pass")
+	assert.Nil(t, err)
+	assert.Equal(t, null.StringFrom("avro"), c.Format)
+	assert.Equal(t, null.StringFrom("http://registry:8081"), c.SchemaRegistry)
+	assert.Equal(t, null.StringFrom("vu"), c.PartitionKey)
+	assert.Equal(t, null.BoolFrom(true), c.TLS)
+	assert.Equal(t, null.StringFrom("ca.pem"), c.CACert)
+	assert.Equal(t, null.BoolFrom(false), c.InsecureSkipVerify)
+	assert.False(t, c.ClientCert.Valid)
+	assert.Equal(t, null.StringFrom("plain"), c.SASLMechanism)
+	assert.Equal(t, null.StringFrom("user"), c.SASLUser)
+	assert.Equal(t, null.StringFrom("pass"), c.SASLPassword)
+}
+
+func TestConfigSarama(t *testing.T) {
+	sc, err := NewConfig().saramaConfig()
+	assert.Nil(t, err)
+	assert.False(t, sc.Net.TLS.Enable)
+	assert.False(t, sc.Net.SASL.Enable)
+
+	sc, err = Config{
+		TLS:                null.BoolFrom(true),
+		InsecureSkipVerify: null.BoolFrom(true),
+		SASLMechanism:      null.StringFrom("PLAIN"),
+		SASLUser:           null.StringFrom("user"),
+		SASLPassword:       null.StringFrom("pass"),
+	}.saramaConfig()
+	assert.Nil(t, err)
+	assert.True(t, sc.Net.TLS.Enable)
+	assert.True(t, sc.Net.TLS.Config.InsecureSkipVerify)
+	assert.True(t, sc.Net.SASL.Enable)
+	assert.Equal(t, "user", sc.Net.SASL.User)
+
+	_, err = Config{SASLMechanism: null.StringFrom("plain")}.saramaConfig()
+	assert.EqualError(t, err, "kafka: invalid configuration (Net.SASL.User must not be empty when SASL is enabled)")
+	_, err = Config{SASLMechanism: null.StringFrom("scram-sha-512")}.saramaConfig()
+	assert.EqualError(t, err, "the SASL mechanism 'scram-sha-512' isn't supported yet, only 'plain' is")
+	_, err = Config{SASLMechanism: null.StringFrom("SCRAM-SHA-256")}.saramaConfig()
+	assert.EqualError(t, err, "the SASL mechanism 'SCRAM-SHA-256' isn't supported yet, only 'plain' is")
+	_, err = Config{SASLMechanism: null.StringFrom("gssapi")}.saramaConfig()
+	assert.EqualError(t, err, "unknown SASL mechanism 'gssapi'")
+	_, err = Config{TLS: null.BoolFrom(true), CACert: null.StringFrom("/nonexistent/ca.pem")}.saramaConfig()
+	assert.Error(t, err)
+}
@@ -0,0 +1,114 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+// Package output is the registry of the outputs that can be used with --out. Every output package
+// registers itself when it's imported, so additional outputs can be compiled into k6 by just
+// blank-importing their packages, without any changes to the cmd package:
+//
+//	import _ "example.com/k6-outputs/myoutput"
+package output
+
+import (
+	"encoding/json"
+	"sort"
+	"sync"
+
+	"github.com/loadimpact/k6/lib"
+	"github.com/loadimpact/k6/loader"
+	"github.com/pkg/errors"
+	"github.com/spf13/afero"
+)
+
+// ConfigParser consolidates the config of an output from its section in the "collectors" part of
+// the JSON config (nil if there isn't one), the environment variables and the --out argument,
+// i.e. whatever follows the first "=" in "--out name=arg". The returned value is passed as-is to
+// the Constructor of the same output.
+type ConfigParser func(jsonConfig json.RawMessage, arg string) (interface{}, error)
+
+// Constructor creates a new collector with a config returned by the output's ConfigParser.
+type Constructor func(config interface{}, params Params) (lib.Collector, error)
+
+// Params are the parts of the test run that some outputs need, besides their own config.
+type Params struct {
+	FS      afero.Fs
+	Options lib.Options
+	Source  *loader.SourceData // nil if the samples don't come from a script, e.g. with k6 replay
+}
+
+// Output describes a registered output.
+type Output struct {
+	Name        string
+	ParseConfig ConfigParser
+	New         Constructor
+}
+
+//nolint:gochecknoglobals
+var (
+	outputs     = make(map[string]Output)
+	outputsLock sync.RWMutex
+)
+
+// Register makes an output available under the given name. It panics if the name is empty,
+// if either function is nil, or if an output with the same name was already registered.
+func Register(name string, parseConfig ConfigParser, constructor Constructor) {
+	if name == "" || parseConfig == nil || constructor == nil {
+		panic("output: invalid registration for '" + name + "'")
+	}
+
+	outputsLock.Lock()
+	defer outputsLock.Unlock()
+	if _, ok := outputs[name]; ok {
+		panic("output: '" + name + "' is already registered")
+	}
+	outputs[name] = Output{Name: name, ParseConfig: parseConfig, New: constructor}
+}
+
+// Get returns the output registered under the given name.
+func Get(name string) (Output, bool) {
+	outputsLock.RLock()
+	defer outputsLock.RUnlock()
+	out, ok := outputs[name]
+	return out, ok
+}
+
+// Names returns the sorted names of all registered outputs.
+func Names() []string {
+	outputsLock.RLock()
+	defer outputsLock.RUnlock()
+	names := make([]string, 0, len(outputs))
+	for name := range outputs {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// New parses the config of the named output and creates a collector with it.
+func New(name string, jsonConfig json.RawMessage, arg string, params Params) (lib.Collector, error) {
+	out, ok := Get(name)
+	if !ok {
+		return nil, errors.Errorf("unknown output type: %s", name)
+	}
+	config, err := out.ParseConfig(jsonConfig, arg)
+	if err != nil {
+		return nil, err
+	}
+	return out.New(config, params)
+}
@@ -0,0 +1,69 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package output
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/loadimpact/k6/lib"
+	"github.com/loadimpact/k6/stats/dummy"
+	"github.com/pkg/errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRegistry(t *testing.T) {
+	type testConfig struct {
+		JSON string
+		Arg  string
+	}
+	parse := func(jsonConfig json.RawMessage, arg string) (interface{}, error) {
+		if arg == "invalid" {
+			return nil, errors.New("invalid arg")
+		}
+		return testConfig{string(jsonConfig), arg}, nil
+	}
+	var created testConfig
+	constructor := func(config interface{}, params Params) (lib.Collector, error) {
+		created = config.(testConfig)
+		return &dummy.Collector{}, nil
+	}
+
+	Register("test-output", parse, constructor)
+	assert.Contains(t, Names(), "test-output")
+	_, ok := Get("test-output")
+	assert.True(t, ok)
+
+	collector, err := New("test-output", json.RawMessage(`{"a":1}`), "arg", Params{})
+	require.NoError(t, err)
+	assert.IsType(t, &dummy.Collector{}, collector)
+	assert.Equal(t, testConfig{`{"a":1}`, "arg"}, created)
+
+	_, err = New("test-output", nil, "invalid", Params{})
+	assert.EqualError(t, err, "invalid arg")
+	_, err = New("not-registered", nil, "", Params{})
+	assert.EqualError(t, err, "unknown output type: not-registered")
+
+	assert.Panics(t, func() { Register("test-output", parse, constructor) })
+	assert.Panics(t, func() { Register("", parse, constructor) })
+	assert.Panics(t, func() { Register("nil-constructor", parse, nil) })
+}
@@ -21,9 +21,45 @@
 package statsd
 
 import (
+	"encoding/json"
+
+	"github.com/kelseyhightower/envconfig"
+	"github.com/loadimpact/k6/lib"
+	"github.com/loadimpact/k6/stats/output"
 	"github.com/loadimpact/k6/stats/statsd/common"
+	"github.com/pkg/errors"
 )
 
+func init() {
+	output.Register("statsd", func(jsonRawConf json.RawMessage, _ string) (interface{}, error) {
+		return GetConsolidatedConfig(jsonRawConf)
+	}, func(conf interface{}, _ output.Params) (lib.Collector, error) {
+		return New(conf.(common.Config))
+	})
+}
+
+// GetConsolidatedConfig combines the default config values with the JSON config and the
+// K6_STATSD_* environment variables. The statsd output doesn't take a --out argument.
+func GetConsolidatedConfig(jsonRawConf json.RawMessage) (common.Config, error) {
+	result := common.NewConfig()
+	// The statsd protocol has no tags, so the tag filters can only be used with the datadog output
+	result.TagBlacklist, result.TagWhitelist = nil, nil
+	if jsonRawConf != nil {
+		jsonConf := common.Config{}
+		if err := json.Unmarshal(jsonRawConf, &jsonConf); err != nil {
+			return result, err
+		}
+		result = result.Apply(jsonConf)
+	}
+	if err := envconfig.Process("k6_statsd", &result); err != nil {
+		return result, err
+	}
+	if result.TagBlacklist != nil || result.TagWhitelist != nil {
+		return result, errors.New("the statsd output doesn't send tags, use the datadog output to filter them")
+	}
+	return result, nil
+}
+
 // New creates a new statsd connector client
 func New(conf common.Config) (*common.Collector, error) {
 	return &common.Collector{
@@ -1,10 +1,13 @@
 package statsd
 
 import (
+	"encoding/json"
+	"os"
 	"testing"
 
 	"github.com/loadimpact/k6/stats"
 	"github.com/loadimpact/k6/stats/statsd/common/testutil"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -14,3 +17,23 @@ func TestCollector(t *testing.T) {
 			require.Equal(t, expectedOutput, output)
 		})
 }
+
+func TestGetConsolidatedConfig(t *testing.T) {
+	os.Clearenv()
+	defer os.Clearenv()
+
+	conf, err := GetConsolidatedConfig(json.RawMessage(`{"addr":"jsonhost:8125","sampleRates":{"vus":0.5}}`))
+	require.NoError(t, err)
+	assert.Equal(t, "jsonhost:8125", conf.Addr.String)
+	assert.Equal(t, map[string]float64{"vus": 0.5}, conf.SampleRates)
+
+	const tagsErr = "the statsd output doesn't send tags, use the datadog output to filter them"
+	_, err = GetConsolidatedConfig(json.RawMessage(`{"tagWhitelist":["url"]}`))
+	assert.EqualError(t, err, tagsErr)
+	_, err = GetConsolidatedConfig(json.RawMessage(`{"tagBlacklist":["url"]}`))
+	assert.EqualError(t, err, tagsErr)
+
+	require.NoError(t, os.Setenv("K6_STATSD_TAG_BLACKLIST", "url"))
+	_, err = GetConsolidatedConfig(nil)
+	assert.EqualError(t, err, tagsErr)
+}
@@ -41,6 +41,8 @@ type Collector struct {
 	// ProcessTags is called on a map of all tags for each metric and returns a slice representation
 	// of those tags that should be sent. No tags are send in case of ProcessTags being null
 	ProcessTags func(map[string]string) []string
+	// DogStatsD enables the trend types that are DogStatsD extensions, which stock statsd drops.
+	DogStatsD bool
 
 	logger     *log.Entry
 	client     *statsd.Client
@@ -52,6 +54,19 @@ type Collector struct {
 // Init sets up the collector
 func (c *Collector) Init() (err error) {
 	c.logger = log.WithField("type", c.Type)
+	if err = c.Config.Validate(); err != nil {
+		c.logger.Error(err)
+
+		return err
+	}
+	if trendType := c.Config.TrendType.String; !c.DogStatsD &&
+		(trendType == TrendTypeHistogram || trendType == TrendTypeDistribution) {
+		err = fmt.Errorf("the '%s' trend type is a DogStatsD extension, it's only supported by the datadog output",
+			trendType)
+		c.logger.Error(err)
+
+		return err
+	}
 	if address := c.Config.Addr.String; address == "" {
 		err = fmt.Errorf(
 			"connection string is invalid. Received: \"%+s\"",
@@ -175,23 +190,35 @@ func (c *Collector) dispatch(entry *Sample) error {
 		tagList = c.ProcessTags(entry.Tags)
 	}
 
+	rate := 1.0
+	if r, ok := c.Config.SampleRates[entry.Metric]; ok {
+		rate = r
+	}
+
 	switch entry.Type {
 	case stats.Counter:
-		return c.client.Count(entry.Metric, int64(entry.Value), tagList, 1)
+		return c.client.Count(entry.Metric, int64(entry.Value), tagList, rate)
 	case stats.Trend:
-		return c.client.TimeInMilliseconds(entry.Metric, entry.Value, tagList, 1)
+		switch c.Config.TrendType.String {
+		case TrendTypeDistribution:
+			return c.client.Distribution(entry.Metric, entry.Value, tagList, rate)
+		case TrendTypeHistogram:
+			return c.client.Histogram(entry.Metric, entry.Value, tagList, rate)
+		default:
+			return c.client.TimeInMilliseconds(entry.Metric, entry.Value, tagList, rate)
+		}
 	case stats.Gauge:
-		return c.client.Gauge(entry.Metric, entry.Value, tagList, 1)
+		return c.client.Gauge(entry.Metric, entry.Value, tagList, rate)
 	case stats.Rate:
 		if check := entry.Tags["check"]; check != "" {
 			return c.client.Count(
 				checkToString(check, entry.Value),
 				1,
 				tagList,
-				1,
+				rate,
 			)
 		}
-		return c.client.Count(entry.Metric, int64(entry.Value), tagList, 1)
+		return c.client.Count(entry.Metric, int64(entry.Value), tagList, rate)
 	default:
 		return fmt.Errorf("unsupported metric type %s", entry.Type)
 	}
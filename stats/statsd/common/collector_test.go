@@ -1,9 +1,16 @@
 package common
 
 import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 
 	"github.com/loadimpact/k6/lib"
+	"github.com/loadimpact/k6/stats"
 	"github.com/stretchr/testify/require"
 	null "gopkg.in/guregu/null.v3"
 )
@@ -42,3 +49,84 @@ func TestGetRequiredSystemTags(t *testing.T) {
 	var c = &Collector{}
 	require.Equal(t, lib.TagSet{}, c.GetRequiredSystemTags())
 }
+
+func TestConfigValidate(t *testing.T) {
+	require.NoError(t, NewConfig().Validate())
+	require.NoError(t, Config{TrendType: null.StringFrom(TrendTypeDistribution)}.Validate())
+	require.Error(t, Config{TrendType: null.StringFrom("percentiles")}.Validate())
+	require.NoError(t, Config{SampleRates: map[string]float64{"http_reqs": 0.5}}.Validate())
+	require.Error(t, Config{SampleRates: map[string]float64{"http_reqs": 0}}.Validate())
+	require.Error(t, Config{SampleRates: map[string]float64{"http_reqs": 1.5}}.Validate())
+}
+
+func TestProcessTags(t *testing.T) {
+	tags := map[string]string{"a": "1", "b": "2", "c": "3", "empty": ""}
+
+	require.ElementsMatch(t, []string{"a:1", "b:2", "c:3"}, NewConfig().ProcessTags(tags))
+	require.ElementsMatch(t, []string{"a:1", "c:3"}, Config{TagBlacklist: lib.GetTagSet("b")}.ProcessTags(tags))
+	require.ElementsMatch(t, []string{"a:1", "b:2"}, Config{TagWhitelist: lib.GetTagSet("a", "b", "empty")}.ProcessTags(tags))
+	require.ElementsMatch(t, []string{"a:1"}, Config{
+		TagWhitelist: lib.GetTagSet("a", "b"),
+		TagBlacklist: lib.GetTagSet("b"),
+	}.ProcessTags(tags))
+}
+
+func TestDogStatsDTrendTypes(t *testing.T) {
+	for _, trendType := range []string{TrendTypeHistogram, TrendTypeDistribution} {
+		c := &Collector{
+			Config: NewConfig().Apply(Config{TrendType: null.StringFrom(trendType)}),
+			Type:   "statsd",
+		}
+		require.EqualError(t, c.Init(), fmt.Sprintf(
+			"the '%s' trend type is a DogStatsD extension, it's only supported by the datadog output", trendType))
+	}
+}
+
+func TestDispatchTrendTypesOverUDS(t *testing.T) {
+	dir, err := ioutil.TempDir("", "k6-statsd")
+	require.NoError(t, err)
+	defer func() { _ = os.RemoveAll(dir) }()
+
+	sockPath := filepath.Join(dir, "statsd.sock")
+	conn, err := net.ListenUnixgram("unixgram", &net.UnixAddr{Name: sockPath, Net: "unixgram"})
+	require.NoError(t, err)
+	defer func() { _ = conn.Close() }()
+
+	testdata := map[string]string{
+		"":                    "my_trend:14.000000|ms|@0.999999",
+		TrendTypeTiming:       "my_trend:14.000000|ms|@0.999999",
+		TrendTypeHistogram:    "my_trend:14.000000|h|@0.999999",
+		TrendTypeDistribution: "my_trend:14.000000|d|@0.999999",
+	}
+	for trendType, expected := range testdata {
+		c := &Collector{
+			Config: NewConfig().Apply(Config{
+				Addr:        null.StringFrom("unix://" + sockPath),
+				Namespace:   null.StringFrom(""),
+				BufferSize:  null.IntFrom(0),
+				TrendType:   null.NewString(trendType, trendType != ""),
+				SampleRates: map[string]float64{"my_trend": 0.999999},
+			}),
+			Type:      "testtype",
+			DogStatsD: true,
+		}
+		require.NoError(t, c.Init())
+
+		// The sample rate is just high enough that the sample is (almost) never dropped
+		// by the client, but it's still reported to the server.
+		for i := 0; ; i++ {
+			require.True(t, i < 10, "no message received")
+			require.NoError(t, c.dispatch(&Sample{Type: stats.Trend, Metric: "my_trend", Value: 14}))
+			require.NoError(t, c.client.Flush())
+			var buf [1024]byte
+			require.NoError(t, conn.SetReadDeadline(time.Now().Add(100*time.Millisecond)))
+			n, err := conn.Read(buf[:])
+			if err != nil {
+				continue
+			}
+			require.Equal(t, expected, string(buf[:n]), trendType)
+			break
+		}
+		c.finish()
+	}
+}
@@ -21,18 +21,38 @@
 package common
 
 import (
+	"fmt"
 	"time"
 
+	"github.com/loadimpact/k6/lib"
 	"github.com/loadimpact/k6/lib/types"
 	null "gopkg.in/guregu/null.v3"
 )
 
+// The different ways trend metrics can be sent. Histograms and distributions are DogStatsD
+// extensions; distributions are aggregated server-side, so their percentiles are correct even
+// when the same metric is sent from many k6 instances.
+const (
+	TrendTypeTiming       = "timing"
+	TrendTypeHistogram    = "histogram"
+	TrendTypeDistribution = "distribution"
+)
+
 // Config defines the statsd configuration
 type Config struct {
+	// Addr is either a host:port UDP address or a unix:///path/to/socket Unix domain socket.
 	Addr         null.String        `json:"addr,omitempty" envconfig:"ADDR"`
 	BufferSize   null.Int           `json:"bufferSize,omitempty" envconfig:"BUFFER_SIZE"`
 	Namespace    null.String        `json:"namespace,omitempty" envconfig:"NAMESPACE"`
 	PushInterval types.NullDuration `json:"pushInterval,omitempty" envconfig:"PUSH_INTERVAL"`
+	TrendType    null.String        `json:"trendType,omitempty" envconfig:"TREND_TYPE"`
+
+	// SampleRates holds the sample rate for each metric name, metrics not in it aren't sampled.
+	SampleRates map[string]float64 `json:"sampleRates,omitempty" envconfig:"SAMPLE_RATES"`
+
+	// Tags in the blacklist are never sent; if the whitelist isn't empty, only tags in it are sent.
+	TagBlacklist lib.TagSet `json:"tagBlacklist,omitempty" envconfig:"TAG_BLACKLIST"`
+	TagWhitelist lib.TagSet `json:"tagWhitelist,omitempty" envconfig:"TAG_WHITELIST"`
 }
 
 // NewConfig creates a new Config instance with default values for some fields.
@@ -42,6 +62,9 @@ func NewConfig() Config {
 		BufferSize:   null.NewInt(20, false),
 		Namespace:    null.NewString("k6.", false),
 		PushInterval: types.NewNullDuration(1*time.Second, false),
+		TrendType:    null.NewString(TrendTypeTiming, false),
+		TagBlacklist: lib.GetTagSet(),
+		TagWhitelist: lib.GetTagSet(),
 	}
 }
 
@@ -63,5 +86,51 @@ func (c Config) Apply(cfg Config) Config {
 		c.PushInterval = cfg.PushInterval
 	}
 
+	if cfg.TrendType.Valid {
+		c.TrendType = cfg.TrendType
+	}
+
+	if cfg.SampleRates != nil {
+		c.SampleRates = cfg.SampleRates
+	}
+
+	if cfg.TagBlacklist != nil {
+		c.TagBlacklist = cfg.TagBlacklist
+	}
+
+	if cfg.TagWhitelist != nil {
+		c.TagWhitelist = cfg.TagWhitelist
+	}
+
 	return c
 }
+
+// Validate checks the trend type and sample rates.
+func (c Config) Validate() error {
+	switch c.TrendType.String {
+	case "", TrendTypeTiming, TrendTypeHistogram, TrendTypeDistribution:
+	default:
+		return fmt.Errorf("invalid trend type '%s', it should be one of '%s', '%s' or '%s'",
+			c.TrendType.String, TrendTypeTiming, TrendTypeHistogram, TrendTypeDistribution)
+	}
+	for name, rate := range c.SampleRates {
+		if rate <= 0 || rate > 1 {
+			return fmt.Errorf("invalid sample rate %g for metric '%s', it should be in the (0, 1] range", rate, name)
+		}
+	}
+	return nil
+}
+
+// ProcessTags returns the DogStatsD representation of the tags allowed by the tag whitelist and
+// blacklist. Tags with empty values are never sent.
+func (c Config) ProcessTags(tags map[string]string) []string {
+	var res []string
+
+	for key, value := range tags {
+		if value == "" || c.TagBlacklist[key] || (len(c.TagWhitelist) > 0 && !c.TagWhitelist[key]) {
+			continue
+		}
+		res = append(res, key+":"+value)
+	}
+	return res
+}
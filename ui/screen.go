@@ -0,0 +1,78 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package ui
+
+import (
+	"bytes"
+	"io"
+	"strings"
+)
+
+// Screen is a full-terminal view that's redrawn in place, used by the --watch modes of the
+// commands that talk to the API server. The terminal is expected to be in raw mode, so that
+// keys can be read as they're pressed, which is why lines are ended with "\r\n".
+type Screen struct {
+	W io.Writer
+}
+
+// Open switches to the terminal's alternate screen, so the previous output is restored when
+// the screen is closed, and hides the cursor.
+func (s Screen) Open() error {
+	_, err := io.WriteString(s.W, "\x1b[?1049h\x1b[?25l")
+	return err
+}
+
+// Close shows the cursor and switches back to the main screen.
+func (s Screen) Close() error {
+	_, err := io.WriteString(s.W, "\x1b[?25h\x1b[?1049l")
+	return err
+}
+
+// Draw replaces the contents of the screen with the text. Lines are overwritten instead of
+// clearing the whole screen first, to avoid flickering.
+func (s Screen) Draw(text string) error {
+	var buf bytes.Buffer
+	buf.WriteString("\x1b[H")
+	for _, line := range strings.Split(strings.TrimSuffix(text, "\n"), "\n") {
+		buf.WriteString(line)
+		buf.WriteString("\x1b[K\r\n")
+	}
+	buf.WriteString("\x1b[J")
+	_, err := s.W.Write(buf.Bytes())
+	return err
+}
+
+// ReadKeys reads r one byte at a time and sends them to the returned channel, which is closed
+// when r returns an error, e.g. at EOF.
+func ReadKeys(r io.Reader) <-chan byte {
+	keys := make(chan byte)
+	go func() {
+		defer close(keys)
+		b := make([]byte, 1)
+		for {
+			if _, err := r.Read(b); err != nil {
+				return
+			}
+			keys <- b[0]
+		}
+	}()
+	return keys
+}
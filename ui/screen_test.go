@@ -0,0 +1,44 @@
+/*
+ *
+ * k6 - a next-generation load testing tool
+ * Copyright (C) 2019 Load Impact
+ *
+ * This program is free software: you can redistribute it and/or modify
+ * it under the terms of the GNU Affero General Public License as
+ * published by the Free Software Foundation, either version 3 of the
+ * License, or (at your option) any later version.
+ *
+ * This program is distributed in the hope that it will be useful,
+ * but WITHOUT ANY WARRANTY; without even the implied warranty of
+ * MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+ * GNU Affero General Public License for more details.
+ *
+ * You should have received a copy of the GNU Affero General Public License
+ * along with this program.  If not, see <http://www.gnu.org/licenses/>.
+ *
+ */
+
+package ui
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestScreenDraw(t *testing.T) {
+	var buf bytes.Buffer
+	s := Screen{W: &buf}
+	assert.NoError(t, s.Draw("status\n  vus: 10\n"))
+	assert.Equal(t, "\x1b[Hstatus\x1b[K\r\n  vus: 10\x1b[K\r\n\x1b[J", buf.String())
+}
+
+func TestReadKeys(t *testing.T) {
+	var keys []byte
+	for key := range ReadKeys(strings.NewReader("p+q")) {
+		keys = append(keys, key)
+	}
+	assert.Equal(t, []byte("p+q"), keys)
+}
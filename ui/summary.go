@@ -37,6 +37,7 @@ import (
 const (
 	GroupPrefix   = "█"
 	DetailsPrefix = "↳"
+	EventPrefix   = "◆"
 
 	SuccMark = "✓"
 	FailMark = "✗"
@@ -163,6 +164,10 @@ type SummaryData struct {
 	Root    *lib.Group
 	Metrics map[string]*stats.Metric
 	Time    time.Duration
+	Events  []stats.Event
+
+	// The number of iterations aborted by each error, the most frequent first.
+	IterationErrors []lib.IterationErrorCount
 }
 
 func SummarizeCheck(w io.Writer, indent string, check *lib.Check) {
@@ -208,6 +213,37 @@ func SummarizeGroup(w io.Writer, indent string, group *lib.Group) {
 	}
 }
 
+// SummarizeEvents lists the events recorded during the test, in the order they happened.
+func SummarizeEvents(w io.Writer, indent string, events []stats.Event) {
+	if len(events) == 0 {
+		return
+	}
+
+	sorted := make([]stats.Event, len(events))
+	copy(sorted, events)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Time.Before(sorted[j].Time) })
+
+	for _, event := range sorted {
+		tags := event.Tags.CloneTags()
+		keys := make([]string, 0, len(tags))
+		for k := range tags {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		pairs := make([]string, len(keys))
+		for i, k := range keys {
+			pairs[i] = k + "=" + tags[k]
+		}
+
+		_, _ = fmt.Fprintf(w, "%s%s %s %s", indent, EventPrefix, event.Time.Format("15:04:05"), event.Message)
+		if len(pairs) > 0 {
+			_, _ = ExtraColor.Fprintf(w, " {%s}", strings.Join(pairs, ", "))
+		}
+		_, _ = fmt.Fprintf(w, "\n")
+	}
+	_, _ = fmt.Fprintf(w, "\n")
+}
+
 func NonTrendMetricValueForSum(t time.Duration, timeUnit string, m *stats.Metric) (data string, extra []string) {
 	switch sink := m.Sink.(type) {
 	case *stats.CounterSink:
@@ -350,10 +386,33 @@ func SummarizeMetrics(w io.Writer, indent string, t time.Duration, timeUnit stri
 	}
 }
 
+// SummarizeIterationErrors lists the errors that aborted iterations, with how many they aborted.
+func SummarizeIterationErrors(w io.Writer, indent string, errs []lib.IterationErrorCount) {
+	if len(errs) == 0 {
+		return
+	}
+
+	for _, e := range errs {
+		msg := e.Type
+		if e.Message != "" {
+			msg += ": " + e.Message
+		}
+		iterations := "iterations"
+		if e.Count == 1 {
+			iterations = "iteration"
+		}
+		_, _ = FailColor.Fprintf(w, "%s%s %s\n", indent, FailMark, msg)
+		_, _ = FailColor.Fprintf(w, "%s %s  %d %s\n", indent, DetailsPrefix, e.Count, iterations)
+	}
+	_, _ = fmt.Fprintf(w, "\n")
+}
+
 // Summarizes a dataset and returns whether the test run was considered a success.
 func Summarize(w io.Writer, indent string, data SummaryData) {
 	if data.Root != nil {
 		SummarizeGroup(w, indent+"    ", data.Root)
 	}
+	SummarizeEvents(w, indent+"    ", data.Events)
+	SummarizeIterationErrors(w, indent+"    ", data.IterationErrors)
 	SummarizeMetrics(w, indent+"  ", data.Time, data.Opts.SummaryTimeUnit.String, data.Metrics)
 }
@@ -21,8 +21,11 @@
 package ui
 
 import (
+	"bytes"
 	"testing"
+	"time"
 
+	"github.com/loadimpact/k6/lib"
 	"github.com/loadimpact/k6/stats"
 	"github.com/stretchr/testify/assert"
 )
@@ -158,3 +161,43 @@ func TestGeneratePercentileTrendColumn(t *testing.T) {
 		assert.Exactly(t, err, ErrPercentileStatInvalidValue)
 	})
 }
+
+func TestSummarizeEvents(t *testing.T) {
+	t.Run("none", func(t *testing.T) {
+		var buf bytes.Buffer
+		SummarizeEvents(&buf, "  ", nil)
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("sorted", func(t *testing.T) {
+		start := time.Date(2019, 2, 1, 10, 30, 0, 0, time.Local)
+		var buf bytes.Buffer
+		SummarizeEvents(&buf, "  ", []stats.Event{
+			{Time: start.Add(time.Minute), Message: "rolled back"},
+			{Time: start, Message: "deployed v2", Tags: stats.IntoSampleTags(&map[string]string{"b": "2", "a": "1"})},
+		})
+		assert.Equal(t, "  ◆ 10:30:00 deployed v2 {a=1, b=2}\n  ◆ 10:31:00 rolled back\n\n", buf.String())
+	})
+}
+
+func TestSummarizeIterationErrors(t *testing.T) {
+	t.Run("none", func(t *testing.T) {
+		var buf bytes.Buffer
+		SummarizeIterationErrors(&buf, "  ", nil)
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("errors", func(t *testing.T) {
+		var buf bytes.Buffer
+		SummarizeIterationErrors(&buf, "  ", []lib.IterationErrorCount{
+			{Type: "TypeError", Message: "Cannot read property 'x' of undefined", Count: 1234},
+			{Type: "GoError", Count: 1},
+		})
+		assert.Equal(t, ""+
+			"  ✗ TypeError: Cannot read property 'x' of undefined\n"+
+			"   ↳  1234 iterations\n"+
+			"  ✗ GoError\n"+
+			"   ↳  1 iteration\n\n",
+			buf.String())
+	})
+}